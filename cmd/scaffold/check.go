@@ -0,0 +1,315 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var snakeFileName = regexp.MustCompile(`^[a-z0-9]+(_[a-z0-9]+)*\.go$`)
+
+// violation of the project layout conventions.
+type violation struct {
+	path    string
+	message string
+}
+
+func (v violation) String() string {
+	return fmt.Sprintf("%s: %s", v.path, v.message)
+}
+
+type useCaseKind uint8
+
+const (
+	commandKind useCaseKind = iota
+	queryKind
+)
+
+// check lints the tree for violations of the conventions described in
+// the doc.go files of internal/api and internal/ui packages.
+func check(root string) ([]violation, error) {
+	var res []violation
+
+	checks := []struct {
+		dir string
+		fn  func(dir string, files []string) ([]violation, error)
+	}{
+		{dir: "internal/api/command", fn: checkUseCases(commandKind)},
+		{dir: "internal/api/query", fn: checkUseCases(queryKind)},
+		{dir: "internal/ui/http", fn: checkSuffixes("_handler")},
+		{dir: "internal/ui/events", fn: checkSuffixes("_handler")},
+		{dir: "internal/ui/grpc", fn: checkServers},
+	}
+
+	for _, c := range checks {
+		dir := filepath.Join(root, c.dir)
+
+		files, flat, err := listGoFiles(dir)
+		if err != nil {
+			return nil, err
+		}
+
+		if !flat {
+			res = append(res, violation{path: c.dir, message: "must be a simple file list without sub packages"})
+		}
+
+		vs, err := c.fn(dir, files)
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, vs...)
+	}
+
+	for i := range res {
+		if rel, err := filepath.Rel(root, res[i].path); err == nil && !strings.HasPrefix(rel, "..") {
+			res[i].path = rel
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].path < res[j].path })
+
+	return res, nil
+}
+
+// listGoFiles returns go files of the package without doc.go and tests.
+func listGoFiles(dir string) ([]string, bool, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, true, nil
+	}
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	var (
+		files []string
+		flat  = true
+	)
+
+	for _, e := range entries {
+		name := e.Name()
+
+		switch {
+		case e.IsDir():
+			flat = false
+		case !strings.HasSuffix(name, ".go"), name == "doc.go", strings.HasSuffix(name, "_test.go"):
+			continue
+		default:
+			files = append(files, filepath.Join(dir, name))
+		}
+	}
+
+	return files, flat, nil
+}
+
+func checkSuffixes(suffixes ...string) func(string, []string) ([]violation, error) {
+	return func(_ string, files []string) ([]violation, error) {
+		var res []violation
+
+		for _, path := range files {
+			name := filepath.Base(path)
+
+			if !snakeFileName.MatchString(name) {
+				res = append(res, violation{path: path, message: "file name must be in snake case"})
+
+				continue
+			}
+
+			if !hasSuffix(strings.TrimSuffix(name, ".go"), suffixes) {
+				res = append(res, violation{
+					path:    path,
+					message: fmt.Sprintf("file name must end with %s", strings.Join(suffixes, " or ")),
+				})
+			}
+		}
+
+		return res, nil
+	}
+}
+
+func checkServers(dir string, files []string) ([]violation, error) {
+	res, _ := checkSuffixes("_server", "_converter")(dir, files)
+
+	for _, path := range files {
+		base := strings.TrimSuffix(filepath.Base(path), ".go")
+
+		if !strings.HasSuffix(base, "_converter") {
+			continue
+		}
+
+		server := strings.TrimSuffix(base, "_converter") + "_server.go"
+
+		if _, err := os.Stat(filepath.Join(dir, server)); err != nil {
+			res = append(res, violation{path: path, message: fmt.Sprintf("converter without server %s", server)})
+		}
+	}
+
+	return res, nil
+}
+
+func hasSuffix(name string, suffixes []string) bool {
+	for _, s := range suffixes {
+		if strings.HasSuffix(name, s) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func checkUseCases(kind useCaseKind) func(string, []string) ([]violation, error) {
+	return func(_ string, files []string) ([]violation, error) {
+		var res []violation
+
+		for _, path := range files {
+			name := filepath.Base(path)
+
+			if !snakeFileName.MatchString(name) {
+				res = append(res, violation{path: path, message: "file name must be in snake case"})
+
+				continue
+			}
+
+			vs, err := checkUseCase(path, toCamel(strings.TrimSuffix(name, ".go")), kind)
+			if err != nil {
+				return nil, err
+			}
+
+			res = append(res, vs...)
+		}
+
+		return res, nil
+	}
+}
+
+//revive:disable:cognitive-complexity
+func checkUseCase(path, name string, kind useCaseKind) ([]violation, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), path, nil, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		res      []violation
+		hasType  = findStruct(file, name) != nil
+		hasParam = findStruct(file, name+"Parameters") != nil
+		do       *ast.FuncDecl
+	)
+
+	if !hasType {
+		res = append(res, violation{path: path, message: fmt.Sprintf("struct %s must be declared", name)})
+	}
+
+	if !hasParam {
+		res = append(res, violation{path: path, message: fmt.Sprintf("struct %sParameters must be declared", name)})
+	}
+
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if ok && fn.Name.Name == "Do" && fn.Recv != nil && receiverName(fn) == name {
+			do = fn
+		}
+	}
+
+	if do == nil {
+		if hasType {
+			res = append(res, violation{path: path, message: fmt.Sprintf("method %s.Do must be declared", name)})
+		}
+
+		return res, nil
+	}
+
+	if !validDoSignature(do, name, kind) {
+		expected := "error"
+		if kind == queryKind {
+			expected = "(<result>, error)"
+		}
+
+		res = append(res, violation{
+			path: path,
+			message: fmt.Sprintf(
+				"method %s.Do must have signature Do(ctx context.Context, params %sParameters) %s",
+				name, name, expected,
+			),
+		})
+	}
+
+	return res, nil
+}
+
+//revive:enable:cognitive-complexity
+
+func receiverName(fn *ast.FuncDecl) string {
+	if len(fn.Recv.List) == 0 {
+		return ""
+	}
+
+	expr := fn.Recv.List[0].Type
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+
+	if ident, ok := expr.(*ast.Ident); ok {
+		return ident.Name
+	}
+
+	return ""
+}
+
+func validDoSignature(fn *ast.FuncDecl, name string, kind useCaseKind) bool {
+	params := flatten(fn.Type.Params)
+	if len(params) != 2 || exprString(params[0]) != "context.Context" || exprString(params[1]) != name+"Parameters" {
+		return false
+	}
+
+	results := flatten(fn.Type.Results)
+
+	switch kind {
+	case commandKind:
+		return len(results) == 1 && exprString(results[0]) == "error"
+	case queryKind:
+		return len(results) == 2 && exprString(results[1]) == "error"
+	default:
+		return false
+	}
+}
+
+// flatten returns type of each parameter, "a, b int" is returned as two types.
+func flatten(list *ast.FieldList) []ast.Expr {
+	if list == nil {
+		return nil
+	}
+
+	var res []ast.Expr
+
+	for _, f := range list.List {
+		n := max(len(f.Names), 1)
+
+		for i := 0; i < n; i++ {
+			res = append(res, f.Type)
+		}
+	}
+
+	return res
+}
+
+func exprString(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return e.Name
+	case *ast.SelectorExpr:
+		return exprString(e.X) + "." + e.Sel.Name
+	case *ast.StarExpr:
+		return "*" + exprString(e.X)
+	default:
+		return ""
+	}
+}
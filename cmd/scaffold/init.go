@@ -0,0 +1,335 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// templateName is the name of the service inside the template.
+const templateName = "boilerplate"
+
+var (
+	serviceName   = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+	markerLine    = regexp.MustCompile(`^\s*(//|#)\s*scaffold:([a-z]+):(begin|end)\s*$`)
+	extraNewLine  = regexp.MustCompile(`\n{3,}`)
+	goPackage     = regexp.MustCompile(`(?m)^(\s*default:\s*).*$`)
+	versionSuffix = regexp.MustCompile(`^v[0-9]+$`)
+)
+
+// components that can be removed from the project. Each component is
+// described by the directories that belong only to it. Code of the component
+// inside shared files is marked by "scaffold:<component>:begin" and
+// "scaffold:<component>:end" comments.
+var components = map[string][]string{
+	"grpc": {"api/grpc", "internal/ui/grpc"},
+	"amqp": {"internal/ui/events"},
+	"http": {"internal/ui/http"},
+}
+
+type initOptions struct {
+	root    string
+	name    string
+	without []string
+}
+
+func runInit(args []string) error {
+	var (
+		opts    initOptions
+		without string
+	)
+
+	fs := flag.NewFlagSet("init", flag.ExitOnError)
+	fs.StringVar(&opts.root, "root", ".", "root directory of the project")
+	fs.StringVar(&opts.name, "name", "", "name of the service, for example order-service")
+	fs.StringVar(&without, "without", "", "comma separated list of removed components (grpc, amqp, http)")
+
+	_ = fs.Parse(args)
+
+	if without != "" {
+		opts.without = strings.Split(without, ",")
+	}
+
+	return initProject(opts)
+}
+
+// initProject renames the service and removes unused components. Should be
+// called once right after the project is created by gonew.
+func initProject(opts initOptions) error {
+	if !serviceName.MatchString(opts.name) {
+		return errors.New("-name must contain only lower case letters, digits and dashes")
+	}
+
+	removed := make(map[string]bool, len(opts.without))
+
+	for _, c := range opts.without {
+		c = strings.TrimSpace(c)
+
+		if _, ok := components[c]; !ok {
+			return fmt.Errorf("unknown component %q", c)
+		}
+
+		removed[c] = true
+	}
+
+	module, err := modulePath(opts.root)
+	if err != nil {
+		return err
+	}
+
+	for c := range removed {
+		for _, dir := range components[c] {
+			if err = os.RemoveAll(filepath.Join(opts.root, dir)); err != nil {
+				return err
+			}
+		}
+	}
+
+	if !removed["grpc"] {
+		if err = renameProto(opts.root, opts.name, module); err != nil {
+			return err
+		}
+	}
+
+	if err = renameService(opts.root, opts.name); err != nil {
+		return err
+	}
+
+	return processMarkers(opts.root, removed)
+}
+
+func renameProto(root, name, module string) error {
+	var (
+		pkg    = strings.ReplaceAll(name, "-", "_")
+		oldDir = filepath.Join(root, "api/grpc/proto", templateName)
+		newDir = filepath.Join(root, "api/grpc/proto", pkg)
+	)
+
+	err := replaceInFile(filepath.Join(oldDir, "service.proto"), func(src string) string {
+		return strings.ReplaceAll(src, "package "+templateName+";", "package "+pkg+";")
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = os.Rename(oldDir, newDir); err != nil {
+		return err
+	}
+
+	return replaceInFile(filepath.Join(root, "api/grpc/buf.gen.yaml"), func(src string) string {
+		return goPackage.ReplaceAllString(src, "${1}"+module+"/api/grpc/go")
+	})
+}
+
+func renameService(root, name string) error {
+	files := map[string]func(string) string{
+		"deployments/docker-compose.yaml": replaceName(name),
+		"Makefile":                        replaceName(name),
+		".env.example":                    replaceName(name),
+		"Dockerfile": func(src string) string {
+			return strings.ReplaceAll(src, "service-entrypoint", name)
+		},
+	}
+
+	for path, fn := range files {
+		if err := replaceInFile(filepath.Join(root, path), fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func replaceName(name string) func(string) string {
+	return func(src string) string {
+		return strings.ReplaceAll(src, templateName, name)
+	}
+}
+
+func replaceInFile(path string, fn func(string) string) error {
+	src, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, []byte(fn(string(src))), 0o644) //nolint:gosec
+}
+
+// processMarkers removes the code of the removed components and all the
+// scaffold markers from the project files.
+func processMarkers(root string, removed map[string]bool) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			switch d.Name() {
+			// sources of the scaffold itself contain the markers as examples.
+			case ".git", "vendor", "node_modules", "scaffold":
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		if !bytes.Contains(src, []byte("scaffold:")) {
+			return nil
+		}
+
+		res, changed, err := stripMarkers(string(src), removed)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+
+		if !changed {
+			return nil
+		}
+
+		if strings.HasSuffix(path, ".go") {
+			out, err := cleanGoSource([]byte(res))
+			if err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+
+			res = string(out)
+		}
+
+		return os.WriteFile(path, []byte(res), 0o644) //nolint:gosec
+	})
+}
+
+// stripMarkers removes lines between markers of removed components and marker
+// lines of kept components.
+func stripMarkers(src string, removed map[string]bool) (string, bool, error) {
+	var (
+		lines   = strings.Split(src, "\n")
+		res     = make([]string, 0, len(lines))
+		open    string
+		changed bool
+	)
+
+	for i, line := range lines {
+		m := markerLine.FindStringSubmatch(line)
+		if m == nil {
+			if open == "" || !removed[open] {
+				res = append(res, line)
+			}
+
+			continue
+		}
+
+		changed = true
+
+		switch component, kind := m[2], m[3]; {
+		case kind == "begin" && open != "":
+			return "", false, fmt.Errorf("line %d: nested marker of %s inside %s", i+1, component, open)
+		case kind == "begin":
+			open = component
+		case component != open:
+			return "", false, fmt.Errorf("line %d: unexpected end marker of %s", i+1, component)
+		default:
+			open = ""
+		}
+	}
+
+	if open != "" {
+		return "", false, fmt.Errorf("marker of %s is not closed", open)
+	}
+
+	out := extraNewLine.ReplaceAllString(strings.Join(res, "\n"), "\n\n")
+	out = strings.TrimRight(out, "\n") + "\n"
+
+	return out, changed, nil
+}
+
+// cleanGoSource removes imports that are not used anymore and formats the source.
+func cleanGoSource(src []byte) ([]byte, error) {
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	used := make(map[string]bool)
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if ident, ok := sel.X.(*ast.Ident); ok {
+				used[ident.Name] = true
+			}
+		}
+
+		return true
+	})
+
+	var unused []*ast.ImportSpec
+
+	for _, imp := range file.Imports {
+		name, ok := importName(imp)
+		if ok && !used[name] {
+			unused = append(unused, imp)
+		}
+	}
+
+	// remove from the end, so the offsets of the previous imports stay valid.
+	for i := len(unused) - 1; i >= 0; i-- {
+		var (
+			start = fset.Position(unused[i].Pos()).Offset
+			end   = fset.Position(unused[i].End()).Offset
+		)
+
+		// remove the whole line of the import.
+		lineStart := bytes.LastIndexByte(src[:start], '\n') + 1
+		if len(bytes.TrimSpace(src[lineStart:start])) == 0 {
+			start = lineStart
+		}
+
+		if next := bytes.IndexByte(src[end:], '\n'); next >= 0 && len(bytes.TrimSpace(src[end:end+next])) == 0 {
+			end += next + 1
+		}
+
+		src = append(src[:start], src[end:]...)
+	}
+
+	return format.Source(src)
+}
+
+// importName returns the name of the imported package. False is returned if
+// the name can not be found out by the import path.
+func importName(imp *ast.ImportSpec) (string, bool) {
+	if imp.Name != nil {
+		return imp.Name.Name, imp.Name.Name != "_" && imp.Name.Name != "."
+	}
+
+	path, _ := strconv.Unquote(imp.Path.Value)
+	parts := strings.Split(path, "/")
+
+	name := parts[len(parts)-1]
+	if versionSuffix.MatchString(name) && len(parts) > 1 {
+		name = parts[len(parts)-2]
+	}
+
+	return name, !strings.ContainsAny(name, "-.")
+}
@@ -0,0 +1,246 @@
+// Command scaffold generates commands, queries, http handlers and gRPC servers
+// by the conventions of the template and checks that the existing tree follows
+// these conventions. Also, it initializes the new project created from the
+// template: renames the service and removes unused components.
+//
+// Usage:
+//
+//	go run ./cmd/scaffold command -name CreateOrder -field "ClientID string required,uuid" -field "Status string 'oneof=new paid'"
+//	go run ./cmd/scaffold query -name AvailableServices -field "Limit int min=1,max=100"
+//	go run ./cmd/scaffold http -name Order
+//	go run ./cmd/scaffold grpc -name Order
+//	go run ./cmd/scaffold check
+//	go run ./cmd/scaffold init -name order-service -without amqp,grpc
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+type fieldsFlag []string
+
+func (f *fieldsFlag) String() string {
+	return strings.Join(*f, "; ")
+}
+
+func (f *fieldsFlag) Set(value string) error {
+	*f = append(*f, value)
+
+	return nil
+}
+
+type options struct {
+	root   string
+	name   string
+	fields fieldsFlag
+	noTest bool
+	noWire bool
+	force  bool
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	var err error
+
+	switch cmd := os.Args[1]; cmd {
+	case "command", "query", "http", "grpc":
+		err = generate(cmd, os.Args[2:])
+	case "check":
+		err = runCheck(os.Args[2:])
+	case "init":
+		err = runInit(os.Args[2:])
+	default:
+		usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: scaffold <command|query|http|grpc|check|init> [flags]")
+}
+
+func generate(kind string, args []string) error {
+	var opts options
+
+	fs := flag.NewFlagSet(kind, flag.ExitOnError)
+	fs.StringVar(&opts.root, "root", ".", "root directory of the project")
+	fs.StringVar(&opts.name, "name", "", "name of the generated entity, for example CreateOrder")
+	fs.Var(&opts.fields, "field", "parameter field in format \"Name type [validate rules]\", can be repeated")
+	fs.BoolVar(&opts.noTest, "no-test", false, "do not generate test skeletons")
+	fs.BoolVar(&opts.noWire, "no-wire", false, "do not wire command or query into the container")
+	fs.BoolVar(&opts.force, "force", false, "overwrite existing files")
+
+	_ = fs.Parse(args)
+
+	if opts.name == "" {
+		return errors.New("-name is required")
+	}
+
+	module, err := modulePath(opts.root)
+	if err != nil {
+		return err
+	}
+
+	data := templateData{
+		Module: module,
+		Name:   toCamel(opts.name),
+	}
+
+	for _, def := range opts.fields {
+		f, err := parseField(def)
+		if err != nil {
+			return err
+		}
+
+		data.Fields = append(data.Fields, f)
+	}
+
+	switch kind {
+	case "command":
+		data.Receiver = "c"
+
+		return generateUseCase(opts, data, "command", commandTemplate, commandTestTemplate)
+	case "query":
+		data.Receiver = "q"
+
+		return generateUseCase(opts, data, "query", queryTemplate, queryTestTemplate)
+	case "http":
+		return generateFiles(opts, data, "internal/ui/http", map[string]string{
+			"_handler.go":      handlerTemplate,
+			"_handler_test.go": handlerTestTemplate,
+		})
+	default:
+		return generateFiles(opts, data, "internal/ui/grpc", map[string]string{
+			"_server.go":      serverTemplate,
+			"_converter.go":   converterTemplate,
+			"_server_test.go": serverTestTemplate,
+		})
+	}
+}
+
+func generateUseCase(opts options, data templateData, pkg, tmpl, testTmpl string) error {
+	dir := "internal/api/" + pkg
+
+	err := generateFiles(opts, data, dir, map[string]string{
+		".go":      tmpl,
+		"_test.go": testTmpl,
+	})
+	if err != nil || opts.noWire {
+		return err
+	}
+
+	err = wireApp(
+		filepath.Join(opts.root, "internal/container/container.go"),
+		filepath.Join(opts.root, "internal/container/dependencies.go"),
+		data.Module+"/"+dir,
+		pkg,
+		data.Name,
+	)
+	if err != nil {
+		return fmt.Errorf("can not wire %s into container, %w", data.Name, err)
+	}
+
+	fmt.Printf("wired %s.%s into container\n", pkg, data.Name)
+
+	return nil
+}
+
+// generateFiles renders templates to the dir. Keys of the templates are the
+// suffixes of file names.
+func generateFiles(opts options, data templateData, dir string, templates map[string]string) error {
+	base := toSnake(data.Name)
+
+	suffixes := make([]string, 0, len(templates))
+	for suffix := range templates {
+		suffixes = append(suffixes, suffix)
+	}
+
+	sort.Strings(suffixes)
+
+	for _, suffix := range suffixes {
+		tmpl := templates[suffix]
+
+		if opts.noTest && strings.HasSuffix(suffix, "_test.go") {
+			continue
+		}
+
+		path := filepath.Join(opts.root, dir, base+suffix)
+
+		if _, err := os.Stat(path); err == nil && !opts.force {
+			return fmt.Errorf("file %s already exists, use -force to overwrite", path)
+		}
+
+		src, err := render(suffix, tmpl, data)
+		if err != nil {
+			return fmt.Errorf("can not render %s, %w", path, err)
+		}
+
+		// generated sources are ordinary files of the repository.
+		if err = os.WriteFile(path, src, 0o644); err != nil { //nolint:gosec
+			return err
+		}
+
+		fmt.Printf("created %s\n", path)
+	}
+
+	return nil
+}
+
+func runCheck(args []string) error {
+	fs := flag.NewFlagSet("check", flag.ExitOnError)
+	root := fs.String("root", ".", "root directory of the project")
+
+	_ = fs.Parse(args)
+
+	violations, err := check(*root)
+	if err != nil {
+		return err
+	}
+
+	for _, v := range violations {
+		fmt.Println(v.String())
+	}
+
+	if len(violations) > 0 {
+		return fmt.Errorf("found %d violations", len(violations))
+	}
+
+	return nil
+}
+
+func modulePath(root string) (string, error) {
+	file, err := os.Open(filepath.Join(root, "go.mod"))
+	if err != nil {
+		return "", err
+	}
+
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if module, ok := strings.CutPrefix(line, "module "); ok {
+			return strings.Trim(strings.TrimSpace(module), `"`), nil
+		}
+	}
+
+	return "", fmt.Errorf("module directive not found in %s", file.Name())
+}
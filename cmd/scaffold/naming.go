@@ -0,0 +1,71 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+)
+
+// initialisms are kept upper case in go names, for example "FindRouteByID".
+var initialisms = map[string]struct{}{
+	"API":  {},
+	"HTTP": {},
+	"ID":   {},
+	"JSON": {},
+	"SQL":  {},
+	"URL":  {},
+	"UUID": {},
+}
+
+// toSnake converts go name to the file name. Example: FindRouteByID -> find_route_by_id.
+func toSnake(name string) string {
+	var (
+		runes   = []rune(name)
+		builder strings.Builder
+	)
+
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				builder.WriteByte('_')
+			}
+		}
+
+		builder.WriteRune(unicode.ToLower(r))
+	}
+
+	return builder.String()
+}
+
+// toCamel converts snake or lower camel name to the exported go name.
+// Example: find_route_by_id -> FindRouteByID, createOrder -> CreateOrder.
+func toCamel(name string) string {
+	var builder strings.Builder
+
+	for _, part := range strings.Split(toSnake(name), "_") {
+		if part == "" {
+			continue
+		}
+
+		upper := strings.ToUpper(part)
+
+		if _, ok := initialisms[upper]; ok {
+			builder.WriteString(upper)
+
+			continue
+		}
+
+		builder.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+
+	return builder.String()
+}
+
+// toLowerCamel converts name to unexported go name. Example: IDProvider -> idProvider.
+func toLowerCamel(name string) string {
+	first, rest, _ := strings.Cut(toSnake(name), "_")
+
+	return first + toCamel(rest)
+}
@@ -0,0 +1,433 @@
+package main
+
+import (
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNaming(t *testing.T) {
+	tests := []struct {
+		name  string
+		snake string
+		camel string
+		lower string
+	}{
+		{name: "CreateOrder", snake: "create_order", camel: "CreateOrder", lower: "createOrder"},
+		{name: "create_order", snake: "create_order", camel: "CreateOrder", lower: "createOrder"},
+		{name: "FindRouteByID", snake: "find_route_by_id", camel: "FindRouteByID", lower: "findRouteByID"},
+		{name: "find_route_by_id", snake: "find_route_by_id", camel: "FindRouteByID", lower: "findRouteByID"},
+		{name: "HTTPServer", snake: "http_server", camel: "HTTPServer", lower: "httpServer"},
+		{name: "order2Items", snake: "order2_items", camel: "Order2Items", lower: "order2Items"},
+	}
+
+	t.Parallel()
+
+	for _, tc := range tests {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.snake, toSnake(tc.name))
+			assert.Equal(t, tc.camel, toCamel(tc.name))
+			assert.Equal(t, tc.lower, toLowerCamel(tc.name))
+		})
+	}
+}
+
+func TestParseField(t *testing.T) {
+	tests := []struct {
+		name     string
+		def      string
+		expected field
+		err      bool
+	}{
+		{
+			name:     "should parse field without rules",
+			def:      "comment string",
+			expected: field{Name: "Comment", Type: "string", JSON: "comment"},
+		},
+		{
+			name:     "should parse field with rules",
+			def:      "ClientID string required,uuid",
+			expected: field{Name: "ClientID", Type: "string", JSON: "client_id", Rules: "required,uuid"},
+		},
+		{
+			name:     "should parse quoted rules with spaces",
+			def:      `Status  string  'required,oneof=new paid'`,
+			expected: field{Name: "Status", Type: "string", JSON: "status", Rules: "required,oneof=new paid"},
+		},
+		{
+			name:     "should parse double quoted rules",
+			def:      `Status string "oneof=new paid"`,
+			expected: field{Name: "Status", Type: "string", JSON: "status", Rules: "oneof=new paid"},
+		},
+		{
+			name: "should return error if type is missing",
+			def:  "Status",
+			err:  true,
+		},
+		{
+			name: "should return error if rules with spaces are not quoted",
+			def:  "Status string oneof=new paid",
+			err:  true,
+		},
+		{
+			name: "should return error if quote is not closed",
+			def:  "Status string 'oneof=new paid",
+			err:  true,
+		},
+	}
+
+	t.Parallel()
+
+	for _, tc := range tests {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			f, err := parseField(tc.def)
+			if tc.err {
+				assert.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, f)
+		})
+	}
+}
+
+func TestWireApp_Should_add_field_initialization_and_import_once(t *testing.T) {
+	dir := t.TempDir()
+
+	container := filepath.Join(dir, "container.go")
+	dependencies := filepath.Join(dir, "dependencies.go")
+
+	writeFile(t, container, `package container
+
+import (
+	"fmt"
+)
+
+type Apps struct {
+	// Application layer.
+}
+
+var _ = fmt.Sprint
+`)
+	writeFile(t, dependencies, `package container
+
+func makeApps() *Apps {
+	return &Apps{}
+}
+`)
+
+	for i := 0; i < 2; i++ {
+		err := wireApp(container, dependencies, "example.com/app/internal/api/command", "command", "CreateOrder")
+		require.NoError(t, err)
+	}
+
+	err := wireApp(container, dependencies, "example.com/app/internal/api/query", "query", "FindRouteByID")
+	require.NoError(t, err)
+
+	assert.Equal(t, `package container
+
+import (
+	"example.com/app/internal/api/command"
+	"example.com/app/internal/api/query"
+	"fmt"
+)
+
+type Apps struct {
+	// Application layer.
+	CreateOrder   *command.CreateOrder
+	FindRouteByID *query.FindRouteByID
+}
+
+var _ = fmt.Sprint
+`, readFile(t, container))
+
+	assert.Equal(t, `package container
+
+import (
+	"example.com/app/internal/api/command"
+	"example.com/app/internal/api/query"
+)
+
+func makeApps() *Apps {
+	return &Apps{
+		CreateOrder:   command.NewCreateOrder(),
+		FindRouteByID: query.NewFindRouteByID(),
+	}
+}
+`, readFile(t, dependencies))
+}
+
+func TestCheck_Should_find_violations_of_conventions(t *testing.T) {
+	root := t.TempDir()
+
+	writeFile(t, filepath.Join(root, "internal/api/command/create_order.go"), `package command
+
+import "context"
+
+type CreateOrder struct{}
+
+type CreateOrderParameters struct{}
+
+func (c *CreateOrder) Do(ctx context.Context, params CreateOrderParameters) error {
+	return nil
+}
+`)
+	writeFile(t, filepath.Join(root, "internal/api/command/make_invoice.go"), `package command
+
+type MakeInvoice struct{}
+
+func (c *MakeInvoice) Do() {}
+`)
+	writeFile(t, filepath.Join(root, "internal/api/query/availableServices.go"), "package query\n")
+	writeFile(t, filepath.Join(root, "internal/api/query/doc.go"), "package query\n")
+	writeFile(t, filepath.Join(root, "internal/ui/http/order_handler.go"), "package http\n")
+	writeFile(t, filepath.Join(root, "internal/ui/http/router.go"), "package http\n")
+	writeFile(t, filepath.Join(root, "internal/ui/grpc/user_converter.go"), "package grpc\n")
+	writeFile(t, filepath.Join(root, "internal/ui/events/order/order_handler.go"), "package order\n")
+
+	violations, err := check(root)
+	require.NoError(t, err)
+
+	var messages []string
+
+	for _, v := range violations {
+		messages = append(messages, v.String())
+	}
+
+	assert.Equal(t, []string{
+		"internal/api/command/make_invoice.go: struct MakeInvoiceParameters must be declared",
+		"internal/api/command/make_invoice.go: method MakeInvoice.Do must have signature " +
+			"Do(ctx context.Context, params MakeInvoiceParameters) error",
+		"internal/api/query/availableServices.go: file name must be in snake case",
+		"internal/ui/events: must be a simple file list without sub packages",
+		"internal/ui/grpc/user_converter.go: converter without server user_server.go",
+		"internal/ui/http/router.go: file name must end with _handler",
+	}, messages)
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	return string(b)
+}
+
+func TestStripMarkers(t *testing.T) {
+	src := `services:
+  db:
+    image: postgres
+
+  # scaffold:amqp:begin
+  rabbitmq:
+    image: rabbitmq
+  # scaffold:amqp:end
+
+# scaffold:http:begin
+port: 4000
+# scaffold:http:end
+`
+
+	res, changed, err := stripMarkers(src, map[string]bool{"amqp": true})
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, `services:
+  db:
+    image: postgres
+
+port: 4000
+`, res)
+}
+
+func TestStripMarkers_Should_return_error_if_marker_is_not_closed(t *testing.T) {
+	_, _, err := stripMarkers("// scaffold:grpc:begin\ncode\n", nil)
+	assert.Error(t, err)
+
+	_, _, err = stripMarkers("// scaffold:grpc:begin\n// scaffold:http:end\n", nil)
+	assert.Error(t, err)
+}
+
+func TestCleanGoSource_Should_remove_unused_imports(t *testing.T) {
+	src := `package container
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/caarlos0/env/v11"
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	_ "github.com/lib/pq"
+)
+
+var (
+	_ = time.Now
+	_ = env.Parse
+)
+`
+
+	res, err := cleanGoSource([]byte(src))
+	require.NoError(t, err)
+	assert.Equal(t, `package container
+
+import (
+	"time"
+
+	"github.com/caarlos0/env/v11"
+	_ "github.com/lib/pq"
+)
+
+var (
+	_ = time.Now
+	_ = env.Parse
+)
+`, string(res))
+}
+
+func TestInitProject(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds the copy of the project")
+	}
+
+	const module = "example.com/shop/order-service"
+
+	tests := []struct {
+		name    string
+		without []string
+		removed []string
+		kept    []string
+	}{
+		{
+			name:    "should remove amqp and grpc",
+			without: []string{"amqp", "grpc"},
+			removed: []string{"api/grpc", "internal/ui/grpc", "internal/ui/events"},
+			kept:    []string{"internal/ui/http"},
+		},
+		{
+			name:    "should remove http and rename proto",
+			without: []string{"http"},
+			removed: []string{"internal/ui/http"},
+			kept:    []string{"api/grpc/proto/order_service", "internal/ui/events"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			root := copyProject(t, "../..")
+			renameModule(t, root, module)
+
+			err := initProject(initOptions{root: root, name: "order-service", without: tc.without})
+			require.NoError(t, err)
+
+			for _, dir := range tc.removed {
+				assert.NoDirExists(t, filepath.Join(root, dir))
+			}
+
+			for _, dir := range tc.kept {
+				assert.DirExists(t, filepath.Join(root, dir))
+			}
+
+			for _, path := range []string{"deployments/docker-compose.yaml", "Makefile", ".env.example"} {
+				assert.NotContains(t, readFile(t, filepath.Join(root, path)), templateName, path)
+			}
+
+			assert.Contains(t, readFile(t, filepath.Join(root, "Dockerfile")), "order-service")
+			assert.NotContains(t, readFile(t, filepath.Join(root, "internal/container/container.go")), "scaffold:")
+
+			if slices.Contains(tc.kept, "api/grpc/proto/order_service") {
+				assert.Contains(t, readFile(t, filepath.Join(root, "api/grpc/buf.gen.yaml")), module+"/api/grpc/go")
+			}
+
+			cmd := exec.Command("go", "build", "./...")
+			cmd.Dir = root
+
+			out, err := cmd.CombinedOutput()
+			require.NoError(t, err, string(out))
+		})
+	}
+}
+
+// copyProject copies the project without the git directory into the
+// temporary directory.
+func copyProject(t *testing.T, src string) string {
+	t.Helper()
+
+	dst := t.TempDir()
+
+	err := filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+
+			return os.MkdirAll(filepath.Join(dst, rel), 0o755)
+		}
+
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		return os.WriteFile(filepath.Join(dst, rel), b, 0o600)
+	})
+	require.NoError(t, err)
+
+	return dst
+}
+
+// renameModule rewrites the module path like gonew does.
+func renameModule(t *testing.T, root, module string) {
+	t.Helper()
+
+	old, err := modulePath(root)
+	require.NoError(t, err)
+
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() && d.Name() == "vendor" {
+			return filepath.SkipDir
+		}
+
+		if d.IsDir() || !strings.HasSuffix(path, ".go") && d.Name() != "go.mod" {
+			return nil
+		}
+
+		return replaceInFile(path, func(src string) string {
+			return strings.ReplaceAll(src, old, module)
+		})
+	})
+	require.NoError(t, err)
+}
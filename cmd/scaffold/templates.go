@@ -0,0 +1,258 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"strings"
+	"text/template"
+)
+
+type field struct {
+	Name  string
+	Type  string
+	JSON  string
+	Rules string
+}
+
+// parseField parses field definition in format "Name type [validate rules]".
+// Rules with spaces must be quoted by single or double quotes.
+// Example: "ClientID string required,uuid", "Status string 'oneof=new paid'".
+func parseField(def string) (field, error) {
+	parts := strings.Fields(def)
+	if len(parts) < 2 {
+		return field{}, fmt.Errorf("invalid field %q, expected \"Name type [rules]\"", def)
+	}
+
+	f := field{
+		Name: toCamel(parts[0]),
+		Type: parts[1],
+		JSON: toSnake(toCamel(parts[0])),
+	}
+
+	if len(parts) == 2 {
+		return f, nil
+	}
+
+	// the rest of the definition after the name and the type.
+	rest := strings.TrimSpace(def)
+	for _, p := range parts[:2] {
+		rest = strings.TrimSpace(strings.TrimPrefix(rest, p))
+	}
+
+	rules, err := unquoteRules(rest)
+	if err != nil {
+		return field{}, fmt.Errorf("invalid field %q, %w", def, err)
+	}
+
+	f.Rules = rules
+
+	return f, nil
+}
+
+func unquoteRules(rules string) (string, error) {
+	if q := rules[0]; q == '\'' || q == '"' {
+		if len(rules) < 2 || rules[len(rules)-1] != q {
+			return "", fmt.Errorf("rules are not closed by %c", q)
+		}
+
+		return rules[1 : len(rules)-1], nil
+	}
+
+	if strings.ContainsAny(rules, " \t") {
+		return "", errors.New("rules with spaces must be quoted")
+	}
+
+	return rules, nil
+}
+
+func (f field) Tag() string {
+	if f.Rules == "" {
+		return fmt.Sprintf("`json:%q`", f.JSON)
+	}
+
+	return fmt.Sprintf("`json:%q validate:%q`", f.JSON, f.Rules)
+}
+
+type templateData struct {
+	Module   string
+	Package  string
+	Name     string
+	Receiver string
+	Fields   []field
+}
+
+var commandTemplate = `package command
+
+import (
+	"context"
+)
+
+type {{.Name}} struct{}
+
+type {{.Name}}Parameters struct {
+{{- range .Fields}}
+	{{.Name}} {{.Type}} {{.Tag}}
+{{- end}}
+}
+
+func New{{.Name}}() *{{.Name}} {
+	return &{{.Name}}{}
+}
+
+func ({{.Receiver}} *{{.Name}}) Do(ctx context.Context, params {{.Name}}Parameters) error {
+	return nil
+}
+`
+
+var commandTestTemplate = `package command
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test{{.Name}}_Do_Should_complete_without_error(t *testing.T) {
+	{{.Receiver}} := New{{.Name}}()
+
+	err := {{.Receiver}}.Do(context.Background(), {{.Name}}Parameters{})
+	assert.NoError(t, err)
+}
+`
+
+var queryTemplate = `package query
+
+import (
+	"context"
+)
+
+type {{.Name}} struct{}
+
+type {{.Name}}Parameters struct {
+{{- range .Fields}}
+	{{.Name}} {{.Type}} {{.Tag}}
+{{- end}}
+}
+
+type {{.Name}}Result struct{}
+
+func New{{.Name}}() *{{.Name}} {
+	return &{{.Name}}{}
+}
+
+func ({{.Receiver}} *{{.Name}}) Do(ctx context.Context, params {{.Name}}Parameters) ({{.Name}}Result, error) {
+	return {{.Name}}Result{}, nil
+}
+`
+
+var queryTestTemplate = `package query
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test{{.Name}}_Do_Should_return_result_without_error(t *testing.T) {
+	{{.Receiver}} := New{{.Name}}()
+
+	_, err := {{.Receiver}}.Do(context.Background(), {{.Name}}Parameters{})
+	assert.NoError(t, err)
+}
+`
+
+var handlerTemplate = `package http
+
+import (
+	"net/http"
+
+	"{{.Module}}/internal/api/bus"
+)
+
+type {{.Name}}Handler struct {
+	dispatcher *bus.Dispatcher
+}
+
+func New{{.Name}}Handler(dispatcher *bus.Dispatcher) *{{.Name}}Handler {
+	return &{{.Name}}Handler{dispatcher: dispatcher}
+}
+
+// Register registers all the routes of the handler.
+func (h *{{.Name}}Handler) Register(mux *http.ServeMux) {
+}
+`
+
+var handlerTestTemplate = `package http
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"{{.Module}}/internal/api/bus"
+)
+
+func Test{{.Name}}Handler_Register_Should_register_routes(t *testing.T) {
+	mux := http.NewServeMux()
+
+	assert.NotPanics(t, func() {
+		New{{.Name}}Handler(bus.NewDispatcher()).Register(mux)
+	})
+}
+`
+
+var serverTemplate = `package grpc
+
+import (
+	"{{.Module}}/internal/api/bus"
+)
+
+type {{.Name}}Server struct {
+	dispatcher *bus.Dispatcher
+}
+
+func New{{.Name}}Server(dispatcher *bus.Dispatcher) *{{.Name}}Server {
+	return &{{.Name}}Server{dispatcher: dispatcher}
+}
+`
+
+var converterTemplate = `package grpc
+
+// File contains converters between gRPC messages of the {{.Name}}Server
+// and parameters or results of the commands and queries.
+`
+
+var serverTestTemplate = `package grpc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"{{.Module}}/internal/api/bus"
+)
+
+func TestNew{{.Name}}Server_Should_create_server(t *testing.T) {
+	assert.NotNil(t, New{{.Name}}Server(bus.NewDispatcher()))
+}
+`
+
+func render(name, text string, data templateData) ([]byte, error) {
+	tmpl, err := template.New(name).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+
+	if err = tmpl.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+
+	return format.Source(buf.Bytes())
+}
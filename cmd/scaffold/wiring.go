@@ -0,0 +1,185 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"sort"
+	"strconv"
+)
+
+// insertion is a text that will be placed to the source at the offset.
+type insertion struct {
+	offset int
+	text   string
+}
+
+// wireApp adds the command or query to the Apps struct of the container and
+// initializes it inside makeApps(). Source is edited as text by positions of
+// the AST nodes, so all comments and formatting are kept.
+//
+//revive:disable:cognitive-complexity
+func wireApp(containerPath, dependenciesPath, importPath, pkg, name string) error {
+	fieldLine := fmt.Sprintf("\t%s *%s.%s\n", name, pkg, name)
+
+	err := editFile(containerPath, func(fset *token.FileSet, file *ast.File) ([]insertion, error) {
+		st := findStruct(file, "Apps")
+		if st == nil {
+			return nil, fmt.Errorf("struct Apps not found in %s", containerPath)
+		}
+
+		for _, f := range st.Fields.List {
+			for _, n := range f.Names {
+				if n.Name == name {
+					return nil, nil
+				}
+			}
+		}
+
+		ins := []insertion{{offset: fset.Position(st.Fields.Closing).Offset, text: fieldLine}}
+
+		return append(ins, importInsertion(fset, file, importPath)...), nil
+	})
+	if err != nil {
+		return err
+	}
+
+	initLine := fmt.Sprintf("%s: %s.New%s(),\n", name, pkg, name)
+
+	return editFile(dependenciesPath, func(fset *token.FileSet, file *ast.File) ([]insertion, error) {
+		lit := findCompositeLit(file, "makeApps", "Apps")
+		if lit == nil {
+			return nil, fmt.Errorf("Apps literal inside makeApps not found in %s", dependenciesPath)
+		}
+
+		for _, elt := range lit.Elts {
+			if kv, ok := elt.(*ast.KeyValueExpr); ok {
+				if ident, ok := kv.Key.(*ast.Ident); ok && ident.Name == name {
+					return nil, nil
+				}
+			}
+		}
+
+		text := initLine
+
+		switch {
+		case len(lit.Elts) == 0:
+			text = "\n" + text
+		case fset.Position(lit.Elts[len(lit.Elts)-1].End()).Line == fset.Position(lit.Rbrace).Line:
+			// single line literal does not have a trailing comma.
+			text = ",\n" + text
+		}
+
+		ins := []insertion{{offset: fset.Position(lit.Rbrace).Offset, text: text}}
+
+		return append(ins, importInsertion(fset, file, importPath)...), nil
+	})
+}
+
+//revive:enable:cognitive-complexity
+
+func editFile(path string, edit func(*token.FileSet, *ast.File) ([]insertion, error)) error {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+	if err != nil {
+		return err
+	}
+
+	ins, err := edit(fset, file)
+	if err != nil || len(ins) == 0 {
+		return err
+	}
+
+	// insert from the end, so the offsets of the previous insertions stay valid.
+	sort.Slice(ins, func(i, j int) bool { return ins[i].offset > ins[j].offset })
+
+	for _, in := range ins {
+		src = append(src[:in.offset], append([]byte(in.text), src[in.offset:]...)...)
+	}
+
+	res, err := format.Source(src)
+	if err != nil {
+		return fmt.Errorf("can not format %s, %w", path, err)
+	}
+
+	return os.WriteFile(path, res, 0o644) //nolint:gosec
+}
+
+func importInsertion(fset *token.FileSet, file *ast.File, importPath string) []insertion {
+	for _, imp := range file.Imports {
+		if p, _ := strconv.Unquote(imp.Path.Value); p == importPath {
+			return nil
+		}
+	}
+
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT {
+			continue
+		}
+
+		if gen.Rparen.IsValid() {
+			return []insertion{{offset: fset.Position(gen.Rparen).Offset, text: fmt.Sprintf("\t%q\n", importPath)}}
+		}
+
+		// single import is converted to the import block.
+		return []insertion{
+			{offset: fset.Position(gen.Specs[0].Pos()).Offset, text: "(\n\t"},
+			{offset: fset.Position(gen.End()).Offset, text: fmt.Sprintf("\n\t%q\n)", importPath)},
+		}
+	}
+
+	return []insertion{{offset: fset.Position(file.Name.End()).Offset, text: fmt.Sprintf("\n\nimport %q\n", importPath)}}
+}
+
+func findStruct(file *ast.File, name string) *ast.StructType {
+	var res *ast.StructType
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		ts, ok := n.(*ast.TypeSpec)
+		if !ok || ts.Name.Name != name {
+			return res == nil
+		}
+
+		res, _ = ts.Type.(*ast.StructType)
+
+		return false
+	})
+
+	return res
+}
+
+func findCompositeLit(file *ast.File, funcName, typeName string) *ast.CompositeLit {
+	var res *ast.CompositeLit
+
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Name.Name != funcName || fn.Body == nil {
+			continue
+		}
+
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			lit, ok := n.(*ast.CompositeLit)
+			if !ok {
+				return res == nil
+			}
+
+			if ident, ok := lit.Type.(*ast.Ident); ok && ident.Name == typeName {
+				res = lit
+			}
+
+			return res == nil
+		})
+	}
+
+	return res
+}
@@ -2,11 +2,20 @@ package main
 
 import (
 	"log"
+	"os"
 
 	"github.com/Melenium2/go-template/internal/container"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(os.Args[2:]); err != nil {
+			log.Fatal(err.Error())
+		}
+
+		return
+	}
+
 	c := container.NewContainer()
 
 	if err := c.Run(); err != nil {
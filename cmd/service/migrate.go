@@ -0,0 +1,281 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Melenium2/go-template/internal/container"
+	"github.com/Melenium2/go-template/pkg/migration"
+)
+
+const migrateUsage = `usage: service migrate <command> [arguments]
+
+Commands:
+  up [-dry-run] [N]  apply all or N pending migrations, -dry-run prints SQL without applying,
+                     fails if applied migration files were changed
+  down [N]           rollback N applied migrations, 1 by default
+  goto V             apply or rollback migrations until version V, fails if applied
+                     migration files were changed
+  force V            set version V without running migrations, resets dirty state,
+                     -1 removes the version
+  version            print current version
+  status             print applied and pending migrations
+  verify             check that applied migration files were not changed
+  recover [S]        print dirty migration or resolve it with strategy S:
+                     force (mark as applied), previous (apply again), down (rollback)
+  create NAME        create pair of up and down migration files`
+
+// runMigrate runs migrate subcommands. Example:
+//
+//	service migrate up
+//	service migrate down 2
+//	service migrate create "add orders table"
+func runMigrate(args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	cfg := container.NewConfig()
+
+	if args[0] == "create" {
+		if len(args) != 2 {
+			return errors.New(migrateUsage)
+		}
+
+		f, err := migration.Create(cfg.DB.MigrationsPath, args[1], time.Now())
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(filepath.Join(cfg.DB.MigrationsPath, f.Up))
+		fmt.Println(filepath.Join(cfg.DB.MigrationsPath, f.Down))
+
+		return nil
+	}
+
+	m, err := container.NewMigrationClient(cfg)
+	if err != nil {
+		return fmt.Errorf("can not setup migrations, %w", err)
+	}
+
+	defer m.Close()
+
+	ctx := context.Background()
+
+	return migrate(ctx, m, args[0], args[1:])
+}
+
+//revive:disable:cyclomatic
+func migrate(ctx context.Context, m *migration.Client, cmd string, args []string) error {
+	switch cmd {
+	case "up":
+		dryRun := len(args) > 0 && args[0] == "-dry-run"
+		if dryRun {
+			args = args[1:]
+		}
+
+		n, err := optionalNumber(args, 0)
+		if err != nil {
+			return err
+		}
+
+		if dryRun {
+			return printPending(m, n)
+		}
+
+		if err = m.VerifyChecksums(ctx); err != nil {
+			return err
+		}
+
+		if n == 0 {
+			return synced(ctx, m, m.Up(ctx))
+		}
+
+		return synced(ctx, m, m.Steps(ctx, n))
+	case "down":
+		n, err := optionalNumber(args, 1)
+		if err != nil {
+			return err
+		}
+
+		return synced(ctx, m, m.Steps(ctx, -n))
+	case "goto":
+		v, err := requiredNumber(args, 0)
+		if err != nil {
+			return err
+		}
+
+		if err = m.VerifyChecksums(ctx); err != nil {
+			return err
+		}
+
+		return synced(ctx, m, m.Migrate(ctx, uint(v))) //nolint:gosec
+	case "force":
+		// -1 is the state without migrations.
+		v, err := requiredNumber(args, -1)
+		if err != nil {
+			return err
+		}
+
+		return synced(ctx, m, m.Force(v))
+	case "version":
+		v, dirty, err := m.Version()
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("version: %d, dirty: %t\n", v, dirty)
+
+		return nil
+	case "status":
+		return printStatus(m)
+	case "verify":
+		return m.VerifyChecksums(ctx)
+	case "recover":
+		return recoverDirty(ctx, m, args)
+	default:
+		return errors.New(migrateUsage)
+	}
+}
+
+//revive:enable:cyclomatic
+
+// synced keeps checksums of the applied files in sync after the migrations
+// were applied or rolled back. Nothing is written if err is not nil.
+func synced(ctx context.Context, m *migration.Client, err error) error {
+	if err != nil {
+		return err
+	}
+
+	return m.SyncChecksums(ctx)
+}
+
+func printStatus(m *migration.Client) error {
+	statuses, err := m.Status()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, "VERSION\tSTATUS\tFILE")
+
+	for _, s := range statuses {
+		status := "pending"
+
+		switch {
+		case s.Dirty:
+			status = "dirty"
+		case s.Applied:
+			status = "applied"
+		}
+
+		fmt.Fprintf(w, "%d\t%s\t%s\n", s.Version, status, fileName(s.File))
+	}
+
+	return w.Flush()
+}
+
+// printPending prints SQL of all or n pending migrations.
+func printPending(m *migration.Client, n int) error {
+	pending, err := m.Pending()
+	if err != nil {
+		return err
+	}
+
+	if n > 0 && n < len(pending) {
+		pending = pending[:n]
+	}
+
+	if len(pending) == 0 {
+		fmt.Println("no pending migrations")
+
+		return nil
+	}
+
+	for _, p := range pending {
+		if p.Go {
+			fmt.Printf("-- %s\n-- runs go code\n", fileName(p.File))
+
+			continue
+		}
+
+		fmt.Printf("-- %s\n%s\n", p.Up, strings.TrimSpace(p.SQL))
+	}
+
+	return nil
+}
+
+// fileName returns name of the up file or the name of the Go migration.
+func fileName(f migration.File) string {
+	if f.Go {
+		return fmt.Sprintf("%d_%s (go)", f.Version, f.Name)
+	}
+
+	return f.Up
+}
+
+// recoverDirty prints the dirty migration if the strategy is not set,
+// otherwise resolves the dirty state with the strategy.
+func recoverDirty(ctx context.Context, m *migration.Client, args []string) error {
+	if len(args) > 1 {
+		return errors.New(migrateUsage)
+	}
+
+	state, err := m.DirtyState()
+	if err != nil {
+		return err
+	}
+
+	if !state.Dirty {
+		fmt.Printf("version: %d, database is not dirty\n", state.Version)
+
+		return nil
+	}
+
+	if len(args) == 0 {
+		fmt.Printf("version %d (%s) is dirty, previous version: %d\n", state.Version, fileName(state.File), state.Previous)
+		fmt.Println("run: service migrate recover force|previous|down")
+
+		return nil
+	}
+
+	strategy, err := migration.ParseRecoverStrategy(args[0])
+	if err != nil {
+		return err
+	}
+
+	return synced(ctx, m, m.Recover(ctx, strategy))
+}
+
+func optionalNumber(args []string, def int) (int, error) {
+	if len(args) == 0 {
+		return def, nil
+	}
+
+	return requiredNumber(args, 1)
+}
+
+func requiredNumber(args []string, minValue int) (int, error) {
+	if len(args) != 1 {
+		return 0, errors.New(migrateUsage)
+	}
+
+	n, err := strconv.Atoi(args[0])
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q", args[0])
+	}
+
+	if n < minValue {
+		return 0, fmt.Errorf("number must be at least %d, got %d", minValue, n)
+	}
+
+	return n, nil
+}
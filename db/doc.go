@@ -1,3 +1,5 @@
 // Package db contains content that scripts for database.
-// For example, migrations.
+// For example, migrations. Migrations are embedded into the binary,
+// see Migrations(). Migrations written in Go
+// are registered in GoMigrations().
 package db
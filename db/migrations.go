@@ -0,0 +1,30 @@
+package db
+
+import (
+	"embed"
+	"io/fs"
+
+	"github.com/Melenium2/go-template/pkg/migration"
+)
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// Migrations returns the migration files embedded into the binary, so the
+// application does not depend on the working directory.
+func Migrations() fs.FS {
+	sub, err := fs.Sub(migrations, "migrations")
+	if err != nil {
+		// can not happen, the directory is checked by the go:embed directive.
+		panic(err)
+	}
+
+	return sub
+}
+
+// GoMigrations returns migrations written in Go, for example data backfills.
+// They are applied in order with the SQL files by the version, see
+// migration.GoMigration.
+func GoMigrations() []migration.GoMigration {
+	return []migration.GoMigration{}
+}
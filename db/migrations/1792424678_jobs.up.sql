@@ -0,0 +1,22 @@
+create table if not exists jobs (
+    id           bigserial   not null primary key,
+    kind         text        not null,
+    payload      jsonb       not null default '{}',
+    -- pending, running or dead, finished jobs are deleted.
+    status       text        not null default 'pending',
+    attempts     int         not null default 0,
+    max_attempts int         not null default 10,
+    run_at       timestamptz not null default now(),
+    -- lease of the running job, the job is claimed again after it expires.
+    locked_until timestamptz,
+    unique_key   text,
+    tenant_id    text,
+    last_error   text,
+    created_at   timestamptz not null default now(),
+    updated_at   timestamptz not null default now()
+);
+
+create index if not exists jobs_claim_idx on jobs (run_at, id) where status in ('pending', 'running');
+
+create unique index if not exists jobs_unique_key_idx on jobs (kind, unique_key)
+    where unique_key is not null and status in ('pending', 'running');
@@ -0,0 +1,11 @@
+-- unlogged: the cache is not written to WAL, it is faster, but truncated
+-- after the crash and not replicated.
+create unlogged table if not exists cache_entries (
+    key        text        not null primary key,
+    value      bytea       not null,
+    -- null if the value is not expired.
+    expires_at timestamptz
+);
+
+create index if not exists cache_entries_expires_at_idx on cache_entries (expires_at)
+    where expires_at is not null;
@@ -0,0 +1,159 @@
+// Package bus is a single entrypoint for all commands and queries of the
+// application. The UI layers (http, grpc, events) should not call Do() of the
+// command or query directly, but pass it through the Dispatcher. In this case
+// all the cross-cutting logic (validation, authorization, transactions,
+// logging, metrics etc.) is applied uniformly to each use case.
+//
+// Example:
+//
+//	d := bus.NewDispatcher(
+//		bus.Logging(),
+//		bus.Validation(),
+//		bus.Transaction(),
+//	)
+//
+//	err := bus.Execute(ctx, d, createOrder, command.CreateOrderParameters{...})
+//
+//	res, err := bus.Ask(ctx, d, availableServices, query.AvailableServicesParameters{...})
+package bus
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// Command is a use case that changes the state of the application
+// and returns nothing except an error.
+type Command[P any] interface {
+	Do(ctx context.Context, params P) error
+}
+
+// Query is a use case that reads the state of the application and
+// returns aggregated result.
+type Query[P, R any] interface {
+	Do(ctx context.Context, params P) (R, error)
+}
+
+// Kind of the dispatched use case.
+type Kind uint8
+
+const (
+	KindCommand Kind = iota + 1
+	KindQuery
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindCommand:
+		return "command"
+	case KindQuery:
+		return "query"
+	default:
+		return "unknown"
+	}
+}
+
+// Info describes the dispatched use case. Name is the type name of the
+// command or query, for example "CreateOrder".
+type Info struct {
+	Name string
+	Kind Kind
+}
+
+// Handler is a type-erased version of the command or query. Commands always
+// return nil result.
+type Handler func(ctx context.Context, info Info, params any) (any, error)
+
+// Middleware wraps the Handler with cross-cutting logic.
+type Middleware func(next Handler) Handler
+
+// Dispatcher applies the list of middlewares to each command or query.
+// Middlewares are applied in the order they are provided, so the first
+// middleware is the outermost one.
+type Dispatcher struct {
+	middlewares []Middleware
+}
+
+func NewDispatcher(middlewares ...Middleware) *Dispatcher {
+	return &Dispatcher{middlewares: middlewares}
+}
+
+// Use appends middlewares to the end of the chain.
+func (d *Dispatcher) Use(middlewares ...Middleware) {
+	d.middlewares = append(d.middlewares, middlewares...)
+}
+
+func (d *Dispatcher) dispatch(ctx context.Context, info Info, params any, h Handler) (any, error) {
+	for i := len(d.middlewares) - 1; i >= 0; i-- {
+		h = d.middlewares[i](h)
+	}
+
+	return h(ctx, info, params)
+}
+
+// Execute runs the command through the middleware chain of the Dispatcher.
+func Execute[P any](ctx context.Context, d *Dispatcher, cmd Command[P], params P) error {
+	info := Info{Name: nameOf(cmd), Kind: KindCommand}
+
+	h := func(ctx context.Context, _ Info, params any) (any, error) {
+		p, ok := params.(P)
+		if !ok {
+			return nil, fmt.Errorf("unexpected parameters type %T for command %s", params, info.Name)
+		}
+
+		return nil, cmd.Do(ctx, p)
+	}
+
+	_, err := d.dispatch(ctx, info, params, h)
+
+	return err
+}
+
+// Ask runs the query through the middleware chain of the Dispatcher.
+func Ask[P, R any](ctx context.Context, d *Dispatcher, q Query[P, R], params P) (R, error) {
+	var (
+		empty R
+		info  = Info{Name: nameOf(q), Kind: KindQuery}
+	)
+
+	h := func(ctx context.Context, _ Info, params any) (any, error) {
+		p, ok := params.(P)
+		if !ok {
+			return empty, fmt.Errorf("unexpected parameters type %T for query %s", params, info.Name)
+		}
+
+		return q.Do(ctx, p)
+	}
+
+	res, err := d.dispatch(ctx, info, params, h)
+	if err != nil {
+		return empty, err
+	}
+
+	// nil slices, maps and pointers lose their type after the middleware chain.
+	if res == nil {
+		return empty, nil
+	}
+
+	r, ok := res.(R)
+	if !ok {
+		return empty, fmt.Errorf("unexpected result type %T for query %s", res, info.Name)
+	}
+
+	return r, nil
+}
+
+func nameOf(v any) string {
+	t := reflect.TypeOf(v)
+
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t == nil {
+		return "unknown"
+	}
+
+	return t.Name()
+}
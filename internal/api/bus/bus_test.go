@@ -0,0 +1,215 @@
+package bus
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/Melenium2/go-template/internal/common/erx"
+	"github.com/Melenium2/go-template/internal/common/tx"
+)
+
+type CreateOrderParameters struct {
+	Number string `sanitize:"trim"`
+}
+
+func (p CreateOrderParameters) Validate() error {
+	if p.Number == "" {
+		return errors.New("number is required")
+	}
+
+	return nil
+}
+
+type CreateOrder struct {
+	calls int
+	err   error
+}
+
+func (c *CreateOrder) Do(_ context.Context, _ CreateOrderParameters) error {
+	c.calls++
+
+	return c.err
+}
+
+type AvailableServicesParameters struct {
+	Limit int
+}
+
+type AvailableServices struct{}
+
+func (q *AvailableServices) Do(_ context.Context, params AvailableServicesParameters) ([]string, error) {
+	if params.Limit == 0 {
+		return nil, nil
+	}
+
+	return []string{"delivery", "cleaning"}[:params.Limit], nil
+}
+
+type recorder struct {
+	infos []Info
+}
+
+func (r *recorder) Record(_ context.Context, info Info, _ time.Duration, _ error) {
+	r.infos = append(r.infos, info)
+}
+
+type BusSuite struct {
+	suite.Suite
+
+	sqlMock sqlmock.Sqlmock
+}
+
+func (suite *BusSuite) SetupSuite() {
+	db, mock, _ := sqlmock.New()
+
+	suite.sqlMock = mock
+	tx.SetupManager(sqlx.NewDb(db, "postgres"))
+}
+
+func (suite *BusSuite) TestExecute_Should_run_command_through_middlewares_in_order() {
+	var order []string
+
+	mw := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(ctx context.Context, info Info, params any) (any, error) {
+				order = append(order, name)
+
+				return next(ctx, info, params)
+			}
+		}
+	}
+
+	cmd := &CreateOrder{}
+	d := NewDispatcher(mw("first"), mw("second"))
+
+	err := Execute(context.Background(), d, cmd, CreateOrderParameters{Number: "1"})
+	suite.Assert().NoError(err)
+	suite.Assert().Equal([]string{"first", "second"}, order)
+	suite.Assert().Equal(1, cmd.calls)
+}
+
+func (suite *BusSuite) TestExecute_Should_return_invalid_argument_if_parameters_are_not_valid() {
+	cmd := &CreateOrder{}
+	d := NewDispatcher(Validation())
+
+	err := Execute(context.Background(), d, cmd, CreateOrderParameters{})
+	suite.Assert().ErrorIs(err, erx.ErrInvalidArgument)
+	suite.Assert().Equal(0, cmd.calls)
+}
+
+func (suite *BusSuite) TestExecute_Should_sanitize_parameters_before_validation() {
+	cmd := &CreateOrder{}
+	d := NewDispatcher(Validation())
+
+	err := Execute(context.Background(), d, cmd, CreateOrderParameters{Number: "   "})
+	suite.Assert().ErrorIs(err, erx.ErrInvalidArgument)
+	suite.Assert().Equal(0, cmd.calls)
+}
+
+func (suite *BusSuite) TestExecute_Should_reject_command_if_not_authorized() {
+	errForbidden := errors.New("forbidden")
+
+	cmd := &CreateOrder{}
+	d := NewDispatcher(Authorization(AuthorizerFunc(func(_ context.Context, info Info, _ any) error {
+		if info.Name == "CreateOrder" {
+			return errForbidden
+		}
+
+		return nil
+	})))
+
+	err := Execute(context.Background(), d, cmd, CreateOrderParameters{Number: "1"})
+	suite.Assert().ErrorIs(err, errForbidden)
+	suite.Assert().Equal(0, cmd.calls)
+}
+
+func (suite *BusSuite) TestExecute_Should_wrap_command_in_transaction() {
+	suite.sqlMock.ExpectBegin()
+	suite.sqlMock.ExpectCommit()
+
+	d := NewDispatcher(Transaction())
+
+	err := Execute(context.Background(), d, &CreateOrder{}, CreateOrderParameters{Number: "1"})
+	suite.Assert().NoError(err)
+	suite.Assert().NoError(suite.sqlMock.ExpectationsWereMet())
+}
+
+func (suite *BusSuite) TestExecute_Should_rollback_transaction_if_command_failed() {
+	suite.sqlMock.ExpectBegin()
+	suite.sqlMock.ExpectRollback()
+
+	d := NewDispatcher(Transaction())
+
+	err := Execute(context.Background(), d, &CreateOrder{err: erx.ErrNotFound}, CreateOrderParameters{Number: "1"})
+	suite.Assert().ErrorIs(err, erx.ErrNotFound)
+	suite.Assert().NoError(suite.sqlMock.ExpectationsWereMet())
+}
+
+func (suite *BusSuite) TestExecute_Should_skip_command_with_already_done_idempotency_key() {
+	cmd := &CreateOrder{}
+	d := NewDispatcher(Idempotency(NewMemoryIdempotencyStore()))
+
+	ctx := WithIdempotencyKey(context.Background(), "key")
+
+	for i := 0; i < 3; i++ {
+		err := Execute(ctx, d, cmd, CreateOrderParameters{Number: "1"})
+		suite.Assert().NoError(err)
+	}
+
+	suite.Assert().Equal(1, cmd.calls)
+}
+
+func (suite *BusSuite) TestExecute_Should_retry_command_with_idempotency_key_if_it_failed() {
+	cmd := &CreateOrder{err: errors.New("error")}
+	d := NewDispatcher(Idempotency(NewMemoryIdempotencyStore()))
+
+	ctx := WithIdempotencyKey(context.Background(), "key")
+
+	err := Execute(ctx, d, cmd, CreateOrderParameters{Number: "1"})
+	suite.Assert().Error(err)
+
+	cmd.err = nil
+
+	err = Execute(ctx, d, cmd, CreateOrderParameters{Number: "1"})
+	suite.Assert().NoError(err)
+	suite.Assert().Equal(2, cmd.calls)
+}
+
+func (suite *BusSuite) TestAsk_Should_return_query_result() {
+	rec := &recorder{}
+	d := NewDispatcher(Metrics(rec), Transaction())
+
+	res, err := Ask(context.Background(), d, &AvailableServices{}, AvailableServicesParameters{Limit: 1})
+	suite.Assert().NoError(err)
+	suite.Assert().Equal([]string{"delivery"}, res)
+	suite.Assert().Equal([]Info{{Name: "AvailableServices", Kind: KindQuery}}, rec.infos)
+}
+
+func (suite *BusSuite) TestAsk_Should_return_nil_result_without_error() {
+	d := NewDispatcher(Logging())
+
+	res, err := Ask(context.Background(), d, &AvailableServices{}, AvailableServicesParameters{})
+	suite.Assert().NoError(err)
+	suite.Assert().Nil(res)
+}
+
+func (suite *BusSuite) TestAsk_Should_run_query_in_read_only_transaction() {
+	suite.sqlMock.ExpectBegin()
+	suite.sqlMock.ExpectCommit()
+
+	d := NewDispatcher(ReadOnly())
+
+	_, err := Ask(context.Background(), d, &AvailableServices{}, AvailableServicesParameters{Limit: 2})
+	suite.Assert().NoError(err)
+	suite.Assert().NoError(suite.sqlMock.ExpectationsWereMet())
+}
+
+func TestBusSuite(t *testing.T) {
+	suite.Run(t, new(BusSuite))
+}
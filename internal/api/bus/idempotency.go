@@ -0,0 +1,127 @@
+package bus
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/Melenium2/go-template/internal/common/helper/cache"
+)
+
+var ErrDuplicateCommand = errors.New("command with the same idempotency key is already in progress")
+
+type idempotencyCtxKey uint8
+
+const idempotencyKey idempotencyCtxKey = 1 << 6
+
+// WithIdempotencyKey sets the idempotency key of the command. Usually the key
+// comes from the client, for example from the "Idempotency-Key" http header.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKey, key)
+}
+
+// IdempotencyKey extracts the idempotency key from the context.
+func IdempotencyKey(ctx context.Context) (string, bool) {
+	key, ok := ctx.Value(idempotencyKey).(string)
+
+	return key, ok && key != ""
+}
+
+// IdempotencyState is a state of the command with the idempotency key.
+type IdempotencyState uint8
+
+const (
+	IdempotencyNew IdempotencyState = iota
+	IdempotencyInProgress
+	IdempotencyDone
+)
+
+// IdempotencyStore keeps the idempotency keys of the executed commands.
+type IdempotencyStore interface {
+	// Reserve marks the key as in progress and returns the previous
+	// state of the key.
+	Reserve(ctx context.Context, key string) (IdempotencyState, error)
+	// Complete marks the key as successfully done.
+	Complete(ctx context.Context, key string) error
+	// Release forgets the key, so the command can be retried with it.
+	Release(ctx context.Context, key string) error
+}
+
+// Idempotency skips the command if the command with the same idempotency key
+// is already done. If the command is still in progress ErrDuplicateCommand
+// is returned. Commands without the key and queries are passed as is.
+func Idempotency(store IdempotencyStore) Middleware {
+	return OnlyCommands(func(next Handler) Handler {
+		return func(ctx context.Context, info Info, params any) (any, error) {
+			key, ok := IdempotencyKey(ctx)
+			if !ok {
+				return next(ctx, info, params)
+			}
+
+			key = info.Name + ":" + key
+
+			state, err := store.Reserve(ctx, key)
+			if err != nil {
+				return nil, err
+			}
+
+			switch state {
+			case IdempotencyDone:
+				return nil, nil
+			case IdempotencyInProgress:
+				return nil, ErrDuplicateCommand
+			}
+
+			if _, err = next(ctx, info, params); err != nil {
+				_ = store.Release(ctx, key)
+
+				return nil, err
+			}
+
+			return nil, store.Complete(ctx, key)
+		}
+	})
+}
+
+// MemoryIdempotencyStore keeps limited amount of keys in memory. It is
+// suitable only for a single replica.
+type MemoryIdempotencyStore struct {
+	mutex sync.Mutex
+	keys  *cache.Cache[string, IdempotencyState]
+}
+
+func NewMemoryIdempotencyStore(keysLimit ...int) *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{
+		keys: cache.NewCache[string, IdempotencyState](keysLimit...),
+	}
+}
+
+func (s *MemoryIdempotencyStore) Reserve(_ context.Context, key string) (IdempotencyState, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	state, _ := s.keys.Get(key)
+	if state == IdempotencyNew {
+		s.keys.Set(key, IdempotencyInProgress)
+	}
+
+	return state, nil
+}
+
+func (s *MemoryIdempotencyStore) Complete(_ context.Context, key string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.keys.Set(key, IdempotencyDone)
+
+	return nil
+}
+
+func (s *MemoryIdempotencyStore) Release(_ context.Context, key string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.keys.Set(key, IdempotencyNew)
+
+	return nil
+}
@@ -0,0 +1,160 @@
+package bus
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"time"
+
+	"github.com/Melenium2/go-template/internal/common/tx"
+	"github.com/Melenium2/go-template/internal/common/validate"
+)
+
+// OnlyCommands applies the middleware to commands only.
+func OnlyCommands(mw Middleware) Middleware {
+	return only(KindCommand, mw)
+}
+
+// OnlyQueries applies the middleware to queries only.
+func OnlyQueries(mw Middleware) Middleware {
+	return only(KindQuery, mw)
+}
+
+func only(kind Kind, mw Middleware) Middleware {
+	return func(next Handler) Handler {
+		wrapped := mw(next)
+
+		return func(ctx context.Context, info Info, params any) (any, error) {
+			if info.Kind != kind {
+				return next(ctx, info, params)
+			}
+
+			return wrapped(ctx, info, params)
+		}
+	}
+}
+
+// Validation cleans the parameters by the "sanitize" struct tags, see
+// validate.Sanitize, and validates them by the "validate" struct tags and their
+// Validate() method, see validate.Params. Returned error always wraps
+// erx.ErrInvalidArgument and contains per-field violations.
+func Validation() Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, info Info, params any) (any, error) {
+			params = validate.Sanitize(params)
+
+			if err := validate.Params(params); err != nil {
+				return nil, err
+			}
+
+			return next(ctx, info, params)
+		}
+	}
+}
+
+// Authorizer decides whether the caller from the context can run the use case.
+type Authorizer interface {
+	Authorize(ctx context.Context, info Info, params any) error
+}
+
+// AuthorizerFunc is a function adapter for Authorizer.
+type AuthorizerFunc func(ctx context.Context, info Info, params any) error
+
+func (f AuthorizerFunc) Authorize(ctx context.Context, info Info, params any) error {
+	return f(ctx, info, params)
+}
+
+// Authorization rejects the use case if Authorizer returns an error.
+func Authorization(a Authorizer) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, info Info, params any) (any, error) {
+			if err := a.Authorize(ctx, info, params); err != nil {
+				return nil, err
+			}
+
+			return next(ctx, info, params)
+		}
+	}
+}
+
+// Transaction runs each command inside tx.Manager().Do. Queries are passed as is.
+func Transaction(opts ...sql.TxOptions) Middleware {
+	return OnlyCommands(func(next Handler) Handler {
+		return func(ctx context.Context, info Info, params any) (any, error) {
+			err := tx.Manager().Do(ctx, func(ctx context.Context) error {
+				_, err := next(ctx, info, params)
+
+				return err
+			}, opts...)
+
+			return nil, err
+		}
+	})
+}
+
+// ReadOnly runs each query inside read only transaction. In that case
+// database (or the pooler in front of it) can route the query to a replica.
+// Commands are passed as is.
+func ReadOnly() Middleware {
+	return OnlyQueries(func(next Handler) Handler {
+		return func(ctx context.Context, info Info, params any) (any, error) {
+			var res any
+
+			err := tx.Manager().Do(ctx, func(ctx context.Context) error {
+				var err error
+
+				res, err = next(ctx, info, params)
+
+				return err
+			}, sql.TxOptions{ReadOnly: true})
+
+			return res, err
+		}
+	})
+}
+
+// Recorder collects metrics of the dispatched use cases.
+type Recorder interface {
+	Record(ctx context.Context, info Info, duration time.Duration, err error)
+}
+
+// Metrics passes duration and result of each use case to the Recorder.
+func Metrics(r Recorder) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, info Info, params any) (any, error) {
+			start := time.Now()
+
+			res, err := next(ctx, info, params)
+
+			r.Record(ctx, info, time.Since(start), err)
+
+			return res, err
+		}
+	}
+}
+
+// Logging writes the result of each use case to the default slog logger.
+// Successful use cases are logged with debug level, failed with error level.
+func Logging() Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, info Info, params any) (any, error) {
+			start := time.Now()
+
+			res, err := next(ctx, info, params)
+
+			attrs := []any{
+				slog.String("name", info.Name),
+				slog.String("kind", info.Kind.String()),
+				slog.Duration("duration", time.Since(start)),
+			}
+
+			if err != nil {
+				slog.ErrorContext(ctx, "use case failed", append(attrs, slog.String("error", err.Error()))...)
+			} else {
+				slog.DebugContext(ctx, "use case done", attrs...)
+			}
+
+			return res, err
+		}
+	}
+}
@@ -20,4 +20,9 @@
 // Each command must have the same entrypoint, func Do(). Example:
 //
 //	func (c *CreateOrder) Do(ctx context.Context, params CreateOrderParameters) error
+//
+// In this case the command implements bus.Command and can be executed by the
+// UI layers through the bus.Dispatcher. Example:
+//
+//	err := bus.Execute(ctx, dispatcher, createOrder, CreateOrderParameters{})
 package command
@@ -23,4 +23,21 @@
 //     Each command must have the same entrypoint, func Do(). Example:
 //
 //     func (c *ActionDescription) Do(ctx context.Context, params ActionDescriptionParameters) error
+//
+// In this case the query implements bus.Query and can be asked by the
+// UI layers through the bus.Dispatcher. Example:
+//
+//	res, err := bus.Ask(ctx, dispatcher, actionDescription, ActionDescriptionParameters{})
+//
+// Queries that return lists embed pagination.Request into the parameters and
+// return pagination.Page of the results. Example:
+//
+//	type AvailableServicesParameters struct {
+//		pagination.Request
+//	}
+//
+//	func (q *AvailableServices) Do(
+//		ctx context.Context,
+//		params AvailableServicesParameters,
+//	) (pagination.Page[Service], error)
 package query
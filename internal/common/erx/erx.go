@@ -5,4 +5,5 @@ import "errors"
 var (
 	ErrNotFound        = errors.New("not found")
 	ErrInvalidArgument = errors.New("invalid argument")
+	ErrConflict        = errors.New("conflict")
 )
@@ -0,0 +1,57 @@
+package cache
+
+import (
+	"errors"
+	"sync"
+)
+
+var errLoaderPanic = errors.New("cache loader panicked")
+
+type call[V any] struct {
+	wg    sync.WaitGroup
+	value V
+	err   error
+}
+
+// Flight de-duplicates concurrent loads of the same key, like
+// golang.org/x/sync/singleflight, but typed. The zero value is ready to use.
+type Flight[K comparable, V any] struct {
+	mu    sync.Mutex
+	calls map[K]*call[V]
+}
+
+// Do calls fn once for concurrent calls with the same key, the callers
+// waiting for the call get its result.
+func (f *Flight[K, V]) Do(key K, fn func() (V, error)) (V, error) {
+	f.mu.Lock()
+
+	if c, ok := f.calls[key]; ok {
+		f.mu.Unlock()
+		c.wg.Wait()
+
+		return c.value, c.err
+	}
+
+	if f.calls == nil {
+		f.calls = make(map[K]*call[V])
+	}
+
+	c := &call[V]{err: errLoaderPanic}
+	c.wg.Add(1)
+	f.calls[key] = c
+
+	f.mu.Unlock()
+
+	// waiters get errLoaderPanic if fn panics, the panic is not recovered.
+	defer func() {
+		f.mu.Lock()
+		delete(f.calls, key)
+		f.mu.Unlock()
+
+		c.wg.Done()
+	}()
+
+	c.value, c.err = fn()
+
+	return c.value, c.err
+}
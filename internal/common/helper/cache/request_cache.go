@@ -3,6 +3,8 @@ package cache
 import (
 	"cmp"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 const defaultKeysLimit = 500
@@ -11,13 +13,39 @@ type ExtendedConstraint interface {
 	cmp.Ordered | ~[16]byte // uuid.UUID ([16]byte) type
 }
 
+// Stats of the cache.
+type Stats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+}
+
+type entry[K ExtendedConstraint, V any] struct {
+	key   K
+	value V
+	// expires is zero if the entry has no TTL.
+	expires time.Time
+
+	prev, next *entry[K, V]
+}
+
+// Cache is the LRU cache limited by the number of keys. The least recently
+// used key is evicted when the limit is exceeded, entries with TTL are
+// removed when they are expired.
 type Cache[K ExtendedConstraint, V any] struct {
-	limit int
+	limit   int
+	ttl     time.Duration
+	onEvict func(key K, value V)
+	now     func() time.Time
 
-	mutex sync.RWMutex
-	c     map[K]V
-	list  []K
-	curr  int
+	mutex sync.Mutex
+	c     map[K]*entry[K, V]
+	// root.next is the most recently used entry, root.prev is the least.
+	root entry[K, V]
+
+	loads Flight[K, V]
+
+	hits, misses, evictions atomic.Uint64
 }
 
 func NewCache[K ExtendedConstraint, V any](keysLimit ...int) *Cache[K, V] {
@@ -27,37 +55,201 @@ func NewCache[K ExtendedConstraint, V any](keysLimit ...int) *Cache[K, V] {
 		limit = keysLimit[0]
 	}
 
-	return &Cache[K, V]{
+	c := &Cache[K, V]{
 		limit: limit,
-		c:     make(map[K]V, limit+1),
-		list:  make([]K, limit+1),
-		curr:  0,
+		now:   time.Now,
+		c:     make(map[K]*entry[K, V], limit),
 	}
+
+	c.root.next = &c.root
+	c.root.prev = &c.root
+
+	return c
 }
 
+// WithTTL sets the TTL of the entries set by Set and GetOrLoad.
+//
+// Default: entries are not expired.
+func (c *Cache[K, V]) WithTTL(ttl time.Duration) *Cache[K, V] {
+	c.ttl = ttl
+
+	return c
+}
+
+// WithOnEvict sets the callback called when the entry is evicted by the limit
+// or removed after the TTL. It is not called for Delete and overwritten
+// values. The callback is called without the lock, so it can use the cache.
+func (c *Cache[K, V]) WithOnEvict(fn func(key K, value V)) *Cache[K, V] {
+	c.onEvict = fn
+
+	return c
+}
+
+// Set sets the value with the TTL of the cache.
 func (c *Cache[K, V]) Set(key K, value V) {
+	c.SetWithTTL(key, value, c.ttl)
+}
+
+// SetWithTTL sets the value expired after the ttl, zero ttl means the value
+// is not expired.
+func (c *Cache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) {
+	var expires time.Time
+
+	if ttl > 0 {
+		expires = c.now().Add(ttl)
+	}
+
+	var evicted *entry[K, V]
+
 	c.mutex.Lock()
-	defer c.mutex.Unlock()
 
-	c.c[key] = value
+	if e, ok := c.c[key]; ok {
+		e.value = value
+		e.expires = expires
+
+		c.moveToFront(e)
+	} else {
+		e = &entry[K, V]{key: key, value: value, expires: expires}
+
+		c.c[key] = e
+		c.pushFront(e)
 
-	// not efficient way to purge map tail, but in this case
-	// we don't care.
-	k := c.list[c.curr]
+		if len(c.c) > c.limit {
+			evicted = c.root.prev
 
-	delete(c.c, k)
+			c.remove(evicted)
+		}
+	}
 
-	c.list[c.curr] = key
+	c.mutex.Unlock()
 
-	c.curr = (c.curr + 1) % c.limit
+	if evicted != nil {
+		c.evicted(evicted)
+	}
 }
 
+// Get returns the value and marks the key as recently used.
 func (c *Cache[K, V]) Get(key K) (V, bool) {
-	c.mutex.RLock()
+	c.mutex.Lock()
+
+	e, ok := c.c[key]
+
+	if ok && c.expired(e) {
+		c.remove(e)
+		c.mutex.Unlock()
+
+		c.evicted(e)
+		c.misses.Add(1)
+
+		var zero V
+
+		return zero, false
+	}
+
+	if !ok {
+		c.mutex.Unlock()
+		c.misses.Add(1)
+
+		var zero V
+
+		return zero, false
+	}
+
+	c.moveToFront(e)
 
-	value, ok := c.c[key]
+	value := e.value
+
+	c.mutex.Unlock()
+	c.hits.Add(1)
+
+	return value, true
+}
+
+// GetOrLoad returns the cached value or calls the loader and caches its
+// value. Concurrent calls for the same key wait for the single loader.
+// Errors of the loader are returned to all waiters and are not cached.
+func (c *Cache[K, V]) GetOrLoad(key K, loader func() (V, error)) (V, error) {
+	if value, ok := c.Get(key); ok {
+		return value, nil
+	}
+
+	return c.loads.Do(key, func() (V, error) {
+		value, err := loader()
+		if err != nil {
+			return value, err
+		}
+
+		c.Set(key, value)
+
+		return value, nil
+	})
+}
+
+// Delete removes the key, returns false if the key is not found.
+func (c *Cache[K, V]) Delete(key K) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	e, ok := c.c[key]
+	if ok {
+		c.remove(e)
+	}
+
+	return ok
+}
+
+// Len returns the number of the keys, including expired keys that are not
+// removed yet.
+func (c *Cache[K, V]) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return len(c.c)
+}
+
+// Stats returns the counters since the cache is created.
+func (c *Cache[K, V]) Stats() Stats {
+	return Stats{
+		Hits:      c.hits.Load(),
+		Misses:    c.misses.Load(),
+		Evictions: c.evictions.Load(),
+	}
+}
+
+func (c *Cache[K, V]) expired(e *entry[K, V]) bool {
+	return !e.expires.IsZero() && !c.now().Before(e.expires)
+}
+
+func (c *Cache[K, V]) evicted(e *entry[K, V]) {
+	c.evictions.Add(1)
+
+	if c.onEvict != nil {
+		c.onEvict(e.key, e.value)
+	}
+}
+
+func (c *Cache[K, V]) pushFront(e *entry[K, V]) {
+	e.prev = &c.root
+	e.next = c.root.next
+	e.prev.next = e
+	e.next.prev = e
+}
+
+func (c *Cache[K, V]) moveToFront(e *entry[K, V]) {
+	if c.root.next == e {
+		return
+	}
+
+	e.prev.next = e.next
+	e.next.prev = e.prev
+
+	c.pushFront(e)
+}
 
-	c.mutex.RUnlock()
+func (c *Cache[K, V]) remove(e *entry[K, V]) {
+	e.prev.next = e.next
+	e.next.prev = e.prev
+	e.prev, e.next = nil, nil
 
-	return value, ok
+	delete(c.c, e.key)
 }
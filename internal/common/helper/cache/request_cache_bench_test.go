@@ -0,0 +1,166 @@
+package cache
+
+import (
+	"fmt"
+	"runtime"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// ringCache is the previous implementation of Cache with the FIFO ring,
+// it is kept to compare the benchmarks.
+type ringCache[K ExtendedConstraint, V any] struct {
+	limit int
+
+	mutex sync.RWMutex
+	c     map[K]V
+	list  []K
+	curr  int
+}
+
+func newRingCache[K ExtendedConstraint, V any](limit int) *ringCache[K, V] {
+	return &ringCache[K, V]{
+		limit: limit,
+		c:     make(map[K]V, limit+1),
+		list:  make([]K, limit+1),
+	}
+}
+
+func (c *ringCache[K, V]) Set(key K, value V) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.c[key] = value
+
+	k := c.list[c.curr]
+
+	delete(c.c, k)
+
+	c.list[c.curr] = key
+
+	c.curr = (c.curr + 1) % c.limit
+}
+
+func (c *ringCache[K, V]) Get(key K) (V, bool) {
+	c.mutex.RLock()
+
+	value, ok := c.c[key]
+
+	c.mutex.RUnlock()
+
+	return value, ok
+}
+
+type benchCache interface {
+	Set(key string, value int)
+	Get(key string) (int, bool)
+}
+
+const benchLimit = 1000
+
+func benchKeys() []string {
+	// twice the limit, so half of the reads miss and writes evict.
+	keys := make([]string, 2*benchLimit)
+
+	for i := range keys {
+		keys[i] = "key-" + strconv.Itoa(i)
+	}
+
+	return keys
+}
+
+func benchImplementations() map[string]func() benchCache {
+	return map[string]func() benchCache{
+		"lru":  func() benchCache { return NewCache[string, int](benchLimit) },
+		"ring": func() benchCache { return newRingCache[string, int](benchLimit) },
+	}
+}
+
+func BenchmarkCache_Set(b *testing.B) {
+	keys := benchKeys()
+
+	for name, newCache := range benchImplementations() {
+		b.Run(name, func(b *testing.B) {
+			cache := newCache()
+
+			for i := 0; b.Loop(); i++ {
+				cache.Set(keys[i%len(keys)], i)
+			}
+		})
+	}
+}
+
+func BenchmarkCache_Get(b *testing.B) {
+	keys := benchKeys()
+
+	for name, newCache := range benchImplementations() {
+		b.Run(name, func(b *testing.B) {
+			cache := newCache()
+
+			for i, k := range keys {
+				cache.Set(k, i)
+			}
+
+			for i := 0; b.Loop(); i++ {
+				cache.Get(keys[i%len(keys)])
+			}
+		})
+	}
+}
+
+// BenchmarkCache_Parallel runs 90% of reads and 10% of writes.
+func BenchmarkCache_Parallel(b *testing.B) {
+	keys := benchKeys()
+
+	for name, newCache := range benchImplementations() {
+		b.Run(name, func(b *testing.B) {
+			cache := newCache()
+
+			b.RunParallel(func(pb *testing.PB) {
+				for i := 0; pb.Next(); i++ {
+					k := keys[i%len(keys)]
+
+					if i%10 == 0 {
+						cache.Set(k, i)
+					} else {
+						cache.Get(k)
+					}
+				}
+			})
+		})
+	}
+}
+
+// BenchmarkCache_Procs compares the single lock and the sharded cache with
+// 90% of reads and 10% of writes at different GOMAXPROCS.
+func BenchmarkCache_Procs(b *testing.B) {
+	keys := benchKeys()
+
+	implementations := map[string]func() benchCache{
+		"lru":     func() benchCache { return NewCache[string, int](benchLimit) },
+		"sharded": func() benchCache { return NewSharded[string, int](benchLimit) },
+	}
+
+	for _, procs := range []int{1, 2, 4, 8, 16} {
+		for name, newCache := range implementations {
+			b.Run(fmt.Sprintf("procs=%d/%s", procs, name), func(b *testing.B) {
+				defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(procs))
+
+				cache := newCache()
+
+				b.RunParallel(func(pb *testing.PB) {
+					for i := 0; pb.Next(); i++ {
+						k := keys[i%len(keys)]
+
+						if i%10 == 0 {
+							cache.Set(k, i)
+						} else {
+							cache.Get(k)
+						}
+					}
+				})
+			})
+		}
+	}
+}
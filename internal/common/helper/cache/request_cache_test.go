@@ -1,8 +1,11 @@
 package cache
 
 import (
+	"errors"
 	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -94,3 +97,153 @@ func TestCache_Get_Should_concurrent_read_write_to_map(t *testing.T) {
 	wgWrite.Wait()
 	wgRead.Wait()
 }
+
+func TestCache_Set_Should_keep_recently_set_key(t *testing.T) {
+	cache := NewCache[int, string](3)
+
+	cache.Set(1, "a")
+	cache.Set(2, "b")
+	cache.Set(1, "c")
+	cache.Set(3, "d")
+	cache.Set(4, "e")
+
+	value, ok := cache.Get(1)
+	assert.True(t, ok)
+	assert.Equal(t, "c", value)
+
+	_, ok = cache.Get(2)
+	assert.False(t, ok)
+	assert.Equal(t, 3, cache.Len())
+}
+
+func TestCache_Get_Should_mark_key_as_recently_used(t *testing.T) {
+	cache := NewCache[int, string](2)
+
+	cache.Set(1, "a")
+	cache.Set(2, "b")
+
+	_, ok := cache.Get(1)
+	assert.True(t, ok)
+
+	cache.Set(3, "c")
+
+	_, ok = cache.Get(1)
+	assert.True(t, ok)
+
+	_, ok = cache.Get(2)
+	assert.False(t, ok)
+}
+
+func TestCache_Get_Should_remove_expired_value(t *testing.T) {
+	now := time.Now()
+
+	var evicted []int
+
+	cache := NewCache[int, string](10).
+		WithTTL(time.Minute).
+		WithOnEvict(func(key int, _ string) {
+			evicted = append(evicted, key)
+		})
+	cache.now = func() time.Time { return now }
+
+	cache.Set(1, "a")
+	cache.SetWithTTL(2, "b", time.Hour)
+	cache.SetWithTTL(3, "c", 0)
+
+	now = now.Add(2 * time.Minute)
+
+	_, ok := cache.Get(1)
+	assert.False(t, ok)
+
+	for _, key := range []int{2, 3} {
+		_, ok = cache.Get(key)
+		assert.True(t, ok)
+	}
+
+	assert.Equal(t, []int{1}, evicted)
+	assert.Equal(t, 2, cache.Len())
+	assert.Equal(t, Stats{Hits: 2, Misses: 1, Evictions: 1}, cache.Stats())
+}
+
+func TestCache_Set_Should_call_on_evict_for_least_recently_used(t *testing.T) {
+	var evicted []int
+
+	cache := NewCache[int, int](2).WithOnEvict(func(key int, _ int) {
+		evicted = append(evicted, key)
+	})
+
+	for i := range 5 {
+		cache.Set(i, i)
+	}
+
+	assert.True(t, cache.Delete(4))
+	assert.False(t, cache.Delete(4))
+
+	assert.Equal(t, []int{0, 1, 2}, evicted)
+	assert.Equal(t, 1, cache.Len())
+	assert.Equal(t, uint64(3), cache.Stats().Evictions)
+}
+
+func TestCache_GetOrLoad_Should_load_value_once(t *testing.T) {
+	cache := NewCache[string, int](10)
+
+	var (
+		calls   atomic.Int32
+		release = make(chan struct{})
+		wg      sync.WaitGroup
+	)
+
+	loader := func() (int, error) {
+		calls.Add(1)
+		<-release
+
+		return 42, nil
+	}
+
+	results := make([]int, 10)
+
+	wg.Add(len(results))
+
+	for i := range results {
+		go func() {
+			defer wg.Done()
+
+			results[i], _ = cache.GetOrLoad("key", loader)
+		}()
+	}
+
+	// waits for the loader, other calls join it.
+	assert.Eventually(t, func() bool { return calls.Load() == 1 }, time.Second, time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls.Load())
+
+	for _, r := range results {
+		assert.Equal(t, 42, r)
+	}
+
+	value, err := cache.GetOrLoad("key", loader)
+	assert.NoError(t, err)
+	assert.Equal(t, 42, value)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestCache_GetOrLoad_Should_not_cache_error(t *testing.T) {
+	cache := NewCache[string, int](10)
+
+	errLoad := errors.New("load")
+
+	_, err := cache.GetOrLoad("key", func() (int, error) {
+		return 0, errLoad
+	})
+	assert.ErrorIs(t, err, errLoad)
+	assert.Equal(t, 0, cache.Len())
+
+	value, err := cache.GetOrLoad("key", func() (int, error) {
+		return 1, nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, value)
+}
@@ -0,0 +1,128 @@
+package cache
+
+import (
+	"hash/maphash"
+	"runtime"
+	"time"
+)
+
+// Sharded is the Cache split into shards by the hash of the key, so writers
+// of different keys do not wait for the single lock. Each shard is the LRU
+// with its part of the total limit, so the evicted key is the least
+// recently used in its shard, not in the whole cache.
+type Sharded[K ExtendedConstraint, V any] struct {
+	seed   maphash.Seed
+	mask   uint64
+	shards []*Cache[K, V]
+}
+
+// NewSharded returns the cache with keysLimit keys in total split into the
+// number of shards rounded down to the power of two.
+//
+// Default: defaultKeysLimit keys, 4 * GOMAXPROCS shards.
+func NewSharded[K ExtendedConstraint, V any](keysLimit int, shards ...int) *Sharded[K, V] {
+	if keysLimit <= 0 {
+		keysLimit = defaultKeysLimit
+	}
+
+	n := 4 * runtime.GOMAXPROCS(0)
+	if len(shards) > 0 && shards[0] > 0 {
+		n = shards[0]
+	}
+
+	// each shard holds at least one key.
+	n = min(n, keysLimit)
+
+	size := 1
+	for size*2 <= n {
+		size <<= 1
+	}
+
+	s := &Sharded[K, V]{
+		seed:   maphash.MakeSeed(),
+		mask:   uint64(size - 1),
+		shards: make([]*Cache[K, V], size),
+	}
+
+	// the limit is split exactly, the first shards take the remainder.
+	perShard, rem := keysLimit/size, keysLimit%size
+
+	for i := range s.shards {
+		limit := perShard
+		if i < rem {
+			limit++
+		}
+
+		s.shards[i] = NewCache[K, V](limit)
+	}
+
+	return s
+}
+
+// WithTTL sets the TTL of the entries, see Cache.WithTTL.
+func (s *Sharded[K, V]) WithTTL(ttl time.Duration) *Sharded[K, V] {
+	for _, c := range s.shards {
+		c.WithTTL(ttl)
+	}
+
+	return s
+}
+
+// WithOnEvict sets the callback of the evicted entries, see Cache.WithOnEvict.
+func (s *Sharded[K, V]) WithOnEvict(fn func(key K, value V)) *Sharded[K, V] {
+	for _, c := range s.shards {
+		c.WithOnEvict(fn)
+	}
+
+	return s
+}
+
+func (s *Sharded[K, V]) shard(key K) *Cache[K, V] {
+	return s.shards[maphash.Comparable(s.seed, key)&s.mask]
+}
+
+func (s *Sharded[K, V]) Set(key K, value V) {
+	s.shard(key).Set(key, value)
+}
+
+func (s *Sharded[K, V]) SetWithTTL(key K, value V, ttl time.Duration) {
+	s.shard(key).SetWithTTL(key, value, ttl)
+}
+
+func (s *Sharded[K, V]) Get(key K) (V, bool) {
+	return s.shard(key).Get(key)
+}
+
+func (s *Sharded[K, V]) GetOrLoad(key K, loader func() (V, error)) (V, error) {
+	return s.shard(key).GetOrLoad(key, loader)
+}
+
+func (s *Sharded[K, V]) Delete(key K) bool {
+	return s.shard(key).Delete(key)
+}
+
+// Len returns the number of the keys in all shards.
+func (s *Sharded[K, V]) Len() int {
+	var n int
+
+	for _, c := range s.shards {
+		n += c.Len()
+	}
+
+	return n
+}
+
+// Stats returns the sum of the counters of the shards.
+func (s *Sharded[K, V]) Stats() Stats {
+	var stats Stats
+
+	for _, c := range s.shards {
+		cs := c.Stats()
+
+		stats.Hits += cs.Hits
+		stats.Misses += cs.Misses
+		stats.Evictions += cs.Evictions
+	}
+
+	return stats
+}
@@ -0,0 +1,97 @@
+package cache
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSharded_Should_split_limit_between_shards(t *testing.T) {
+	tt := []struct {
+		name       string
+		limit      int
+		shards     int
+		wantShards int
+	}{
+		{name: "power of two", limit: 100, shards: 8, wantShards: 8},
+		{name: "rounded down", limit: 100, shards: 12, wantShards: 8},
+		{name: "more shards than keys", limit: 3, shards: 16, wantShards: 2},
+	}
+
+	for _, test := range tt {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewSharded[int, int](test.limit, test.shards)
+
+			assert.Len(t, s.shards, test.wantShards)
+
+			var total int
+
+			for _, c := range s.shards {
+				assert.Positive(t, c.limit)
+
+				total += c.limit
+			}
+
+			assert.Equal(t, test.limit, total)
+		})
+	}
+}
+
+func TestSharded_Set_Should_keep_total_limit_concurrent(t *testing.T) {
+	s := NewSharded[uuid.UUID, int](64, 4)
+
+	parallels := 8
+
+	var wg sync.WaitGroup
+	wg.Add(parallels)
+
+	for range parallels {
+		go func() {
+			defer wg.Done()
+
+			for j := range 200 {
+				s.Set(uuid.New(), j)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	assert.LessOrEqual(t, s.Len(), 64)
+	assert.Equal(t, uint64(parallels*200-s.Len()), s.Stats().Evictions)
+}
+
+func TestSharded_Get_Should_return_values_from_shards(t *testing.T) {
+	var evicted int
+
+	s := NewSharded[string, int](1000, 4).WithOnEvict(func(string, int) {
+		evicted++
+	})
+
+	keys := []string{"a", "b", "c", "d", "e", "f"}
+
+	for i, k := range keys {
+		s.Set(k, i)
+	}
+
+	for i, k := range keys {
+		value, ok := s.Get(k)
+		assert.True(t, ok)
+		assert.Equal(t, i, value)
+	}
+
+	assert.True(t, s.Delete("a"))
+
+	_, ok := s.Get("a")
+	assert.False(t, ok)
+
+	value, err := s.GetOrLoad("a", func() (int, error) { return 10, nil })
+	assert.NoError(t, err)
+	assert.Equal(t, 10, value)
+
+	assert.Equal(t, len(keys), s.Len())
+	assert.Equal(t, Stats{Hits: 6, Misses: 2}, s.Stats())
+	assert.Zero(t, evicted)
+}
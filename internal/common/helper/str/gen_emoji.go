@@ -0,0 +1,218 @@
+//go:build ignore
+
+// This program generates emoji_tables.go from emoji-data.txt of the Unicode
+// Character Database. The version must match the version of uniseg, which
+// splits the strings into grapheme clusters.
+//
+//	go run gen_emoji.go [-data emoji-data.txt] [-out emoji_tables.go]
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const emojiURL = "https://unicode.org/Public/15.0.0/ucd/emoji/emoji-data.txt"
+
+// tables are the generated properties in order.
+var tables = []struct {
+	property string
+	name     string
+	doc      string
+}{
+	{
+		property: "Extended_Pictographic",
+		name:     "extendedPictographic",
+		doc: "// extendedPictographic is the Extended_Pictographic property, the base of\n" +
+			"// emoji sequences.",
+	},
+	{
+		property: "Emoji_Presentation",
+		name:     "emojiPresentation",
+		doc: "// emojiPresentation is the Emoji_Presentation property, such characters are\n" +
+			"// displayed as emoji by default.",
+	},
+}
+
+// the line of the code point or the range with the property.
+var linePattern = regexp.MustCompile(`^([0-9A-F]{4,6})(?:\.\.([0-9A-F]{4,6}))?\s*;\s*([A-Za-z_]+)`)
+
+type runeRange struct {
+	lo, hi rune
+}
+
+func main() {
+	data := flag.String("data", emojiURL, "URL or path of emoji-data.txt")
+	out := flag.String("out", "emoji_tables.go", "generated file")
+
+	flag.Parse()
+
+	log.SetFlags(0)
+	log.SetPrefix("gen_emoji: ")
+
+	src, err := open(*data)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	defer src.Close()
+
+	ranges, err := parse(src)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	code, err := generate(*data, ranges)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err = os.WriteFile(*out, code, 0o644); err != nil { //nolint:gosec
+		log.Fatal(err)
+	}
+}
+
+func open(data string) (io.ReadCloser, error) {
+	if !strings.HasPrefix(data, "https://") {
+		return os.Open(data)
+	}
+
+	resp, err := http.Get(data) //nolint:gosec,noctx
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+
+		return nil, fmt.Errorf("can not download %s, status %s", data, resp.Status)
+	}
+
+	return resp.Body, nil
+}
+
+// parse returns the sorted and merged ranges of each property.
+func parse(r io.Reader) (map[string][]runeRange, error) {
+	ranges := make(map[string][]runeRange)
+	scanner := bufio.NewScanner(r)
+
+	for scanner.Scan() {
+		m := linePattern.FindStringSubmatch(scanner.Text())
+		if m == nil {
+			continue
+		}
+
+		lo, err := strconv.ParseUint(m[1], 16, 32)
+		if err != nil {
+			return nil, err
+		}
+
+		hi := lo
+
+		if m[2] != "" {
+			if hi, err = strconv.ParseUint(m[2], 16, 32); err != nil {
+				return nil, err
+			}
+		}
+
+		ranges[m[3]] = append(ranges[m[3]], runeRange{lo: rune(lo), hi: rune(hi)})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	for property, rs := range ranges {
+		ranges[property] = merge(rs)
+	}
+
+	return ranges, nil
+}
+
+func merge(rs []runeRange) []runeRange {
+	slices.SortFunc(rs, func(a, b runeRange) int { return int(a.lo - b.lo) })
+
+	merged := rs[:1]
+
+	for _, r := range rs[1:] {
+		last := &merged[len(merged)-1]
+
+		if r.lo <= last.hi+1 {
+			last.hi = max(last.hi, r.hi)
+
+			continue
+		}
+
+		merged = append(merged, r)
+	}
+
+	return merged
+}
+
+func generate(data string, ranges map[string][]runeRange) ([]byte, error) {
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "// Code generated by gen_emoji.go from %s. DO NOT EDIT.\n\n", data)
+	buf.WriteString("package str\n\nimport \"unicode\"\n")
+
+	for _, t := range tables {
+		rs, ok := ranges[t.property]
+		if !ok {
+			return nil, fmt.Errorf("property %s is not found", t.property)
+		}
+
+		var r16, r32 []runeRange
+
+		latinOffset := 0
+
+		for _, r := range rs {
+			if r.hi <= 0xFFFF {
+				r16 = append(r16, r)
+			} else {
+				r32 = append(r32, r)
+			}
+
+			if r.hi <= unicode.MaxLatin1 {
+				latinOffset++
+			}
+		}
+
+		fmt.Fprintf(&buf, "\n%s\nvar %s = &unicode.RangeTable{\n", t.doc, t.name)
+
+		if len(r16) > 0 {
+			buf.WriteString("R16: []unicode.Range16{\n")
+
+			for _, r := range r16 {
+				fmt.Fprintf(&buf, "{Lo: 0x%04X, Hi: 0x%04X, Stride: 1},\n", r.lo, r.hi)
+			}
+
+			buf.WriteString("},\n")
+		}
+
+		if len(r32) > 0 {
+			buf.WriteString("R32: []unicode.Range32{\n")
+
+			for _, r := range r32 {
+				fmt.Fprintf(&buf, "{Lo: 0x%04X, Hi: 0x%04X, Stride: 1},\n", r.lo, r.hi)
+			}
+
+			buf.WriteString("},\n")
+		}
+
+		fmt.Fprintf(&buf, "LatinOffset: %d,\n}\n", latinOffset)
+	}
+
+	return format.Source(buf.Bytes())
+}
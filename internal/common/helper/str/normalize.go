@@ -0,0 +1,143 @@
+package str
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/rivo/uniseg"
+	"golang.org/x/text/unicode/norm"
+)
+
+// NFC composes characters, so the same text typed differently, for example
+// "й" as one or two code points, is stored the same way.
+func NFC(str string) string {
+	return norm.NFC.String(str)
+}
+
+// NFKC is like NFC, but also replaces compatibility characters, for example
+// full width letters, ligatures and superscripts, by the plain ones. Use it
+// for identifiers and search, not for the text shown to the user.
+func NFKC(str string) string {
+	return norm.NFKC.String(str)
+}
+
+// invisible characters that are not in the Cc and Cf categories.
+var invisibleFillers = map[rune]struct{}{
+	'\u115F': {}, // HANGUL CHOSEONG FILLER
+	'\u1160': {}, // HANGUL JUNGSEONG FILLER
+	'\u3164': {}, // HANGUL FILLER
+	'\uFFA0': {}, // HALFWIDTH HANGUL FILLER
+	'\u2800': {}, // BRAILLE PATTERN BLANK
+}
+
+// IsInvisible reports whether the character is the control (except
+// whitespace) or the invisible format character, for example zero width
+// space, BOM or bidi controls.
+func IsInvisible(r rune) bool {
+	if _, ok := invisibleFillers[r]; ok {
+		return true
+	}
+
+	if unicode.IsSpace(r) {
+		return false
+	}
+
+	return unicode.Is(unicode.Cc, r) || unicode.Is(unicode.Cf, r)
+}
+
+// StripInvisible removes invisible characters, see IsInvisible. Format
+// characters inside emojis (zero width joiner, tags of flags) are kept.
+func StripInvisible(str string) string {
+	var builder strings.Builder
+
+	builder.Grow(len(str))
+
+	state := -1
+
+	for rest := str; len(rest) > 0; {
+		var cluster string
+
+		cluster, rest, _, state = uniseg.FirstGraphemeClusterInString(rest, state)
+
+		if IsEmoji(cluster) {
+			builder.WriteString(cluster)
+
+			continue
+		}
+
+		for _, r := range cluster {
+			if !IsInvisible(r) {
+				builder.WriteRune(r)
+			}
+		}
+	}
+
+	return builder.String()
+}
+
+// NewLines is the mode of CollapseSpace.
+type NewLines int
+
+const (
+	// DropNewLines replaces line breaks by the space.
+	DropNewLines NewLines = iota
+	// KeepNewLines keeps each line break, so empty lines are kept.
+	KeepNewLines
+	// SingleNewLines keeps one line break of the several in a row.
+	SingleNewLines
+)
+
+// CollapseSpace replaces each run of whitespace by the single space and trims
+// the string. Line breaks (\n, \r\n, \r, U+2028, U+2029) are handled by the
+// mode, spaces around them are removed.
+func CollapseSpace(str string, mode NewLines) string {
+	var builder strings.Builder
+
+	builder.Grow(len(str))
+
+	var (
+		space  bool
+		breaks int
+	)
+
+	flush := func() {
+		switch {
+		case builder.Len() == 0:
+		case breaks > 0 && mode == KeepNewLines:
+			builder.WriteString(strings.Repeat("\n", breaks))
+		case breaks > 0 && mode == SingleNewLines:
+			builder.WriteByte('\n')
+		case breaks > 0, space:
+			builder.WriteByte(' ')
+		}
+
+		space, breaks = false, 0
+	}
+
+	for i, r := range str {
+		switch {
+		case r == '\n' && i > 0 && str[i-1] == '\r':
+		case isLineBreak(r):
+			breaks++
+		case unicode.IsSpace(r):
+			space = true
+		default:
+			if space || breaks > 0 {
+				flush()
+			}
+
+			builder.WriteRune(r)
+		}
+	}
+
+	return builder.String()
+}
+
+func isLineBreak(r rune) bool {
+	switch r {
+	case '\n', '\r', '\u2028', '\u2029':
+		return true
+	default:
+		return false
+	}
+}
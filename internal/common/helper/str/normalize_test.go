@@ -0,0 +1,111 @@
+package str
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNFC(t *testing.T) {
+	tests := []struct {
+		name string
+		str  string
+		nfc  string
+		nfkc string
+	}{
+		{
+			name: "should compose letter with combining mark",
+			str:  "\u0438\u0306 e\u0301",
+			nfc:  "\u0439 \u00e9",
+			nfkc: "\u0439 \u00e9",
+		},
+		{
+			name: "should replace compatibility characters only by nfkc",
+			str:  "\ufb01 \uff26\uff55\uff4c\uff4c x\u00b2",
+			nfc:  "\ufb01 \uff26\uff55\uff4c\uff4c x\u00b2",
+			nfkc: "fi Full x2",
+		},
+	}
+
+	t.Parallel()
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.nfc, NFC(tc.str))
+			assert.Equal(t, tc.nfkc, NFKC(tc.str))
+		})
+	}
+}
+
+func TestStripInvisible(t *testing.T) {
+	tests := []struct {
+		name     string
+		str      string
+		expected string
+	}{
+		{
+			name:     "should remove zero width and bom characters",
+			str:      "\ufeffad\u200bmin\u2060",
+			expected: "admin",
+		},
+		{
+			name:     "should remove controls but keep whitespace",
+			str:      "a\x00b\x1b[31m\tc\nd",
+			expected: "ab[31m\tc\nd",
+		},
+		{
+			name:     "should remove bidi controls and fillers",
+			str:      "\u202eabc\u202c\u3164",
+			expected: "abc",
+		},
+		{
+			name:     "should keep joiners inside emoji",
+			str:      "\U0001f468\u200d\U0001f469\u200d\U0001f467 \u200d",
+			expected: "\U0001f468\u200d\U0001f469\u200d\U0001f467 ",
+		},
+	}
+
+	t.Parallel()
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, StripInvisible(tc.str))
+		})
+	}
+}
+
+func TestCollapseSpace(t *testing.T) {
+	const str = "  first \t line \r\n\r\n\n  second  line third  "
+
+	tests := []struct {
+		name     string
+		mode     NewLines
+		expected string
+	}{
+		{
+			name:     "should replace line breaks by space",
+			mode:     DropNewLines,
+			expected: "first line second line third",
+		},
+		{
+			name:     "should keep each line break",
+			mode:     KeepNewLines,
+			expected: "first line\n\n\nsecond line\nthird",
+		},
+		{
+			name:     "should keep single line break",
+			mode:     SingleNewLines,
+			expected: "first line\nsecond line\nthird",
+		},
+	}
+
+	t.Parallel()
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, CollapseSpace(str, tc.mode))
+		})
+	}
+
+	assert.Equal(t, "", CollapseSpace(" \n\t ", KeepNewLines))
+}
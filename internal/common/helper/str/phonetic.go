@@ -0,0 +1,148 @@
+package str
+
+import (
+	"strings"
+	"unicode"
+)
+
+// PhoneticKey returns the key of the string that is the same for words that
+// sound alike, for example names typed by ear: Cyrillic words are encoded by
+// MetaphoneRu, others by Soundex.
+func PhoneticKey(str string) string {
+	words := searchWords(str)
+	keys := make([]string, 0, len(words))
+
+	for _, word := range words {
+		var key string
+
+		if strings.IndexFunc(word, isCyrillic) >= 0 {
+			key = MetaphoneRu(word)
+		} else {
+			key = Soundex(word)
+		}
+
+		if key != "" {
+			keys = append(keys, key)
+		}
+	}
+
+	return strings.Join(keys, " ")
+}
+
+func isCyrillic(r rune) bool {
+	return unicode.Is(unicode.Cyrillic, r)
+}
+
+var soundexCodes = [26]byte{
+	'0', '1', '2', '3', '0', '1', '2', 0, '0', '2', '2', '4', '5',
+	'5', '0', '1', '2', '6', '2', '3', '0', '1', 0, '2', '0', '2',
+}
+
+// Soundex returns the American Soundex code of the Latin word, for example
+// "Robert" and "Rupert" are "R163". Letters of other alphabets are skipped.
+func Soundex(word string) string {
+	code := make([]byte, 0, 4)
+
+	var last byte
+
+	for _, r := range Fold(word) {
+		if r < 'a' || r > 'z' {
+			continue
+		}
+
+		c := soundexCodes[r-'a']
+
+		switch {
+		case len(code) == 0:
+			code = append(code, byte(unicode.ToUpper(r)))
+		case c == 0:
+			// "h" and "w" do not separate letters with the same code.
+			continue
+		case c != '0' && c != last:
+			code = append(code, c)
+		}
+
+		last = c
+
+		if len(code) == 4 {
+			break
+		}
+	}
+
+	if len(code) == 0 {
+		return ""
+	}
+
+	for len(code) < 4 {
+		code = append(code, '0')
+	}
+
+	return string(code)
+}
+
+var (
+	metaphoneVowels = map[rune]rune{
+		'а': 'а', 'о': 'а', 'ы': 'а', 'я': 'а',
+		'е': 'и', 'э': 'и', 'и': 'и',
+		'у': 'у', 'ю': 'у',
+	}
+	metaphoneVoiced = map[rune]rune{
+		'б': 'п', 'в': 'ф', 'г': 'к', 'д': 'т', 'ж': 'ш', 'з': 'с',
+	}
+	metaphoneVoiceless = "пфктшсхцчщ"
+)
+
+// MetaphoneRu returns the phonetic key of the Russian word by the Russian
+// Metaphone of P. Kamyshev without the replacement of surname endings:
+// vowels are reduced, voiced consonants are devoiced before voiceless ones
+// and at the end, soft and hard signs are removed and double letters are
+// collapsed. For example "Петров" and "Питрофф" are "питраф".
+func MetaphoneRu(word string) string {
+	runes := make([]rune, 0, len(word))
+
+	for _, r := range Fold(word) {
+		if r == 'ъ' || r == 'ь' || r < 'а' || r > 'я' {
+			continue
+		}
+
+		// "йо", "ио", "йе" and "ие" sound like "и".
+		if n := len(runes); n > 0 && (r == 'о' || r == 'е') && (runes[n-1] == 'й' || runes[n-1] == 'и') {
+			runes[n-1] = 'и'
+
+			continue
+		}
+
+		runes = append(runes, r)
+	}
+
+	for i, r := range runes {
+		if v, ok := metaphoneVowels[r]; ok {
+			runes[i] = v
+		}
+	}
+
+	for i := len(runes) - 1; i >= 0; i-- {
+		v, ok := metaphoneVoiced[runes[i]]
+		if ok && (i == len(runes)-1 || strings.ContainsRune(metaphoneVoiceless, runes[i+1])) {
+			runes[i] = v
+		}
+	}
+
+	var builder strings.Builder
+
+	for i, r := range runes {
+		// "тс" sounds like "ц".
+		if r == 'т' && i+1 < len(runes) && runes[i+1] == 'с' {
+			r = 'ц'
+			runes[i], runes[i+1] = r, r
+		}
+
+		if i > 0 && runes[i-1] == r {
+			continue
+		}
+
+		builder.WriteRune(r)
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,92 @@
+package str
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSoundex(t *testing.T) {
+	tests := []struct {
+		word     string
+		expected string
+	}{
+		{word: "Robert", expected: "R163"},
+		{word: "Rupert", expected: "R163"},
+		{word: "Rubin", expected: "R150"},
+		{word: "Ashcraft", expected: "A261"},
+		{word: "Tymczak", expected: "T522"},
+		{word: "Pfister", expected: "P236"},
+		{word: "Lee", expected: "L000"},
+		{word: "Müller", expected: "M460"},
+		{word: "Петров", expected: ""},
+	}
+
+	t.Parallel()
+
+	for _, tc := range tests {
+		t.Run(tc.word, func(t *testing.T) {
+			assert.Equal(t, tc.expected, Soundex(tc.word))
+		})
+	}
+}
+
+func TestMetaphoneRu(t *testing.T) {
+	tests := []struct {
+		name     string
+		words    []string
+		expected string
+	}{
+		{
+			name:     "should reduce vowels and devoice final consonant",
+			words:    []string{"Петров", "Питрофф", "ПЕТРОВ"},
+			expected: "питраф",
+		},
+		{
+			name:     "should reduce ио and ие",
+			words:    []string{"Дмитриев", "Дмитреев"},
+			expected: "дмитриф",
+		},
+		{
+			name:     "should fold ё",
+			words:    []string{"Аксёнов", "Аксенов"},
+			expected: "аксинаф",
+		},
+		{
+			name:     "should devoice consonant before voiceless one",
+			words:    []string{"Лодка", "Лотка"},
+			expected: "латка",
+		},
+		{
+			name:     "should collapse double letters",
+			words:    []string{"Горбусс", "Гарбуз"},
+			expected: "гарбус",
+		},
+		{
+			name:     "should replace тс and дс by ц",
+			words:    []string{"Детский", "Децкий"},
+			expected: "дицкий",
+		},
+		{
+			name:     "should skip non cyrillic letters",
+			words:    []string{"Иван-IV", "Ивань"},
+			expected: "иван",
+		},
+	}
+
+	t.Parallel()
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, word := range tc.words {
+				assert.Equal(t, tc.expected, MetaphoneRu(word), word)
+			}
+		})
+	}
+}
+
+func TestPhoneticKey(t *testing.T) {
+	assert.Equal(t, "R163 питраф", PhoneticKey("Robert, Петров!"))
+	assert.Equal(t, PhoneticKey("Rupert Питрофф"), PhoneticKey("Robert Петров"))
+	assert.Equal(t, "", PhoneticKey("123"))
+}
@@ -1,60 +1,27 @@
 package str
 
+//go:generate go run gen_emoji.go
+
 import (
 	"strings"
-)
+	"unicode"
 
-// http://apps.timwhitlock.info/emoji/tables/unicode
-var emojiUnicodeRanges = [][]uint{
-	{0x1F600, 0x1F92F},
-	{0x1F300, 0x1F5FF},
-	{0x1F680, 0x1F6FF},
-	{0x1F190, 0x1F1FF},
-	{0x2702, 0x27B0},
-	{0x1F926, 0x1FA9F},
-	{0x200d, 0x200d},
-	{0x2640, 0x2642},
-	{0x2600, 0x2B55},
-	{0x23cf, 0x23cf},
-	{0x23e9, 0x23e9},
-	{0x231a, 0x231a},
-	{0xfe0f, 0xfe0f},
-}
+	"github.com/rivo/uniseg"
+)
 
-var replaceExceptions = map[rune]struct{}{
-	'$':  {},
-	'&':  {},
-	'+':  {},
-	':':  {},
-	';':  {},
-	'=':  {},
-	'?':  {},
-	'@':  {},
-	'#':  {},
-	'|':  {},
-	'\'': {},
-	'"':  {},
-	'<':  {},
-	'>':  {},
-	'.':  {},
-	'-':  {},
-	'^':  {},
-	'*':  {},
-	'(':  {},
-	'}':  {},
-	'{':  {},
-	',':  {},
-	')':  {},
-	'%':  {},
-	'!':  {},
-}
+const (
+	variationSelector16 = '\uFE0F'
+	combiningKeycap     = '\u20E3'
+)
 
 func ReplaceEmoji2Empty(str string) string {
 	return ReplaceEmoji(str, "")
 }
 
-// ReplaceEmoji replaces all emojis from the string to provided `replace` string.
-// This function can not replace some emojis, but most of all can be replaced.
+// ReplaceEmoji replaces all emojis from the string to provided `replace`
+// string. Emoji sequences (ZWJ families, skin tones, flags, keycaps) are
+// replaced as a whole by the single `replace`. Symbols with the text
+// presentation, for example © or ☺ without the variation selector, are kept.
 func ReplaceEmoji(str, replace string) string {
 	if len(str) == 0 {
 		return ""
@@ -64,26 +31,57 @@ func ReplaceEmoji(str, replace string) string {
 
 	builder.Grow(len(str))
 
-	for _, r := range str {
-		currStr := string(r)
-		code := uint(r)
+	state := -1
 
-		_, ok := replaceExceptions[r]
+	for rest := str; len(rest) > 0; {
+		var cluster string
 
-		for j := 0; j < len(emojiUnicodeRanges) && !ok; j++ {
-			leftBoundary, rightBoundary := emojiUnicodeRanges[j][0], emojiUnicodeRanges[j][1]
+		cluster, rest, _, state = uniseg.FirstGraphemeClusterInString(rest, state)
 
-			if leftBoundary <= code && code <= rightBoundary {
-				builder.Grow(len(replace))
+		if IsEmoji(cluster) {
+			builder.WriteString(replace)
+		} else {
+			builder.WriteString(cluster)
+		}
+	}
 
-				currStr = replace
+	return builder.String()
+}
 
-				break
-			}
+// HasEmoji reports whether the string contains emojis, see ReplaceEmoji.
+func HasEmoji(str string) bool {
+	state := -1
+
+	for rest := str; len(rest) > 0; {
+		var cluster string
+
+		cluster, rest, _, state = uniseg.FirstGraphemeClusterInString(rest, state)
+
+		if IsEmoji(cluster) {
+			return true
 		}
+	}
 
-		builder.WriteString(currStr)
+	return false
+}
+
+// IsEmoji reports whether the grapheme cluster is the emoji by Unicode emoji
+// properties: the character with the emoji presentation (including skin
+// tones and flags), the pictographic character followed by U+FE0F or the
+// keycap sequence.
+func IsEmoji(cluster string) bool {
+	var pictographic, selector bool
+
+	for _, r := range cluster {
+		switch {
+		case unicode.Is(emojiPresentation, r), r == combiningKeycap:
+			return true
+		case r == variationSelector16:
+			selector = true
+		case unicode.Is(extendedPictographic, r):
+			pictographic = true
+		}
 	}
 
-	return builder.String()
+	return pictographic && selector
 }
@@ -50,3 +50,51 @@ func TestReplaceEmoji(t *testing.T) {
 		})
 	}
 }
+
+func TestReplaceEmoji_Should_replace_emoji_sequences_as_whole(t *testing.T) {
+	tests := []struct {
+		name     string
+		str      string
+		expected string
+	}{
+		{
+			name:     "should replace zwj family as single emoji",
+			str:      "family \U0001f468\u200d\U0001f469\u200d\U0001f467\u200d\U0001f466!",
+			expected: "family _!",
+		},
+		{
+			name:     "should replace emoji with skin tone as single emoji",
+			str:      "ok 👍🏽 ok",
+			expected: "ok _ ok",
+		},
+		{
+			name:     "should replace flags by pairs of regional indicators",
+			str:      "🇷🇺🇺🇸 and 🏴\U000e0067\U000e0062\U000e0065\U000e006e\U000e0067\U000e007f",
+			expected: "__ and _",
+		},
+		{
+			name:     "should replace keycaps but keep digits",
+			str:      "1\ufe0f\u20e3 #\ufe0f\u20e3 1 #",
+			expected: "_ _ 1 #",
+		},
+		{
+			name:     "should keep symbols with text presentation",
+			str:      "© ™ ☺ ←, but not ©\ufe0f ☺\ufe0f",
+			expected: "© ™ ☺ ←, but not _ _",
+		},
+		{
+			name:     "should keep letters with combining marks",
+			str:      "café й",
+			expected: "café й",
+		},
+	}
+
+	t.Parallel()
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, ReplaceEmoji(tc.str, "_"))
+			assert.Equal(t, tc.str != tc.expected, HasEmoji(tc.str))
+		})
+	}
+}
@@ -0,0 +1,366 @@
+package str
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Step is the step of the Sanitizer. Rune steps map each rune, consecutive
+// rune steps run in the single pass over the string.
+type Step struct {
+	mapRune func(r rune) rune
+	text    func(str string) string
+}
+
+// Map returns the step mapping each rune like strings.Map, the negative rune
+// is dropped.
+func Map(fn func(r rune) rune) Step {
+	return Step{mapRune: fn}
+}
+
+// Func returns the step of the function over the whole string, for example
+// str.NFC or str.StripInvisible.
+func Func(fn func(str string) string) Step {
+	return Step{text: fn}
+}
+
+// Sanitizer cleans the text by the chain of steps.
+//
+// Example:
+//
+//	var comments = str.NewSanitizer(
+//		str.Func(str.NFC),
+//		str.StripBidi(),
+//		str.Func(str.StripInvisible),
+//		str.StripTags(),
+//		str.MaskProfanity(badWords, '*'),
+//	)
+//
+//	comment = comments.Clean(comment)
+type Sanitizer struct {
+	steps []func(str string) string
+}
+
+func NewSanitizer(steps ...Step) *Sanitizer {
+	s := &Sanitizer{}
+
+	var runes []func(r rune) rune
+
+	flush := func() {
+		if len(runes) == 0 {
+			return
+		}
+
+		s.steps = append(s.steps, mapRunes(runes))
+		runes = nil
+	}
+
+	for _, step := range steps {
+		if step.mapRune != nil {
+			runes = append(runes, step.mapRune)
+
+			continue
+		}
+
+		flush()
+
+		s.steps = append(s.steps, step.text)
+	}
+
+	flush()
+
+	return s
+}
+
+// Clean runs the steps over the string.
+func (s *Sanitizer) Clean(str string) string {
+	for _, step := range s.steps {
+		str = step(str)
+	}
+
+	return str
+}
+
+func mapRunes(fns []func(r rune) rune) func(str string) string {
+	return func(str string) string {
+		return strings.Map(func(r rune) rune {
+			for _, fn := range fns {
+				if r = fn(r); r < 0 {
+					return r
+				}
+			}
+
+			return r
+		}, str)
+	}
+}
+
+// IsBidiControl reports whether the rune changes the direction of the text,
+// such runes make the code or the text look different from what it is
+// (Trojan Source, CVE-2021-42574).
+func IsBidiControl(r rune) bool {
+	switch {
+	// embeddings, overrides and isolates.
+	case r >= '\u202A' && r <= '\u202E', r >= '\u2066' && r <= '\u2069':
+		return true
+	// direction marks.
+	case r == '\u200E', r == '\u200F', r == '\u061C':
+		return true
+	default:
+		return false
+	}
+}
+
+// StripBidi removes bidi controls, see IsBidiControl.
+func StripBidi() Step {
+	return Map(func(r rune) rune {
+		if IsBidiControl(r) {
+			return -1
+		}
+
+		return r
+	})
+}
+
+// EscapeHTML escapes <, >, &, ' and ".
+func EscapeHTML() Step {
+	return Func(html.EscapeString)
+}
+
+// StripTags removes HTML tags and comments, the content of script and style
+// elements is removed too. Unclosed tags and comments are kept as the text.
+// Entities are decoded, so escape the result before rendering it as HTML.
+func StripTags() Step {
+	return Func(stripTags)
+}
+
+//revive:disable:cognitive-complexity
+func stripTags(str string) string {
+	if !strings.ContainsAny(str, "<&") {
+		return str
+	}
+
+	var (
+		builder strings.Builder
+		skipTo  string
+	)
+
+	builder.Grow(len(str))
+
+	for len(str) > 0 {
+		if skipTo != "" {
+			// the content of the unclosed element is kept as the text.
+			if i := strings.Index(strings.ToLower(str), skipTo); i >= 0 {
+				str = str[i:]
+			}
+
+			skipTo = ""
+
+			continue
+		}
+
+		i := strings.IndexByte(str, '<')
+		if i < 0 {
+			builder.WriteString(str)
+
+			break
+		}
+
+		builder.WriteString(str[:i])
+		str = str[i:]
+
+		// "<" not followed by the tag is the text, for example "a < b".
+		if len(str) < 2 || !isTagStart(str[1]) {
+			builder.WriteByte('<')
+			str = str[1:]
+
+			continue
+		}
+
+		end := ">"
+		if strings.HasPrefix(str, "<!--") {
+			end = "-->"
+		}
+
+		// the unclosed tag is the text too, for example "if a<b then c".
+		j := strings.Index(str, end)
+		if j < 0 {
+			builder.WriteByte('<')
+			str = str[1:]
+
+			continue
+		}
+
+		tag := strings.ToLower(str[:j])
+		str = str[j+len(end):]
+
+		for _, name := range []string{"script", "style"} {
+			if strings.HasPrefix(tag, "<"+name) && isTagEnd(tag, len(name)+1) {
+				skipTo = "</" + name
+			}
+		}
+	}
+
+	return html.UnescapeString(builder.String())
+}
+
+//revive:enable:cognitive-complexity
+
+func isTagStart(c byte) bool {
+	return c == '/' || c == '!' || c == '?' || (c|0x20) >= 'a' && (c|0x20) <= 'z'
+}
+
+func isTagEnd(tag string, i int) bool {
+	return i >= len(tag) || tag[i] == ' ' || tag[i] == '/' || tag[i] == '\t' || tag[i] == '\n'
+}
+
+// confusables are Cyrillic and Greek letters that look like Latin ones.
+var confusables = map[rune]rune{
+	'а': 'a', 'в': 'b', 'е': 'e', 'к': 'k', 'м': 'm', 'н': 'h', 'о': 'o', 'р': 'p',
+	'с': 'c', 'т': 't', 'у': 'y', 'х': 'x', 'ѕ': 's', 'і': 'i', 'ј': 'j', 'ԛ': 'q',
+	'ԝ': 'w', 'ɡ': 'g',
+	'А': 'A', 'В': 'B', 'Е': 'E', 'К': 'K', 'М': 'M', 'Н': 'H', 'О': 'O', 'Р': 'P',
+	'С': 'C', 'Т': 'T', 'У': 'Y', 'Х': 'X', 'Ѕ': 'S', 'І': 'I', 'Ј': 'J',
+	'α': 'a', 'ο': 'o', 'ρ': 'p', 'ν': 'v', 'ι': 'i', 'κ': 'k', 'τ': 't', 'χ': 'x',
+	'Α': 'A', 'Β': 'B', 'Ε': 'E', 'Ζ': 'Z', 'Η': 'H', 'Ι': 'I', 'Κ': 'K', 'Μ': 'M',
+	'Ν': 'N', 'Ο': 'O', 'Ρ': 'P', 'Τ': 'T', 'Υ': 'Y', 'Χ': 'X',
+}
+
+// FoldConfusables replaces Cyrillic and Greek letters that look like Latin
+// ones in the words that contain Latin letters, for example "раураl" with
+// Cyrillic "р", "а" and "у" becomes "paypal". Words without Latin letters
+// are kept, so the Russian text is not changed. Full width letters are
+// folded by NFKC, apply it before.
+func FoldConfusables() Step {
+	return Func(func(str string) string {
+		return mapWords(str, func(word string) string {
+			if !hasLatin(word) {
+				return word
+			}
+
+			return strings.Map(func(r rune) rune {
+				if l, ok := confusables[r]; ok {
+					return l
+				}
+
+				return r
+			}, word)
+		})
+	})
+}
+
+func hasLatin(word string) bool {
+	for _, r := range word {
+		if r < utf8.RuneSelf && unicode.IsLetter(r) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// MaskProfanity replaces each rune of the words from the list by the mask.
+// Words are compared case insensitive and after folding of confusables, so
+// "ВаD" matches "bad".
+func MaskProfanity(words []string, mask rune) Step {
+	set := make(map[string]struct{}, len(words))
+
+	for _, w := range words {
+		set[profanityKey(w)] = struct{}{}
+	}
+
+	return Func(func(str string) string {
+		return mapWords(str, func(word string) string {
+			if _, ok := set[profanityKey(word)]; !ok {
+				return word
+			}
+
+			return strings.Repeat(string(mask), utf8.RuneCountInString(word))
+		})
+	})
+}
+
+func profanityKey(word string) string {
+	return strings.Map(func(r rune) rune {
+		if l, ok := confusables[r]; ok {
+			r = l
+		}
+
+		return unicode.ToLower(r)
+	}, word)
+}
+
+// mapWords replaces the words (runs of letters, digits and marks) by fn.
+func mapWords(str string, fn func(word string) string) string {
+	var builder strings.Builder
+
+	builder.Grow(len(str))
+
+	start := -1
+
+	for i, r := range str {
+		inWord := unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.M, r)
+
+		switch {
+		case inWord && start < 0:
+			start = i
+		case !inWord && start >= 0:
+			builder.WriteString(fn(str[start:i]))
+			builder.WriteRune(r)
+
+			start = -1
+		case !inWord:
+			builder.WriteRune(r)
+		}
+	}
+
+	if start >= 0 {
+		builder.WriteString(fn(str[start:]))
+	}
+
+	return builder.String()
+}
+
+// NeutralizeLog escapes line breaks, controls and bidi controls, so the user
+// input written to the log can not fake the next record or hide the text.
+// For example "bob\nERROR admin logged in" becomes "bob\\nERROR admin logged
+// in".
+func NeutralizeLog() Step {
+	return Func(neutralizeLog)
+}
+
+func neutralizeLog(str string) string {
+	i := strings.IndexFunc(str, needsLogEscape)
+	if i < 0 {
+		return str
+	}
+
+	var builder strings.Builder
+
+	builder.Grow(len(str) + 8)
+	builder.WriteString(str[:i])
+
+	for _, r := range str[i:] {
+		switch {
+		case r == '\n':
+			builder.WriteString(`\n`)
+		case r == '\r':
+			builder.WriteString(`\r`)
+		case r == '\t':
+			builder.WriteString(`\t`)
+		case needsLogEscape(r):
+			fmt.Fprintf(&builder, `\u%04X`, r)
+		default:
+			builder.WriteRune(r)
+		}
+	}
+
+	return builder.String()
+}
+
+func needsLogEscape(r rune) bool {
+	return unicode.IsControl(r) || r == '\u2028' || r == '\u2029' || IsBidiControl(r)
+}
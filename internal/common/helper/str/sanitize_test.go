@@ -0,0 +1,267 @@
+package str
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSanitizer(t *testing.T) {
+	tests := []struct {
+		name     string
+		steps    []Step
+		str      string
+		expected string
+	}{
+		{
+			name:     "should return string as is without steps",
+			steps:    nil,
+			str:      "<b>text</b>",
+			expected: "<b>text</b>",
+		},
+		{
+			name: "should run steps in order",
+			steps: []Step{
+				StripTags(),
+				Func(strings.TrimSpace),
+				EscapeHTML(),
+			},
+			str:      " <p>Tom &amp; Jerry</p> ",
+			expected: "Tom &amp; Jerry",
+		},
+		{
+			name: "should drop rune if any rune step drops it",
+			steps: []Step{
+				Map(func(r rune) rune {
+					if r == 'a' {
+						return 'b'
+					}
+
+					return r
+				}),
+				Map(func(r rune) rune {
+					if r == 'b' {
+						return -1
+					}
+
+					return r
+				}),
+				StripBidi(),
+			},
+			str:      "abc\u202ed",
+			expected: "cd",
+		},
+	}
+
+	t.Parallel()
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, NewSanitizer(tc.steps...).Clean(tc.str))
+		})
+	}
+}
+
+func TestSanitizer_Should_fuse_rune_steps(t *testing.T) {
+	s := NewSanitizer(StripBidi(), Map(func(r rune) rune { return r }), EscapeHTML(), StripBidi())
+
+	assert.Len(t, s.steps, 3)
+}
+
+func TestStripBidi(t *testing.T) {
+	tests := []struct {
+		name     string
+		str      string
+		expected string
+	}{
+		{
+			name:     "should remove overrides and isolates",
+			str:      "access\u202e\u2066// admin\u2069\u2066level",
+			expected: "access// adminlevel",
+		},
+		{
+			name:     "should remove direction marks",
+			str:      "\u200eabc\u200f\u061c",
+			expected: "abc",
+		},
+		{
+			name:     "should keep arabic and hebrew text",
+			str:      "مرحبا שלום",
+			expected: "مرحبا שלום",
+		},
+	}
+
+	t.Parallel()
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, NewSanitizer(StripBidi()).Clean(tc.str))
+		})
+	}
+}
+
+func TestStripTags(t *testing.T) {
+	tests := []struct {
+		name     string
+		str      string
+		expected string
+	}{
+		{
+			name:     "should return text without tags as is",
+			str:      "plain text",
+			expected: "plain text",
+		},
+		{
+			name:     "should remove tags and keep text",
+			str:      `<p class="x">Hello, <b>world</b>!<br/></p>`,
+			expected: "Hello, world!",
+		},
+		{
+			name:     "should remove script and style with contents",
+			str:      "a<script>alert('<b>x</b>')</script>b<STYLE>p{}</STYLE >c",
+			expected: "abc",
+		},
+		{
+			name:     "should not treat scripts prefix as script",
+			str:      "<scripts>a</scripts>",
+			expected: "a",
+		},
+		{
+			name:     "should remove comments",
+			str:      "a<!-- <b>hidden</b> -->b",
+			expected: "ab",
+		},
+		{
+			name:     "should keep less than sign that does not start tag",
+			str:      "1 < 2 and 3 <= 4",
+			expected: "1 < 2 and 3 <= 4",
+		},
+		{
+			name:     "should decode entities",
+			str:      "Tom &amp; Jerry &lt;3",
+			expected: "Tom & Jerry <3",
+		},
+		{
+			name:     "should keep unclosed tag as text",
+			str:      "if a<b then c",
+			expected: "if a<b then c",
+		},
+		{
+			name:     "should keep unclosed comment as text",
+			str:      "hi <!-- x",
+			expected: "hi <!-- x",
+		},
+		{
+			name:     "should keep content of unclosed script as text",
+			str:      "text<script>alert(1)",
+			expected: "textalert(1)",
+		},
+	}
+
+	t.Parallel()
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, NewSanitizer(StripTags()).Clean(tc.str))
+		})
+	}
+}
+
+func TestFoldConfusables(t *testing.T) {
+	tests := []struct {
+		name     string
+		str      string
+		expected string
+	}{
+		{
+			name:     "should fold cyrillic letters in latin word",
+			str:      "раураl login",
+			expected: "paypal login",
+		},
+		{
+			name:     "should fold greek letters in latin word",
+			str:      "gοοgle",
+			expected: "google",
+		},
+		{
+			name:     "should keep russian words",
+			str:      "сорт apple",
+			expected: "сорт apple",
+		},
+	}
+
+	t.Parallel()
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, NewSanitizer(FoldConfusables()).Clean(tc.str))
+		})
+	}
+}
+
+func TestMaskProfanity(t *testing.T) {
+	tests := []struct {
+		name     string
+		str      string
+		expected string
+	}{
+		{
+			name:     "should mask whole words",
+			str:      "bad, badge and BAD!",
+			expected: "***, badge and ***!",
+		},
+		{
+			name:     "should mask words with confusables",
+			str:      "ВаD word",
+			expected: "*** word",
+		},
+		{
+			name:     "should mask cyrillic words",
+			str:      "хуже некуда",
+			expected: "**** некуда",
+		},
+	}
+
+	t.Parallel()
+
+	mask := NewSanitizer(MaskProfanity([]string{"bad", "хуже"}, '*'))
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, mask.Clean(tc.str))
+		})
+	}
+}
+
+func TestNeutralizeLog(t *testing.T) {
+	tests := []struct {
+		name     string
+		str      string
+		expected string
+	}{
+		{
+			name:     "should return safe string as is",
+			str:      "bob привет",
+			expected: "bob привет",
+		},
+		{
+			name:     "should escape line breaks",
+			str:      "bob\r\nERROR admin logged in",
+			expected: `bob\r\nERROR admin logged in`,
+		},
+		{
+			name:     "should escape controls, separators and bidi controls",
+			str:      "a\x1b[31m\u2028b\u202ec\t",
+			expected: `a\u001B[31m\u2028b\u202Ec\t`,
+		},
+	}
+
+	t.Parallel()
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, NewSanitizer(NeutralizeLog()).Clean(tc.str))
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package str
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// foldKeep are letters with diacritics that are separate letters of the
+// alphabet, so they are not folded.
+var foldKeep = map[rune]struct{}{
+	'й': {}, 'ў': {},
+}
+
+// foldSpecial are letters that are not decomposed by Unicode.
+var foldSpecial = map[rune]string{
+	'ß': "ss", 'æ': "ae", 'œ': "oe", 'ø': "o", 'ł': "l", 'đ': "d", 'ð': "d",
+	'þ': "th", 'ı': "i", 'ħ': "h", 'ŧ': "t",
+}
+
+// Fold lowercases the string and removes diacritics, so "Ёлка" and "ёлка"
+// are "елка", "Crème Brûlée" is "creme brulee" and "Straße" is "strasse".
+// Letters of the alphabet like "й" are kept. Use it for search keys, not for
+// the text shown to the user.
+func Fold(str string) string {
+	var builder strings.Builder
+
+	builder.Grow(len(str))
+
+	for _, r := range norm.NFC.String(str) {
+		builder.WriteString(foldRune(r))
+	}
+
+	return builder.String()
+}
+
+func foldRune(r rune) string {
+	if r < utf8.RuneSelf {
+		return string(unicode.ToLower(r))
+	}
+
+	r = unicode.ToLower(r)
+
+	if _, ok := foldKeep[r]; ok {
+		return string(r)
+	}
+
+	if s, ok := foldSpecial[r]; ok {
+		return s
+	}
+
+	decomposed := norm.NFKD.String(string(r))
+	if len(decomposed) == utf8.RuneLen(r) {
+		return decomposed
+	}
+
+	return strings.Map(func(r rune) rune {
+		if unicode.Is(unicode.Mn, r) {
+			return -1
+		}
+
+		return unicode.ToLower(r)
+	}, decomposed)
+}
+
+// SearchKey returns the key of the string for the search: words (letters and
+// digits) are folded by Fold and separated by the single space, so
+// " Ёлка-палка! " is "елка палка".
+func SearchKey(str string) string {
+	return strings.Join(searchWords(str), " ")
+}
+
+func searchWords(str string) []string {
+	return strings.FieldsFunc(Fold(str), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
@@ -0,0 +1,76 @@
+package str
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFold(t *testing.T) {
+	tests := []struct {
+		name     string
+		str      string
+		expected string
+	}{
+		{
+			name:     "should lower case and fold ё",
+			str:      "Ёлка ЁЖИК",
+			expected: "елка ежик",
+		},
+		{
+			name:     "should keep й and ў",
+			str:      "Йошкар-Ола Магілёў",
+			expected: "йошкар-ола магілеў",
+		},
+		{
+			name:     "should remove diacritics of latin letters",
+			str:      "Crème Brûlée, Ångström, Łódź",
+			expected: "creme brulee, angstrom, lodz",
+		},
+		{
+			name:     "should replace letters without decomposition",
+			str:      "Straße Æsir Øresund",
+			expected: "strasse aesir oresund",
+		},
+		{
+			name:     "should fold compatibility characters and combining marks",
+			str:      "ﬁle ＡＢ é",
+			expected: "file ab e",
+		},
+	}
+
+	t.Parallel()
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, Fold(tc.str))
+		})
+	}
+}
+
+func TestSearchKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		str      string
+		expected string
+	}{
+		{
+			name:     "should return empty key without words",
+			str:      " -- !",
+			expected: "",
+		},
+		{
+			name:     "should separate folded words by single space",
+			str:      "  Ёлка-палка!  Café\t№5 ",
+			expected: "елка палка cafe no5",
+		},
+	}
+
+	t.Parallel()
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, SearchKey(tc.str))
+		})
+	}
+}
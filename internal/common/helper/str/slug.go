@@ -0,0 +1,195 @@
+package str
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultSlugSeparator = "-"
+	defaultSlugMaxLength = 100
+	defaultSlugAttempts  = 100
+)
+
+var (
+	// ErrEmptySlug is returned by UniqueSlug if the string has no letters
+	// and digits.
+	ErrEmptySlug = errors.New("slug is empty")
+	// ErrSlugTaken is returned by UniqueSlug if all attempts are taken.
+	ErrSlugTaken = errors.New("slug is taken")
+)
+
+type slugOptions struct {
+	table     TranslitTable
+	separator string
+	maxLength int
+	attempts  int
+}
+
+// SlugOption configures Slug and UniqueSlug.
+type SlugOption func(o *slugOptions)
+
+// WithSlugTable sets the transliteration table.
+//
+// Default: Russian.
+func WithSlugTable(t TranslitTable) SlugOption {
+	return func(o *slugOptions) {
+		o.table = t
+	}
+}
+
+// WithSlugSeparator sets the separator of words.
+//
+// Default: defaultSlugSeparator.
+func WithSlugSeparator(sep string) SlugOption {
+	return func(o *slugOptions) {
+		o.separator = sep
+	}
+}
+
+// WithSlugMaxLength sets the max length of the slug in bytes, 0 disables the
+// limit. The slug is cut at the word boundary if possible.
+//
+// Default: defaultSlugMaxLength.
+func WithSlugMaxLength(n int) SlugOption {
+	return func(o *slugOptions) {
+		o.maxLength = n
+	}
+}
+
+// WithSlugAttempts sets the number of slugs checked by UniqueSlug.
+//
+// Default: defaultSlugAttempts.
+func WithSlugAttempts(n int) SlugOption {
+	return func(o *slugOptions) {
+		o.attempts = n
+	}
+}
+
+func makeSlugOptions(opts []SlugOption) slugOptions {
+	o := slugOptions{
+		table:     Russian,
+		separator: defaultSlugSeparator,
+		maxLength: defaultSlugMaxLength,
+		attempts:  defaultSlugAttempts,
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// Slug returns the URL slug of the string: the string is transliterated and
+// folded, Latin letters and digits are kept in lower case, other characters
+// are replaced by the separator. For example "Щи да каша — пища наша!" is
+// "shchi-da-kasha-pishcha-nasha".
+func Slug(str string, opts ...SlugOption) string {
+	o := makeSlugOptions(opts)
+
+	return o.cut(o.slug(str), o.maxLength)
+}
+
+func (o slugOptions) slug(str string) string {
+	var (
+		builder strings.Builder
+		sep     bool
+	)
+
+	builder.Grow(len(str))
+
+	for _, r := range Fold(Translit(str, o.table)) {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			if sep && builder.Len() > 0 {
+				builder.WriteString(o.separator)
+			}
+
+			builder.WriteRune(r)
+
+			sep = false
+
+			continue
+		}
+
+		// apostrophes inside words are dropped: "don't" is "dont".
+		if r != '\'' && r != '’' {
+			sep = true
+		}
+	}
+
+	return builder.String()
+}
+
+// cut returns the prefix of the slug not longer than n bytes that ends at
+// the word boundary if there is one.
+func (o slugOptions) cut(slug string, n int) string {
+	if n <= 0 || len(slug) <= n {
+		return slug
+	}
+
+	if o.separator == "" || strings.HasPrefix(slug[n:], o.separator) {
+		return slug[:n]
+	}
+
+	if i := strings.LastIndex(slug[:n], o.separator); i > 0 {
+		return slug[:i]
+	}
+
+	return slug[:n]
+}
+
+// UniqueSlug returns the slug of the string that does not exist: the slug
+// itself or the slug with the number suffix, for example "news-2", "news-3".
+// The slug is cut to leave the room for the suffix.
+//
+// Example:
+//
+//	slug, err := str.UniqueSlug(ctx, title, func(ctx context.Context, slug string) (bool, error) {
+//		return r.articles.SlugExists(ctx, slug)
+//	})
+//
+// Check the uniqueness by the database constraint too, concurrent calls may
+// return the same slug.
+func UniqueSlug(
+	ctx context.Context,
+	str string,
+	exists func(ctx context.Context, slug string) (bool, error),
+	opts ...SlugOption,
+) (string, error) {
+	o := makeSlugOptions(opts)
+
+	base := o.slug(str)
+	if base == "" {
+		return "", ErrEmptySlug
+	}
+
+	for i := 1; i <= o.attempts; i++ {
+		var suffix string
+
+		if i > 1 {
+			suffix = o.separator + strconv.Itoa(i)
+		}
+
+		slug := base
+		if o.maxLength > 0 {
+			slug = o.cut(base, max(o.maxLength-len(suffix), 1))
+		}
+
+		slug += suffix
+
+		taken, err := exists(ctx, slug)
+		if err != nil {
+			return "", fmt.Errorf("can not check slug %s, %w", slug, err)
+		}
+
+		if !taken {
+			return slug, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: %d attempts", ErrSlugTaken, o.attempts)
+}
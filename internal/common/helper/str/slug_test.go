@@ -0,0 +1,144 @@
+package str
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlug(t *testing.T) {
+	tests := []struct {
+		name     string
+		str      string
+		opts     []SlugOption
+		expected string
+	}{
+		{
+			name:     "should return empty slug without letters",
+			str:      " — !",
+			expected: "",
+		},
+		{
+			name:     "should transliterate and separate words",
+			str:      "Щи да каша — пища наша!",
+			expected: "shchi-da-kasha-pishcha-nasha",
+		},
+		{
+			name:     "should fold latin letters and drop apostrophes",
+			str:      "Don't stop: Crème Brûlée 2024",
+			expected: "dont-stop-creme-brulee-2024",
+		},
+		{
+			name:     "should use table and separator",
+			str:      "Знам'янка, Київ",
+			opts:     []SlugOption{WithSlugTable(Ukrainian), WithSlugSeparator("_")},
+			expected: "znamianka_kyiv",
+		},
+		{
+			name:     "should cut slug at word boundary",
+			str:      "hello big world",
+			opts:     []SlugOption{WithSlugMaxLength(12)},
+			expected: "hello-big",
+		},
+		{
+			name:     "should cut long word",
+			str:      "abcdefgh",
+			opts:     []SlugOption{WithSlugMaxLength(5)},
+			expected: "abcde",
+		},
+		{
+			name:     "should not limit length",
+			str:      strings.Repeat("a ", 100),
+			opts:     []SlugOption{WithSlugMaxLength(0)},
+			expected: strings.TrimSuffix(strings.Repeat("a-", 100), "-"),
+		},
+	}
+
+	t.Parallel()
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, Slug(tc.str, tc.opts...))
+		})
+	}
+}
+
+func TestUniqueSlug(t *testing.T) {
+	errCheck := errors.New("check")
+
+	tests := []struct {
+		name        string
+		str         string
+		taken       []string
+		opts        []SlugOption
+		checkErr    error
+		expected    string
+		expectedErr error
+	}{
+		{
+			name:     "should return slug if it is free",
+			str:      "Новости",
+			expected: "novosti",
+		},
+		{
+			name:     "should add number suffix",
+			str:      "Новости",
+			taken:    []string{"novosti", "novosti-2"},
+			expected: "novosti-3",
+		},
+		{
+			name:     "should cut slug for suffix",
+			str:      "hello world",
+			taken:    []string{"hello-world"},
+			opts:     []SlugOption{WithSlugMaxLength(11)},
+			expected: "hello-2",
+		},
+		{
+			name:        "should return error if slug is empty",
+			str:         "!!!",
+			expectedErr: ErrEmptySlug,
+		},
+		{
+			name:        "should return error if all attempts are taken",
+			str:         "news",
+			taken:       []string{"news", "news-2"},
+			opts:        []SlugOption{WithSlugAttempts(2)},
+			expectedErr: ErrSlugTaken,
+		},
+		{
+			name:        "should return error of check",
+			str:         "news",
+			checkErr:    errCheck,
+			expectedErr: errCheck,
+		},
+	}
+
+	t.Parallel()
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			exists := func(_ context.Context, slug string) (bool, error) {
+				for _, taken := range tc.taken {
+					if taken == slug {
+						return true, nil
+					}
+				}
+
+				return false, tc.checkErr
+			}
+
+			slug, err := UniqueSlug(context.Background(), tc.str, exists, tc.opts...)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, slug)
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package str
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// TranslitTable describes the transliteration of the alphabet to Latin.
+// Tables are values, so copy the maps before changing them, for example to
+// add letters of another alphabet.
+//
+// Example:
+//
+//	table := str.Russian
+//	table.Letters = maps.Clone(str.Russian.Letters)
+//	table.Letters['ў'] = "w"
+type TranslitTable struct {
+	// Letters maps lowercase letters, uppercase letters are mapped by the
+	// lowercase ones. The empty string drops the letter, for example "ь".
+	Letters map[rune]string
+	// Initial overrides Letters at the start of the word, for example "є" is
+	// "ye" at the start of the Ukrainian word and "ie" in other places.
+	Initial map[rune]string
+	// InitialAfter lists letters after which Initial is used as at the start
+	// of the word, for example vowels.
+	InitialAfter string
+	// Pairs overrides Letters for the pairs of lowercase letters, for example
+	// "зг" is "zgh" in Ukrainian.
+	Pairs map[string]string
+	// Final overrides Letters for the endings of the word.
+	Final map[string]string
+}
+
+// Translit transliterates the string by the table. Characters that are not
+// in the table are kept. The case is kept: "Жук" becomes "Zhuk" and "ЖУК"
+// becomes "ZHUK".
+func Translit(str string, table TranslitTable) string {
+	runes := []rune(str)
+
+	var builder strings.Builder
+
+	builder.Grow(len(str))
+
+	for i := 0; i < len(runes); {
+		out, n, ok := table.match(runes, i)
+		if !ok {
+			builder.WriteRune(runes[i])
+			i++
+
+			continue
+		}
+
+		builder.WriteString(matchCase(out, runes, i, n))
+		i += n
+	}
+
+	return builder.String()
+}
+
+// match returns the transliteration of the letters from the position and
+// the number of matched letters.
+func (t TranslitTable) match(runes []rune, i int) (string, int, bool) {
+	var (
+		final   string
+		matched int
+	)
+
+	// the longest ending wins.
+	for ending, out := range t.Final {
+		n := utf8.RuneCountInString(ending)
+		if n > matched && i+n <= len(runes) && !t.inWord(runes, i+n) && lowerEqual(runes[i:i+n], ending) {
+			final, matched = out, n
+		}
+	}
+
+	if matched > 0 {
+		return final, matched, true
+	}
+
+	if i+1 < len(runes) {
+		pair := string([]rune{unicode.ToLower(runes[i]), unicode.ToLower(runes[i+1])})
+
+		if out, ok := t.Pairs[pair]; ok {
+			return out, 2, true
+		}
+	}
+
+	r := unicode.ToLower(runes[i])
+
+	if out, ok := t.Initial[r]; ok && t.isInitial(runes, i) {
+		return out, 1, true
+	}
+
+	out, ok := t.Letters[r]
+
+	return out, 1, ok
+}
+
+func (t TranslitTable) isInitial(runes []rune, i int) bool {
+	if i == 0 || !t.inWord(runes, i-1) {
+		return true
+	}
+
+	return strings.ContainsRune(t.InitialAfter, unicode.ToLower(runes[i-1]))
+}
+
+// inWord reports whether the rune at the position is the part of the word,
+// letters of the table like the Ukrainian apostrophe are the part too.
+func (t TranslitTable) inWord(runes []rune, i int) bool {
+	if i < 0 || i >= len(runes) {
+		return false
+	}
+
+	if unicode.IsLetter(runes[i]) {
+		return true
+	}
+
+	_, ok := t.Letters[runes[i]]
+
+	return ok
+}
+
+func lowerEqual(runes []rune, lower string) bool {
+	for _, r := range runes {
+		l, size := utf8.DecodeRuneInString(lower)
+		if unicode.ToLower(r) != l {
+			return false
+		}
+
+		lower = lower[size:]
+	}
+
+	return true
+}
+
+// matchCase applies the case of n source letters from the position to the
+// transliteration: the upper case word stays upper case, the capitalized
+// word stays capitalized.
+func matchCase(out string, runes []rune, i, n int) string {
+	if out == "" || !unicode.IsUpper(runes[i]) {
+		return out
+	}
+
+	next := i + n
+
+	switch {
+	case n > 1 && unicode.IsUpper(runes[i+1]),
+		next < len(runes) && unicode.IsUpper(runes[next]),
+		(next >= len(runes) || !unicode.IsLetter(runes[next])) && i > 0 && unicode.IsUpper(runes[i-1]):
+		return strings.ToUpper(out)
+	default:
+		first, size := utf8.DecodeRuneInString(out)
+
+		return string(unicode.ToUpper(first)) + out[size:]
+	}
+}
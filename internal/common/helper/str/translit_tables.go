@@ -0,0 +1,94 @@
+package str
+
+// Russian is the transliteration of Russian used in passports (ICAO Doc
+// 9303), it has no diacritics and is good for URLs.
+var Russian = TranslitTable{
+	Letters: map[rune]string{
+		'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e",
+		'ж': "zh", 'з': "z", 'и': "i", 'й': "i", 'к': "k", 'л': "l", 'м': "m",
+		'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u",
+		'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch",
+		'ъ': "ie", 'ы': "y", 'ь': "", 'э': "e", 'ю': "iu", 'я': "ia",
+	},
+}
+
+// RussianGOST is the transliteration of Russian by GOST 7.79-2000 system B,
+// it has no diacritics, but uses backticks, for example "ы" is "y`".
+var RussianGOST = TranslitTable{
+	Letters: map[rune]string{
+		'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo",
+		'ж': "zh", 'з': "z", 'и': "i", 'й': "j", 'к': "k", 'л': "l", 'м': "m",
+		'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u",
+		'ф': "f", 'х': "x", 'ц': "cz", 'ч': "ch", 'ш': "sh", 'щ': "shh",
+		'ъ': "``", 'ы': "y`", 'ь': "`", 'э': "e`", 'ю': "yu", 'я': "ya",
+	},
+	// "ц" is "c" before "е", "и", "ы" and "й".
+	Pairs: map[string]string{
+		"це": "ce", "ци": "ci", "цы": "cy`", "цй": "cj",
+	},
+}
+
+// RussianISO9 is the transliteration of Russian by ISO 9:1995 (GOST 7.79-2000
+// system A), each letter has the single Latin letter with diacritics, so the
+// text can be transliterated back.
+var RussianISO9 = TranslitTable{
+	Letters: map[rune]string{
+		'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "ë",
+		'ж': "ž", 'з': "z", 'и': "i", 'й': "j", 'к': "k", 'л': "l", 'м': "m",
+		'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u",
+		'ф': "f", 'х': "h", 'ц': "c", 'ч': "č", 'ш': "š", 'щ': "ŝ",
+		'ъ': "ʺ", 'ы': "y", 'ь': "ʹ", 'э': "è", 'ю': "û", 'я': "â",
+	},
+}
+
+// Ukrainian is the official transliteration of Ukrainian (the resolution of
+// the Cabinet of Ministers No. 55 of 2010).
+var Ukrainian = TranslitTable{
+	Letters: map[rune]string{
+		'а': "a", 'б': "b", 'в': "v", 'г': "h", 'ґ': "g", 'д': "d", 'е': "e",
+		'є': "ie", 'ж': "zh", 'з': "z", 'и': "y", 'і': "i", 'ї': "i", 'й': "i",
+		'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o", 'п': "p", 'р': "r",
+		'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch",
+		'ш': "sh", 'щ': "shch", 'ь': "", 'ю': "iu", 'я': "ia",
+		'\'': "", '’': "", 'ʼ': "",
+	},
+	Initial: map[rune]string{
+		'є': "ye", 'ї': "yi", 'й': "y", 'ю': "yu", 'я': "ya",
+	},
+	Pairs: map[string]string{
+		"зг": "zgh",
+	},
+}
+
+// Belarusian is the transliteration of Belarusian by BGN/PCGN 1979 without
+// diacritics.
+var Belarusian = TranslitTable{
+	Letters: map[rune]string{
+		'а': "a", 'б': "b", 'в': "v", 'г': "h", 'ґ': "g", 'д': "d", 'е': "e",
+		'ё': "yo", 'ж': "zh", 'з': "z", 'і': "i", 'й': "y", 'к': "k", 'л': "l",
+		'м': "m", 'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t",
+		'у': "u", 'ў': "w", 'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh",
+		'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
+		'\'': "", '’': "", 'ʼ': "",
+	},
+	Initial: map[rune]string{
+		'е': "ye",
+	},
+	InitialAfter: "аеёіоуыэюяйўь'’ʼ",
+}
+
+// Bulgarian is the official transliteration of Bulgarian (the Transliteration
+// Act of 2009).
+var Bulgarian = TranslitTable{
+	Letters: map[rune]string{
+		'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ж': "zh",
+		'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n",
+		'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f",
+		'х': "h", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "sht", 'ъ': "a",
+		'ь': "y", 'ю': "yu", 'я': "ya",
+	},
+	// "ия" at the end of the word is "ia": "София" becomes "Sofia".
+	Final: map[string]string{
+		"ия": "ia",
+	},
+}
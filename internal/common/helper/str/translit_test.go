@@ -0,0 +1,90 @@
+package str
+
+import (
+	"maps"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTranslit(t *testing.T) {
+	tests := []struct {
+		name     string
+		table    TranslitTable
+		str      string
+		expected string
+	}{
+		{
+			name:     "should transliterate russian by icao",
+			table:    Russian,
+			str:      "Щукин Юрий Ёлкин, объём",
+			expected: "Shchukin Iurii Elkin, obieem",
+		},
+		{
+			name:     "should keep upper case words",
+			table:    Russian,
+			str:      "ЖУК и Ж",
+			expected: "ZHUK i Zh",
+		},
+		{
+			name:     "should keep characters that are not in the table",
+			table:    Russian,
+			str:      "Go 1.24 — язык",
+			expected: "Go 1.24 — iazyk",
+		},
+		{
+			name:     "should transliterate russian by gost with context of ц",
+			table:    RussianGOST,
+			str:      "Цыплёнок цех Щукин объём",
+			expected: "Cy`plyonok cex Shhukin ob``yom",
+		},
+		{
+			name:     "should transliterate russian by iso 9",
+			table:    RussianISO9,
+			str:      "Щукин Юрий ЖУК объём",
+			expected: "Ŝukin Ûrij ŽUK obʺëm",
+		},
+		{
+			name:     "should transliterate ukrainian with initial letters",
+			table:    Ukrainian,
+			str:      "Єнакієве Їжакевич Йосипівка Юрій Яготин Стрий",
+			expected: "Yenakiieve Yizhakevych Yosypivka Yurii Yahotyn Stryi",
+		},
+		{
+			name:     "should transliterate ukrainian pairs and apostrophe",
+			table:    Ukrainian,
+			str:      "Згурський Знам'янка Короп’є",
+			expected: "Zghurskyi Znamianka Koropie",
+		},
+		{
+			name:     "should transliterate belarusian with initial letters after vowels",
+			table:    Belarusian,
+			str:      "Ельскі Магілёў Рэчыца Заелле",
+			expected: "Yelski Mahilyow Rechytsa Zayelle",
+		},
+		{
+			name:     "should transliterate bulgarian endings",
+			table:    Bulgarian,
+			str:      "София Щастие Ия ИЯ",
+			expected: "Sofia Shtastie Ia IA",
+		},
+	}
+
+	t.Parallel()
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, Translit(tc.str, tc.table))
+		})
+	}
+}
+
+func TestTranslit_Should_use_extended_table(t *testing.T) {
+	table := Russian
+	table.Letters = maps.Clone(Russian.Letters)
+	table.Letters['ў'] = "w"
+
+	assert.Equal(t, "Magilew", Translit("Магилёў", table))
+	assert.Equal(t, "Mahilёў", Translit("Mahilёў", TranslitTable{}))
+	assert.NotContains(t, Russian.Letters, 'ў')
+}
@@ -0,0 +1,52 @@
+package str
+
+import "github.com/rivo/uniseg"
+
+// TruncateGraphemes returns the first n grapheme clusters of the string, so
+// emojis and letters with combining marks are not cut in the middle.
+func TruncateGraphemes(str string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+
+	var (
+		end   int
+		state = -1
+	)
+
+	for rest := str; len(rest) > 0 && n > 0; n-- {
+		var cluster string
+
+		cluster, rest, _, state = uniseg.FirstGraphemeClusterInString(rest, state)
+		end += len(cluster)
+	}
+
+	return str[:end]
+}
+
+// TruncateBytes returns the longest prefix of the string not longer than n
+// bytes that ends at the boundary of the grapheme cluster. Use it for
+// columns and headers limited in bytes.
+func TruncateBytes(str string, n int) string {
+	if len(str) <= n {
+		return str
+	}
+
+	var (
+		end   int
+		state = -1
+	)
+
+	for rest := str; len(rest) > 0; {
+		var cluster string
+
+		cluster, rest, _, state = uniseg.FirstGraphemeClusterInString(rest, state)
+		if end+len(cluster) > n {
+			break
+		}
+
+		end += len(cluster)
+	}
+
+	return str[:end]
+}
@@ -0,0 +1,52 @@
+package str
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTruncateGraphemes(t *testing.T) {
+	tests := []struct {
+		name     string
+		str      string
+		n        int
+		expected string
+	}{
+		{name: "should keep short string", str: "abc", n: 5, expected: "abc"},
+		{name: "should return empty string for zero", str: "abc", n: 0, expected: ""},
+		{name: "should count cyrillic letters", str: "привет", n: 3, expected: "при"},
+		{name: "should not split combining marks", str: "ééé", n: 2, expected: "éé"},
+		{name: "should not split emoji sequences", str: "👍🏽🇷🇺ab", n: 2, expected: "👍🏽🇷🇺"},
+	}
+
+	t.Parallel()
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, TruncateGraphemes(tc.str, tc.n))
+		})
+	}
+}
+
+func TestTruncateBytes(t *testing.T) {
+	tests := []struct {
+		name     string
+		str      string
+		n        int
+		expected string
+	}{
+		{name: "should keep short string", str: "abc", n: 3, expected: "abc"},
+		{name: "should not split utf8 sequence", str: "при", n: 5, expected: "пр"},
+		{name: "should not split emoji with skin tone", str: "a👍🏽", n: 6, expected: "a"},
+		{name: "should return empty string if first grapheme is longer", str: "👍", n: 2, expected: ""},
+	}
+
+	t.Parallel()
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, TruncateBytes(tc.str, tc.n))
+		})
+	}
+}
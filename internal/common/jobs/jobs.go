@@ -0,0 +1,172 @@
+// Package jobs is the queue of background jobs stored in the Postgres table
+// (see db/migrations/*_jobs.up.sql). Jobs are enqueued in the transaction of
+// the caller, so the job exists only if the transaction is committed.
+// Workers claim jobs by FOR UPDATE SKIP LOCKED, failed jobs are retried with
+// the exponential backoff until MaxAttempts, then the job becomes dead.
+//
+// Jobs are delivered at least once, handlers must be idempotent.
+//
+// Example:
+//
+//	var SendEmail = jobs.NewKind[SendEmailPayload]("send_email")
+//
+//	jobs.Handle(c.Jobs, SendEmail, func(ctx context.Context, job jobs.Job[SendEmailPayload]) error {
+//		return mailer.Send(ctx, job.Payload.To, job.Payload.Body)
+//	})
+//
+//	err := tx.Manager().Do(ctx, func(ctx context.Context) error {
+//		...
+//		_, err := jobs.Enqueue(ctx, c.Jobs, SendEmail, payload, jobs.Delay(time.Minute))
+//
+//		return err
+//	})
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/Melenium2/go-template/internal/common/erx"
+	"github.com/Melenium2/go-template/internal/common/tenant"
+	"github.com/Melenium2/go-template/internal/common/tx"
+)
+
+// ErrDuplicate is returned by Enqueue if the job of the kind with the same
+// unique key is waiting or running.
+var ErrDuplicate = fmt.Errorf("%w: job is already enqueued", erx.ErrConflict)
+
+// Status of the job.
+const (
+	StatusPending = "pending"
+	StatusRunning = "running"
+	StatusDead    = "dead"
+)
+
+// Kind is the type of the job with the payload T, the payload is stored as
+// JSON.
+type Kind[T any] struct {
+	Name string
+}
+
+// NewKind returns the kind of the job, the name must be unique.
+func NewKind[T any](name string) Kind[T] {
+	return Kind[T]{Name: name}
+}
+
+// Job is the claimed job.
+type Job[T any] struct {
+	ID      int64
+	Kind    string
+	Payload T
+	// Attempt is the number of the current attempt, starts from 1.
+	Attempt     int
+	MaxAttempts int
+	CreatedAt   time.Time
+}
+
+type options struct {
+	runAt       *time.Time
+	uniqueKey   *string
+	maxAttempts int
+}
+
+// Option of the enqueued job.
+type Option func(o *options)
+
+// RunAt schedules the job at the time.
+func RunAt(t time.Time) Option {
+	return func(o *options) {
+		o.runAt = &t
+	}
+}
+
+// Delay schedules the job after the delay.
+func Delay(d time.Duration) Option {
+	return RunAt(time.Now().Add(d))
+}
+
+// UniqueKey skips the job with ErrDuplicate if the job of the same kind
+// with the key is waiting or running.
+func UniqueKey(key string) Option {
+	return func(o *options) {
+		o.uniqueKey = &key
+	}
+}
+
+// MaxAttempts sets the number of attempts before the job becomes dead.
+//
+// Default: Config.MaxAttempts.
+func MaxAttempts(n int) Option {
+	return func(o *options) {
+		o.maxAttempts = n
+	}
+}
+
+// Enqueue adds the job to the queue in the transaction from the context. The
+// tenant from the context is stored with the job and restored for the
+// handler. Returns the ID of the job.
+func Enqueue[T any](ctx context.Context, q *Queue, kind Kind[T], payload T, opts ...Option) (int64, error) {
+	o := options{maxAttempts: q.cfg.MaxAttempts}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return 0, err
+	}
+
+	var tenantID *string
+
+	if id, ok := tenant.ID(ctx); ok {
+		tenantID = &id
+	}
+
+	query := fmt.Sprintf(`INSERT INTO %s (kind, payload, run_at, max_attempts, unique_key, tenant_id)
+		VALUES ($1, $2, coalesce($3::timestamptz, now()), $4, $5, $6)
+		ON CONFLICT (kind, unique_key) WHERE unique_key IS NOT NULL AND status IN ('pending', 'running')
+		DO NOTHING
+		RETURNING id`, q.table)
+
+	var id int64
+
+	err = sqlx.GetContext(ctx, tx.Manager().Conn(ctx), &id, query,
+		kind.Name, string(raw), o.runAt, o.maxAttempts, o.uniqueKey, tenantID,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, ErrDuplicate
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	if o.runAt == nil {
+		// wakes the workers after commit, see Queue.Subscribe.
+		_, err = tx.Manager().Conn(ctx).ExecContext(ctx, "SELECT pg_notify($1, $2)", q.cfg.Channel, kind.Name)
+	}
+
+	return id, err
+}
+
+// permanent is the error that makes the job dead without retries.
+type permanent struct {
+	err error
+}
+
+func (p permanent) Error() string { return p.err.Error() }
+
+func (p permanent) Unwrap() error { return p.err }
+
+// Permanent wraps the error of the handler, the job becomes dead without
+// retries. For example, if the payload is invalid.
+func Permanent(err error) error {
+	return permanent{err: err}
+}
@@ -0,0 +1,167 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/Melenium2/go-template/internal/common/tenant"
+	"github.com/Melenium2/go-template/internal/common/tx"
+)
+
+type email struct {
+	To string `json:"to"`
+}
+
+var sendEmail = NewKind[email]("send_email")
+
+type JobsSuite struct {
+	suite.Suite
+
+	sqlMock sqlmock.Sqlmock
+	queue   *Queue
+}
+
+func (suite *JobsSuite) SetupSuite() {
+	db, mock, _ := sqlmock.New()
+
+	suite.sqlMock = mock
+	suite.queue = New(sqlx.NewDb(db, "postgres"), Config{MaxAttempts: 3})
+	tx.SetupManager(sqlx.NewDb(db, "postgres"))
+}
+
+func (suite *JobsSuite) TearDownTest() {
+	suite.Assert().NoError(suite.sqlMock.ExpectationsWereMet())
+}
+
+func (suite *JobsSuite) expectClaim(attempts int, payload string) {
+	suite.sqlMock.ExpectQuery(`UPDATE "jobs" SET status = 'running'.*WHERE kind IN \(\$2\).*FOR UPDATE SKIP LOCKED`).
+		WithArgs(defaultTimeout.Seconds(), "send_email").
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "kind", "payload", "attempts", "max_attempts", "tenant_id", "created_at",
+		}).AddRow(1, "send_email", []byte(payload), attempts, 3, "acme", time.Now()))
+}
+
+func (suite *JobsSuite) TestEnqueue_Should_insert_job_in_transaction_and_notify_workers() {
+	suite.sqlMock.ExpectBegin()
+	suite.sqlMock.ExpectQuery(`INSERT INTO "jobs"`).
+		WithArgs("send_email", `{"to":"a@b.c"}`, nil, 3, nil, "acme").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+	suite.sqlMock.ExpectExec(`SELECT pg_notify`).WithArgs("jobs", "send_email").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.sqlMock.ExpectCommit()
+
+	ctx, err := tenant.WithID(context.Background(), "acme")
+	suite.Require().NoError(err)
+
+	err = tx.Manager().Do(ctx, func(ctx context.Context) error {
+		id, err := Enqueue(ctx, suite.queue, sendEmail, email{To: "a@b.c"})
+		suite.Assert().Equal(int64(7), id)
+
+		return err
+	})
+	suite.Assert().NoError(err)
+}
+
+func (suite *JobsSuite) TestEnqueue_Should_return_duplicate_for_same_unique_key() {
+	runAt := time.Now().Add(time.Hour)
+
+	suite.sqlMock.ExpectQuery(`INSERT INTO "jobs"`).
+		WithArgs("send_email", `{"to":"a@b.c"}`, runAt, 5, "a@b.c", nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err := Enqueue(context.Background(), suite.queue, sendEmail, email{To: "a@b.c"},
+		RunAt(runAt), UniqueKey("a@b.c"), MaxAttempts(5))
+	suite.Assert().ErrorIs(err, ErrDuplicate)
+}
+
+func (suite *JobsSuite) TestRunNext_Should_delete_finished_job() {
+	var got Job[email]
+
+	Handle(suite.queue, sendEmail, func(ctx context.Context, job Job[email]) error {
+		got = job
+
+		id, _ := tenant.ID(ctx)
+		suite.Assert().Equal("acme", id)
+
+		return nil
+	})
+
+	suite.expectClaim(1, `{"to":"a@b.c"}`)
+	suite.sqlMock.ExpectExec(`DELETE FROM "jobs" WHERE id = \$1`).WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	found, err := suite.queue.RunNext(context.Background())
+	suite.Require().NoError(err)
+	suite.Assert().True(found)
+	suite.Assert().Equal(email{To: "a@b.c"}, got.Payload)
+	suite.Assert().Equal(1, got.Attempt)
+}
+
+func (suite *JobsSuite) TestRunNext_Should_retry_failed_job_and_make_it_dead_after_max_attempts() {
+	Handle(suite.queue, sendEmail, func(context.Context, Job[email]) error {
+		return errors.New("smtp is down")
+	})
+
+	suite.expectClaim(1, `{}`)
+	suite.sqlMock.ExpectExec(`UPDATE "jobs" SET status = \$2`).
+		WithArgs(1, StatusPending, sqlmock.AnyArg(), "smtp is down").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	_, err := suite.queue.RunNext(context.Background())
+	suite.Require().NoError(err)
+
+	suite.expectClaim(3, `{}`)
+	suite.sqlMock.ExpectExec(`UPDATE "jobs" SET status = \$2`).
+		WithArgs(1, StatusDead, sqlmock.AnyArg(), "smtp is down").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	_, err = suite.queue.RunNext(context.Background())
+	suite.Require().NoError(err)
+}
+
+func (suite *JobsSuite) TestRunNext_Should_make_job_with_invalid_payload_dead() {
+	Handle(suite.queue, sendEmail, func(context.Context, Job[email]) error {
+		return nil
+	})
+
+	suite.expectClaim(1, `broken`)
+	suite.sqlMock.ExpectExec(`UPDATE "jobs" SET status = \$2`).
+		WithArgs(1, StatusDead, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	_, err := suite.queue.RunNext(context.Background())
+	suite.Require().NoError(err)
+}
+
+func (suite *JobsSuite) TestRunNext_Should_release_job_on_shutdown() {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	Handle(suite.queue, sendEmail, func(ctx context.Context, _ Job[email]) error {
+		cancel()
+
+		return ctx.Err()
+	})
+
+	suite.expectClaim(1, `{}`)
+	suite.sqlMock.ExpectExec(`UPDATE "jobs" SET status = 'pending', attempts = attempts - 1`).WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	_, err := suite.queue.RunNext(ctx)
+	suite.Require().NoError(err)
+}
+
+func (suite *JobsSuite) TestBackoff() {
+	suite.Assert().Equal(time.Second, suite.queue.backoff(1))
+	suite.Assert().Equal(4*time.Second, suite.queue.backoff(3))
+	suite.Assert().Equal(time.Hour, suite.queue.backoff(100))
+}
+
+func TestJobsSuite(t *testing.T) {
+	suite.Run(t, new(JobsSuite))
+}
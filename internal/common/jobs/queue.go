@@ -0,0 +1,390 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/Melenium2/go-template/internal/common/tenant"
+	"github.com/Melenium2/go-template/pkg/psql"
+)
+
+const (
+	defaultTable        = "jobs"
+	defaultChannel      = "jobs"
+	defaultConcurrency  = 4
+	defaultPollInterval = time.Second
+	defaultTimeout      = 5 * time.Minute
+	defaultMaxAttempts  = 10
+	defaultRetryBase    = time.Second
+	defaultRetryMax     = time.Hour
+)
+
+// Config of the queue.
+type Config struct {
+	// Table is the name of the jobs table, may be schema qualified.
+	//
+	// Default: jobs.
+	Table string
+	// Channel is notified by Enqueue to wake the workers.
+	//
+	// Default: jobs.
+	Channel string
+	// Concurrency is the number of jobs run at the same time by the replica.
+	//
+	// Default: defaultConcurrency.
+	Concurrency int
+	// PollInterval is the interval of checks for new jobs.
+	//
+	// Default: defaultPollInterval.
+	PollInterval time.Duration
+	// Timeout of the single attempt, the job is claimed again by another
+	// worker after the timeout.
+	//
+	// Default: defaultTimeout.
+	Timeout time.Duration
+	// MaxAttempts of the jobs enqueued without MaxAttempts option.
+	//
+	// Default: defaultMaxAttempts.
+	MaxAttempts int
+	// RetryBase and RetryMax limit the exponential backoff of the retries:
+	// RetryBase * 2^(attempt-1), but not longer than RetryMax.
+	//
+	// Default: defaultRetryBase, defaultRetryMax.
+	RetryBase time.Duration
+	RetryMax  time.Duration
+}
+
+func (c Config) withDefaults() Config {
+	if c.Table == "" {
+		c.Table = defaultTable
+	}
+
+	if c.Channel == "" {
+		c.Channel = defaultChannel
+	}
+
+	if c.Concurrency <= 0 {
+		c.Concurrency = defaultConcurrency
+	}
+
+	if c.PollInterval <= 0 {
+		c.PollInterval = defaultPollInterval
+	}
+
+	if c.Timeout <= 0 {
+		c.Timeout = defaultTimeout
+	}
+
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = defaultMaxAttempts
+	}
+
+	if c.RetryBase <= 0 {
+		c.RetryBase = defaultRetryBase
+	}
+
+	if c.RetryMax <= 0 {
+		c.RetryMax = defaultRetryMax
+	}
+
+	return c
+}
+
+type handler func(ctx context.Context, r record) error
+
+// Queue runs the handlers of the jobs.
+type Queue struct {
+	db    *sqlx.DB
+	cfg   Config
+	table string
+
+	mu       sync.RWMutex
+	handlers map[string]handler
+
+	wake chan struct{}
+}
+
+// New returns the queue over the database. Workers use the database
+// directly, not the transactions of tx.Manager, so the queue does not
+// depend on the tenant of the context.
+func New(db *sqlx.DB, cfg Config) *Queue {
+	cfg = cfg.withDefaults()
+
+	return &Queue{
+		db:       db,
+		cfg:      cfg,
+		table:    pgx.Identifier(strings.Split(cfg.Table, ".")).Sanitize(),
+		handlers: make(map[string]handler),
+		wake:     make(chan struct{}, cfg.Concurrency),
+	}
+}
+
+// Handle registers the handler of the kind. Register handlers before Run.
+func Handle[T any](q *Queue, kind Kind[T], fn func(ctx context.Context, job Job[T]) error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.handlers[kind.Name] = func(ctx context.Context, r record) error {
+		job := Job[T]{
+			ID:          r.ID,
+			Kind:        r.Kind,
+			Attempt:     r.Attempts,
+			MaxAttempts: r.MaxAttempts,
+			CreatedAt:   r.CreatedAt,
+		}
+
+		if err := json.Unmarshal(r.Payload, &job.Payload); err != nil {
+			return Permanent(fmt.Errorf("can not decode payload, %w", err))
+		}
+
+		return fn(ctx, job)
+	}
+}
+
+// Subscribe wakes the workers by notifications of Enqueue, so new jobs are
+// run without waiting for PollInterval. Call it after the handlers are
+// registered, the queue without handlers does not subscribe.
+func (q *Queue) Subscribe(l *psql.Listener) (unsubscribe func()) {
+	if len(q.kinds()) == 0 {
+		return func() {}
+	}
+
+	return l.Subscribe(q.cfg.Channel, func(context.Context, *pgconn.Notification) {
+		select {
+		case q.wake <- struct{}{}:
+		default:
+		}
+	})
+}
+
+func (q *Queue) kinds() []string {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	kinds := make([]string, 0, len(q.handlers))
+
+	for k := range q.handlers {
+		kinds = append(kinds, k)
+	}
+
+	return kinds
+}
+
+func (q *Queue) handler(kind string) (handler, bool) {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	h, ok := q.handlers[kind]
+
+	return h, ok
+}
+
+// Run runs Concurrency workers until the context is canceled. Jobs of the
+// kinds without handlers are not claimed. Running jobs are canceled on
+// shutdown and returned to the queue without counting the attempt.
+func (q *Queue) Run(ctx context.Context) error {
+	if len(q.kinds()) == 0 {
+		<-ctx.Done()
+
+		return nil
+	}
+
+	var wg sync.WaitGroup
+
+	wg.Add(q.cfg.Concurrency)
+
+	for range q.cfg.Concurrency {
+		go func() {
+			defer wg.Done()
+
+			q.work(ctx)
+		}()
+	}
+
+	wg.Wait()
+
+	return nil
+}
+
+func (q *Queue) work(ctx context.Context) {
+	ticker := time.NewTicker(q.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		found, err := q.RunNext(ctx)
+		if err != nil && ctx.Err() == nil {
+			slog.Error("can not run job", slog.String("error", err.Error()))
+		}
+
+		if found && err == nil {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-q.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+type record struct {
+	ID          int64          `db:"id"`
+	Kind        string         `db:"kind"`
+	Payload     []byte         `db:"payload"`
+	Attempts    int            `db:"attempts"`
+	MaxAttempts int            `db:"max_attempts"`
+	TenantID    sql.NullString `db:"tenant_id"`
+	CreatedAt   time.Time      `db:"created_at"`
+}
+
+// RunNext claims and runs the single job. False is returned if there are
+// no jobs to run.
+func (q *Queue) RunNext(ctx context.Context) (bool, error) {
+	r, err := q.claim(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	err = q.run(ctx, r)
+
+	// the state of the job is saved even if the context is canceled.
+	saveCtx := context.WithoutCancel(ctx)
+
+	switch {
+	case err == nil:
+		_, err = q.db.ExecContext(saveCtx, fmt.Sprintf("DELETE FROM %s WHERE id = $1", q.table), r.ID)
+	case ctx.Err() != nil:
+		err = q.release(saveCtx, r)
+	default:
+		err = q.fail(saveCtx, r, err)
+	}
+
+	return true, err
+}
+
+func (q *Queue) claim(ctx context.Context) (record, error) {
+	var r record
+
+	kinds := q.kinds()
+	if len(kinds) == 0 {
+		return r, sql.ErrNoRows
+	}
+
+	placeholders := make([]string, 0, len(kinds))
+	args := []any{q.cfg.Timeout.Seconds()}
+
+	for _, k := range kinds {
+		args = append(args, k)
+		placeholders = append(placeholders, fmt.Sprintf("$%d", len(args)))
+	}
+
+	query := fmt.Sprintf(`UPDATE %[1]s
+		SET status = 'running', attempts = attempts + 1,
+			locked_until = now() + make_interval(secs => $1), updated_at = now()
+		WHERE id = (
+			SELECT id FROM %[1]s
+			WHERE kind IN (%[2]s) AND run_at <= now()
+				AND (status = 'pending' OR (status = 'running' AND locked_until < now()))
+			ORDER BY run_at, id
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, kind, payload, attempts, max_attempts, tenant_id, created_at`,
+		q.table, strings.Join(placeholders, ", "),
+	)
+
+	err := q.db.GetContext(ctx, &r, query, args...)
+
+	return r, err
+}
+
+// run calls the handler with the tenant of the job and recovers panics, so
+// the attempt is counted.
+func (q *Queue) run(ctx context.Context, r record) (err error) {
+	h, ok := q.handler(r.Kind)
+	if !ok {
+		return fmt.Errorf("handler of %s is not registered", r.Kind)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, q.cfg.Timeout)
+	defer cancel()
+
+	if r.TenantID.Valid {
+		if ctx, err = tenant.WithID(ctx, r.TenantID.String); err != nil {
+			return Permanent(err)
+		}
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			debug.PrintStack()
+
+			err = fmt.Errorf("recovered after panic in job %s, %v", r.Kind, p)
+		}
+	}()
+
+	return h(ctx, r)
+}
+
+// fail schedules the retry or makes the job dead.
+func (q *Queue) fail(ctx context.Context, r record, jobErr error) error {
+	status := StatusPending
+	if r.Attempts >= r.MaxAttempts || errors.As(jobErr, new(permanent)) {
+		status = StatusDead
+	}
+
+	slog.Error("job is failed",
+		slog.Int64("id", r.ID),
+		slog.String("kind", r.Kind),
+		slog.Int("attempt", r.Attempts),
+		slog.String("status", status),
+		slog.String("error", jobErr.Error()),
+	)
+
+	query := fmt.Sprintf(`UPDATE %s
+		SET status = $2, run_at = $3, last_error = $4, locked_until = NULL, updated_at = now()
+		WHERE id = $1`, q.table)
+
+	_, err := q.db.ExecContext(ctx, query, r.ID, status, time.Now().Add(q.backoff(r.Attempts)), jobErr.Error())
+
+	return err
+}
+
+// release returns the job interrupted by shutdown to the queue.
+func (q *Queue) release(ctx context.Context, r record) error {
+	query := fmt.Sprintf(`UPDATE %s
+		SET status = 'pending', attempts = attempts - 1, locked_until = NULL, updated_at = now()
+		WHERE id = $1`, q.table)
+
+	_, err := q.db.ExecContext(ctx, query, r.ID)
+
+	return err
+}
+
+func (q *Queue) backoff(attempt int) time.Duration {
+	d := q.cfg.RetryBase
+
+	for i := 1; i < attempt && d < q.cfg.RetryMax; i++ {
+		d *= 2
+	}
+
+	return min(d, q.cfg.RetryMax)
+}
@@ -0,0 +1,110 @@
+package lock
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+const defaultElectionInterval = 5 * time.Second
+
+// Leadership contains the callbacks of the leader election.
+type Leadership struct {
+	// OnElected is called when the replica becomes the leader. The context is
+	// canceled when the leadership is lost, OnElected must return after it.
+	OnElected func(ctx context.Context)
+	// OnLost is called after OnElected returned because the leadership is
+	// lost or the election is stopped.
+	//
+	// Optional.
+	OnLost func()
+	// Interval of the attempts to become the leader and the checks that the
+	// leadership is held.
+	//
+	// Default: defaultElectionInterval.
+	Interval time.Duration
+}
+
+// Elect runs the leader election for the name until the context is canceled.
+// Only one replica is the leader at the time, the leadership is kept while
+// the connection of the lock is alive. Can be used as the worker of the
+// container.
+//
+// Example:
+//
+//	err := locker.Elect(ctx, "outbox-relay", lock.Leadership{
+//		OnElected: func(ctx context.Context) {
+//			relay.Run(ctx)
+//		},
+//	})
+func (l *Locker) Elect(ctx context.Context, name string, leadership Leadership) error {
+	interval := leadership.Interval
+	if interval <= 0 {
+		interval = defaultElectionInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		lk, ok, err := l.TryLock(ctx, name)
+		if err != nil && ctx.Err() == nil {
+			slog.Error("can not take leader lock", slog.String("name", name), slog.String("error", err.Error()))
+		}
+
+		if ok {
+			lead(ctx, lk, leadership, ticker.C)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// lead runs OnElected while the lock is alive.
+func lead(ctx context.Context, lk *Lock, leadership Leadership, tick <-chan time.Time) {
+	slog.Info("leadership is gained", slog.String("name", lk.Name()))
+
+	leaderCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		leadership.OnElected(leaderCtx)
+	}()
+
+	func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-done:
+				return
+			case <-tick:
+				if err := lk.Alive(ctx); err != nil {
+					slog.Error("leader lock is lost", slog.String("name", lk.Name()), slog.String("error", err.Error()))
+
+					return
+				}
+			}
+		}
+	}()
+
+	cancel()
+	<-done
+
+	// the lock is not held if the connection is lost.
+	_ = lk.Unlock(context.Background())
+
+	slog.Info("leadership is lost", slog.String("name", lk.Name()))
+
+	if leadership.OnLost != nil {
+		leadership.OnLost()
+	}
+}
@@ -0,0 +1,207 @@
+// Package lock contains distributed locks and the leader election on the
+// Postgres advisory locks. Session locks are held by the dedicated
+// connection of the pool until Unlock, so they are released by Postgres if
+// the replica dies. Transaction locks are released with the transaction.
+//
+// Example:
+//
+//	l, ok, err := locker.TryLock(ctx, "reports:daily")
+//	if err != nil || !ok {
+//		return err
+//	}
+//
+//	defer l.Unlock(ctx)
+package lock
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/Melenium2/go-template/internal/common/tx"
+)
+
+const defaultRetryInterval = 500 * time.Millisecond
+
+var ErrNotHeld = errors.New("lock is not held")
+
+// Pool gives dedicated connections, implemented by *sql.DB and *sqlx.DB.
+type Pool interface {
+	Conn(ctx context.Context) (*sql.Conn, error)
+}
+
+// Locker takes session advisory locks on the dedicated connections.
+type Locker struct {
+	pool  Pool
+	retry time.Duration
+}
+
+// New returns Locker over the connection pool. Lock polls the lock every
+// retry interval, 500ms by default.
+func New(pool Pool, retry ...time.Duration) *Locker {
+	l := &Locker{
+		pool:  pool,
+		retry: defaultRetryInterval,
+	}
+
+	if len(retry) > 0 && retry[0] > 0 {
+		l.retry = retry[0]
+	}
+
+	return l
+}
+
+// Key returns the key of the advisory lock by the name.
+func Key(name string) int64 {
+	h := fnv.New64a()
+
+	_, _ = h.Write([]byte(name))
+
+	return int64(h.Sum64()) //nolint:gosec
+}
+
+// Lock is the acquired session lock.
+type Lock struct {
+	name string
+	key  int64
+
+	mu   sync.Mutex
+	conn *sql.Conn
+}
+
+// Name returns the name of the lock.
+func (l *Lock) Name() string {
+	return l.name
+}
+
+// TryLock takes the lock without waiting. False is returned if the lock is
+// held by somebody else.
+func (l *Locker) TryLock(ctx context.Context, name string) (*Lock, bool, error) {
+	conn, err := l.pool.Conn(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	key := Key(name)
+
+	var locked bool
+
+	err = conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", key).Scan(&locked)
+	if err != nil || !locked {
+		return nil, false, errors.Join(err, conn.Close())
+	}
+
+	return &Lock{name: name, key: key, conn: conn}, true, nil
+}
+
+// Lock waits until the lock is acquired or the context is canceled.
+func (l *Locker) Lock(ctx context.Context, name string) (*Lock, error) {
+	ticker := time.NewTicker(l.retry)
+	defer ticker.Stop()
+
+	for {
+		lk, ok, err := l.TryLock(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			return lk, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Unlock releases the lock and returns the connection to the pool.
+// ErrNotHeld is returned if the lock was already released or lost.
+func (l *Lock) Unlock(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn == nil {
+		return ErrNotHeld
+	}
+
+	conn := l.conn
+	l.conn = nil
+
+	var unlocked bool
+
+	err := conn.QueryRowContext(ctx, "SELECT pg_advisory_unlock($1)", l.key).Scan(&unlocked)
+	if err != nil {
+		// the lock is released with the session.
+		return errors.Join(err, discard(conn))
+	}
+
+	if err = conn.Close(); err != nil {
+		return err
+	}
+
+	if !unlocked {
+		return ErrNotHeld
+	}
+
+	return nil
+}
+
+// Alive checks that the connection of the lock is alive, so the lock is
+// still held.
+func (l *Lock) Alive(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn == nil {
+		return ErrNotHeld
+	}
+
+	if err := l.conn.PingContext(ctx); err != nil {
+		conn := l.conn
+		l.conn = nil
+
+		return errors.Join(err, discard(conn))
+	}
+
+	return nil
+}
+
+// discard closes the connection instead of returning it to the pool, so the
+// session locks are released.
+func discard(conn *sql.Conn) error {
+	err := conn.Raw(func(any) error {
+		return driver.ErrBadConn
+	})
+	if errors.Is(err, driver.ErrBadConn) {
+		err = nil
+	}
+
+	return errors.Join(err, conn.Close())
+}
+
+// WithXactLock runs fn in the transaction under the transaction advisory
+// lock, other replicas wait until the transaction is finished. If the
+// context already has the transaction, it is used.
+//
+// Example:
+//
+//	err := lock.WithXactLock(ctx, "balance:"+clientID, func(ctx context.Context) error {
+//		...
+//	})
+func WithXactLock(ctx context.Context, name string, fn tx.Func) error {
+	return tx.Manager().Do(ctx, func(ctx context.Context) error {
+		_, err := tx.Manager().Conn(ctx).ExecContext(ctx, "SELECT pg_advisory_xact_lock($1)", Key(name))
+		if err != nil {
+			return err
+		}
+
+		return fn(ctx)
+	})
+}
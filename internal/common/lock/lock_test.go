@@ -0,0 +1,128 @@
+package lock
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/Melenium2/go-template/internal/common/tx"
+)
+
+type LockSuite struct {
+	suite.Suite
+
+	db      *sqlx.DB
+	sqlMock sqlmock.Sqlmock
+	locker  *Locker
+}
+
+func (suite *LockSuite) SetupSuite() {
+	db, mock, _ := sqlmock.New()
+
+	suite.sqlMock = mock
+	suite.db = sqlx.NewDb(db, "postgres")
+	suite.locker = New(suite.db, 10*time.Millisecond)
+	tx.SetupManager(suite.db)
+}
+
+func (suite *LockSuite) TearDownTest() {
+	suite.Assert().NoError(suite.sqlMock.ExpectationsWereMet())
+}
+
+func (suite *LockSuite) expectTryLock(name string, locked bool) {
+	suite.sqlMock.ExpectQuery(`SELECT pg_try_advisory_lock\(\$1\)`).WithArgs(Key(name)).
+		WillReturnRows(sqlmock.NewRows([]string{"locked"}).AddRow(locked))
+}
+
+func (suite *LockSuite) expectUnlock(name string) {
+	suite.sqlMock.ExpectQuery(`SELECT pg_advisory_unlock\(\$1\)`).WithArgs(Key(name)).
+		WillReturnRows(sqlmock.NewRows([]string{"unlocked"}).AddRow(true))
+}
+
+func (suite *LockSuite) TestTryLock_Should_return_false_if_lock_is_held() {
+	suite.expectTryLock("job", false)
+
+	lk, ok, err := suite.locker.TryLock(context.Background(), "job")
+	suite.Require().NoError(err)
+	suite.Assert().False(ok)
+	suite.Assert().Nil(lk)
+}
+
+func (suite *LockSuite) TestLock_Should_wait_until_lock_is_released() {
+	ctx := context.Background()
+
+	suite.expectTryLock("job", false)
+	suite.expectTryLock("job", true)
+
+	lk, err := suite.locker.Lock(ctx, "job")
+	suite.Require().NoError(err)
+	suite.Assert().Equal("job", lk.Name())
+
+	suite.expectUnlock("job")
+
+	suite.Assert().NoError(lk.Unlock(ctx))
+	suite.Assert().ErrorIs(lk.Unlock(ctx), ErrNotHeld)
+	suite.Assert().ErrorIs(lk.Alive(ctx), ErrNotHeld)
+}
+
+func (suite *LockSuite) TestLock_Should_stop_waiting_after_context_is_canceled() {
+	// the retry interval is longer than the timeout.
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+
+	suite.expectTryLock("job", false)
+
+	_, err := suite.locker.Lock(ctx, "job")
+	suite.Assert().ErrorIs(err, context.DeadlineExceeded)
+}
+
+func (suite *LockSuite) TestWithXactLock_Should_run_fn_in_transaction_under_lock() {
+	suite.sqlMock.ExpectBegin()
+	suite.sqlMock.ExpectExec(`SELECT pg_advisory_xact_lock\(\$1\)`).WithArgs(Key("balance")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.sqlMock.ExpectRollback()
+
+	expected := errors.New("fn")
+
+	err := WithXactLock(context.Background(), "balance", func(context.Context) error {
+		return expected
+	})
+	suite.Assert().ErrorIs(err, expected)
+}
+
+func (suite *LockSuite) TestElect_Should_call_callbacks_while_leader() {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	suite.expectTryLock("leader", true)
+	suite.expectUnlock("leader")
+
+	var (
+		elected = make(chan struct{})
+		lost    bool
+	)
+
+	go func() {
+		<-elected
+		cancel()
+	}()
+
+	err := suite.locker.Elect(ctx, "leader", Leadership{
+		OnElected: func(ctx context.Context) {
+			close(elected)
+			<-ctx.Done()
+		},
+		OnLost:   func() { lost = true },
+		Interval: 10 * time.Millisecond,
+	})
+	suite.Require().NoError(err)
+	suite.Assert().True(lost)
+}
+
+func TestLockSuite(t *testing.T) {
+	suite.Run(t, new(LockSuite))
+}
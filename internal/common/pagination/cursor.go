@@ -0,0 +1,184 @@
+package pagination
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Melenium2/go-template/internal/common/erx"
+)
+
+// signatureSize is the size of the truncated HMAC of the cursor.
+const signatureSize = 16
+
+var ErrInvalidCursor = fmt.Errorf("%w: invalid cursor", erx.ErrInvalidArgument)
+
+// Codec encodes keyset values into the opaque cursor and decodes them back.
+// Cursors are signed, so the client can not change the values.
+type Codec struct {
+	secret []byte
+}
+
+func NewCodec(secret []byte) *Codec {
+	return &Codec{secret: secret}
+}
+
+type cursorPayload struct {
+	// Sort is the hash of the sort, the cursor can not be used with other sort.
+	Sort   uint32        `json:"s"`
+	Values []cursorValue `json:"v"`
+}
+
+// cursorValue keeps the type of the value, so time and numbers are decoded
+// to the same types.
+type cursorValue struct {
+	Type  string `json:"t"`
+	Value string `json:"v"`
+}
+
+// Encode returns the cursor with the values of the sort columns.
+func (c *Codec) Encode(sort Sort, values []any) (string, error) {
+	payload := cursorPayload{Sort: sortHash(sort)}
+
+	for _, v := range values {
+		cv, err := encodeValue(v)
+		if err != nil {
+			return "", err
+		}
+
+		payload.Values = append(payload.Values, cv)
+	}
+
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	enc := base64.RawURLEncoding
+
+	return enc.EncodeToString(raw) + "." + enc.EncodeToString(c.sign(raw)), nil
+}
+
+// Decode returns values of the sort columns from the cursor. ErrInvalidCursor
+// is returned if the cursor is changed or was made for another sort.
+func (c *Codec) Decode(sort Sort, cursor string) ([]any, error) {
+	enc := base64.RawURLEncoding
+
+	rawPart, sigPart, ok := strings.Cut(cursor, ".")
+	if !ok {
+		return nil, ErrInvalidCursor
+	}
+
+	raw, err := enc.DecodeString(rawPart)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	sig, err := enc.DecodeString(sigPart)
+	if err != nil || !hmac.Equal(sig, c.sign(raw)) {
+		return nil, ErrInvalidCursor
+	}
+
+	var payload cursorPayload
+
+	if err = json.NewDecoder(bytes.NewReader(raw)).Decode(&payload); err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	if payload.Sort != sortHash(sort) || len(payload.Values) != len(sort) {
+		return nil, ErrInvalidCursor
+	}
+
+	values := make([]any, 0, len(payload.Values))
+
+	for _, cv := range payload.Values {
+		v, err := decodeValue(cv)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+
+		values = append(values, v)
+	}
+
+	return values, nil
+}
+
+func (c *Codec) sign(raw []byte) []byte {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write(raw)
+
+	return mac.Sum(nil)[:signatureSize]
+}
+
+func sortHash(sort Sort) uint32 {
+	h := fnv.New32a()
+
+	for _, s := range sort {
+		_, _ = fmt.Fprintf(h, "%s:%t;", s.Column, s.Desc)
+	}
+
+	return h.Sum32()
+}
+
+//revive:disable:cyclomatic
+func encodeValue(v any) (cursorValue, error) {
+	if valuer, ok := v.(driver.Valuer); ok {
+		var err error
+
+		if v, err = valuer.Value(); err != nil {
+			return cursorValue{}, err
+		}
+	}
+
+	switch v := v.(type) {
+	case time.Time:
+		return cursorValue{Type: "t", Value: v.Format(time.RFC3339Nano)}, nil
+	case string:
+		return cursorValue{Type: "s", Value: v}, nil
+	case []byte:
+		return cursorValue{Type: "s", Value: string(v)}, nil
+	case bool:
+		return cursorValue{Type: "b", Value: strconv.FormatBool(v)}, nil
+	case int:
+		return cursorValue{Type: "i", Value: strconv.FormatInt(int64(v), 10)}, nil
+	case int32:
+		return cursorValue{Type: "i", Value: strconv.FormatInt(int64(v), 10)}, nil
+	case int64:
+		return cursorValue{Type: "i", Value: strconv.FormatInt(v, 10)}, nil
+	case uint32:
+		return cursorValue{Type: "i", Value: strconv.FormatUint(uint64(v), 10)}, nil
+	case float64:
+		return cursorValue{Type: "f", Value: strconv.FormatFloat(v, 'g', -1, 64)}, nil
+	case fmt.Stringer:
+		return cursorValue{Type: "s", Value: v.String()}, nil
+	default:
+		return cursorValue{}, fmt.Errorf("unsupported cursor value %T", v)
+	}
+}
+
+//revive:enable:cyclomatic
+
+func decodeValue(cv cursorValue) (any, error) {
+	switch cv.Type {
+	case "t":
+		return time.Parse(time.RFC3339Nano, cv.Value)
+	case "s":
+		return cv.Value, nil
+	case "b":
+		return strconv.ParseBool(cv.Value)
+	case "i":
+		return strconv.ParseInt(cv.Value, 10, 64)
+	case "f":
+		return strconv.ParseFloat(cv.Value, 64)
+	default:
+		return nil, fmt.Errorf("unknown cursor value type %q", cv.Type)
+	}
+}
@@ -0,0 +1,64 @@
+package pagination
+
+// ProtoRequest is the generated PageRequest message of the service.proto.
+// The getters of the generated code are used, so the package does not depend
+// on the generated code.
+type ProtoRequest interface {
+	GetLimit() int32
+	GetCursor() string
+	GetOffset() int32
+	GetWithTotal() bool
+}
+
+// FromProto returns the request from the PageRequest message, nil message
+// is the first page with the default limit.
+//
+// Example:
+//
+//	page, err := s.bus.Query(ctx, query.ListOrdersParameters{
+//		Request: pagination.FromProto(in.GetPage()),
+//	})
+func FromProto(msg ProtoRequest) Request {
+	if msg == nil {
+		return Request{}
+	}
+
+	return Request{
+		Limit:     int(msg.GetLimit()),
+		Cursor:    msg.GetCursor(),
+		Offset:    int(msg.GetOffset()),
+		WithTotal: msg.GetWithTotal(),
+	}
+}
+
+// Info is the content of the PageInfo message of the service.proto, total is
+// -1 if it was not requested.
+type Info struct {
+	NextCursor string
+	HasMore    bool
+	Total      int64
+}
+
+// Info returns the pagination info of the page for the responses.
+//
+// Example:
+//
+//	info := page.Info()
+//
+//	return &pb.ListOrdersResponse{
+//		Orders: orders,
+//		Page: &pb.PageInfo{NextCursor: info.NextCursor, HasMore: info.HasMore, Total: info.Total},
+//	}, nil
+func (p Page[T]) Info() Info {
+	info := Info{
+		NextCursor: p.NextCursor,
+		HasMore:    p.HasMore,
+		Total:      -1,
+	}
+
+	if p.Total != nil {
+		info.Total = *p.Total
+	}
+
+	return info
+}
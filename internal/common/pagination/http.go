@@ -0,0 +1,83 @@
+package pagination
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/Melenium2/go-template/internal/common/erx"
+)
+
+// Names of the query parameters.
+const (
+	LimitParam  = "limit"
+	CursorParam = "cursor"
+	OffsetParam = "offset"
+	TotalParam  = "with_total"
+)
+
+// FromQuery returns the request from the query parameters of the URL:
+// ?limit=20&cursor=...&offset=40&with_total=true. Errors wrap
+// erx.ErrInvalidArgument.
+//
+// Example:
+//
+//	req, err := pagination.FromQuery(r.URL.Query())
+func FromQuery(values url.Values) (Request, error) {
+	var (
+		req Request
+		err error
+	)
+
+	if req.Limit, err = intParam(values, LimitParam); err != nil {
+		return req, err
+	}
+
+	if req.Offset, err = intParam(values, OffsetParam); err != nil {
+		return req, err
+	}
+
+	if v := values.Get(TotalParam); v != "" {
+		if req.WithTotal, err = strconv.ParseBool(v); err != nil {
+			return req, fmt.Errorf("%w: %s must be boolean", erx.ErrInvalidArgument, TotalParam)
+		}
+	}
+
+	req.Cursor = values.Get(CursorParam)
+
+	return req, nil
+}
+
+// Query returns the query parameters of the next page for the links in the
+// responses.
+func (r Request) Query(nextCursor string) url.Values {
+	values := url.Values{}
+
+	if r.Limit > 0 {
+		values.Set(LimitParam, strconv.Itoa(r.Limit))
+	}
+
+	if nextCursor != "" {
+		values.Set(CursorParam, nextCursor)
+	}
+
+	if r.WithTotal {
+		values.Set(TotalParam, "true")
+	}
+
+	return values
+}
+
+func intParam(values url.Values, name string) (int, error) {
+	v := values.Get(name)
+	if v == "" {
+		return 0, nil
+	}
+
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%w: %s must be non-negative integer", erx.ErrInvalidArgument, name)
+	}
+
+	return n, nil
+}
@@ -0,0 +1,96 @@
+// Package pagination contains the pagination model shared by the queries,
+// the repositories and the UI layers. Keyset pagination is used by default:
+// the page ends with the opaque signed cursor of the last item, the next page
+// starts right after it. Offset pagination is the fallback for the clients
+// that need to jump to the page by number.
+//
+// Example of the query:
+//
+//	type AvailableServicesParameters struct {
+//		pagination.Request
+//		ClientID string `json:"client_id" validate:"required,uuid"`
+//	}
+//
+//	func (q *AvailableServices) Do(
+//		ctx context.Context,
+//		params AvailableServicesParameters,
+//	) (pagination.Page[Service], error)
+package pagination
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+// Request describes the requested page. If Cursor is set, the page starts
+// right after the item of the cursor and Offset is ignored.
+type Request struct {
+	Limit  int    `json:"limit" validate:"min=0,max=100"`
+	Cursor string `json:"cursor"`
+	Offset int    `json:"offset" validate:"min=0"`
+	// WithTotal requests the total count of items, it costs additional query.
+	WithTotal bool `json:"with_total"`
+}
+
+// PageLimit returns the limit of the page, DefaultLimit if it is not set.
+func (r Request) PageLimit() int {
+	switch {
+	case r.Limit <= 0:
+		return DefaultLimit
+	case r.Limit > MaxLimit:
+		return MaxLimit
+	default:
+		return r.Limit
+	}
+}
+
+// Page is the list of items with the cursor of the next page.
+type Page[T any] struct {
+	Items []T `json:"items"`
+	// NextCursor is empty if there are no more items.
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+	// Total is set only if Request.WithTotal is true.
+	Total *int64 `json:"total,omitempty"`
+}
+
+// Map converts items of the page, used to convert storage models to the
+// results of the query.
+func Map[T, R any](p Page[T], fn func(T) R) Page[R] {
+	items := make([]R, 0, len(p.Items))
+
+	for _, item := range p.Items {
+		items = append(items, fn(item))
+	}
+
+	return Page[R]{
+		Items:      items,
+		NextCursor: p.NextCursor,
+		HasMore:    p.HasMore,
+		Total:      p.Total,
+	}
+}
+
+// SortColumn is the column of the keyset.
+type SortColumn struct {
+	// Column is the SQL column, for example "o.created_at".
+	Column string
+	// Field is the db tag of the item field with the value of the column,
+	// the last part of Column by default.
+	Field string
+	Desc  bool
+}
+
+// Sort is the order of the items. The last column must be unique, for
+// example primary key, so the order is stable. Columns must be not null.
+type Sort []SortColumn
+
+// Asc returns ascending sort column.
+func Asc(column string) SortColumn {
+	return SortColumn{Column: column}
+}
+
+// Desc returns descending sort column.
+func Desc(column string) SortColumn {
+	return SortColumn{Column: column, Desc: true}
+}
@@ -0,0 +1,88 @@
+package pagination
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Melenium2/go-template/internal/common/erx"
+)
+
+func TestCodec_Should_decode_encoded_values(t *testing.T) {
+	codec := NewCodec([]byte("secret"))
+	sort := Sort{Desc("created_at"), Asc("id"), Asc("name"), Asc("price")}
+	createdAt := time.Date(2024, 5, 1, 10, 30, 0, 123, time.UTC)
+
+	cursor, err := codec.Encode(sort, []any{createdAt, 42, "foo", 9.5})
+	require.NoError(t, err)
+
+	values, err := codec.Decode(sort, cursor)
+	require.NoError(t, err)
+	assert.Equal(t, []any{createdAt, int64(42), "foo", 9.5}, values)
+}
+
+func TestCodec_Should_reject_changed_or_foreign_cursors(t *testing.T) {
+	codec := NewCodec([]byte("secret"))
+	sort := Sort{Asc("id")}
+
+	cursor, err := codec.Encode(sort, []any{1})
+	require.NoError(t, err)
+
+	other, err := NewCodec([]byte("other")).Encode(sort, []any{1})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		sort   Sort
+		cursor string
+	}{
+		{name: "garbage", sort: sort, cursor: "garbage"},
+		{name: "changed payload", sort: sort, cursor: "x" + cursor},
+		{name: "other secret", sort: sort, cursor: other},
+		{name: "other sort", sort: Sort{Desc("id")}, cursor: cursor},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := codec.Decode(tc.sort, tc.cursor)
+			assert.ErrorIs(t, err, ErrInvalidCursor)
+			assert.ErrorIs(t, err, erx.ErrInvalidArgument)
+		})
+	}
+}
+
+func TestRequest_PageLimit(t *testing.T) {
+	assert.Equal(t, DefaultLimit, Request{}.PageLimit())
+	assert.Equal(t, 5, Request{Limit: 5}.PageLimit())
+	assert.Equal(t, MaxLimit, Request{Limit: 1000}.PageLimit())
+}
+
+func TestFromQuery(t *testing.T) {
+	req, err := FromQuery(url.Values{
+		"limit":      {"10"},
+		"cursor":     {"abc"},
+		"offset":     {"20"},
+		"with_total": {"true"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, Request{Limit: 10, Cursor: "abc", Offset: 20, WithTotal: true}, req)
+
+	_, err = FromQuery(url.Values{"limit": {"-1"}})
+	assert.ErrorIs(t, err, erx.ErrInvalidArgument)
+
+	_, err = FromQuery(url.Values{"with_total": {"maybe"}})
+	assert.ErrorIs(t, err, erx.ErrInvalidArgument)
+
+	assert.Equal(t, url.Values{"limit": {"10"}, "cursor": {"next"}}, Request{Limit: 10}.Query("next"))
+}
+
+func TestPage_Info(t *testing.T) {
+	total := int64(3)
+
+	assert.Equal(t, Info{NextCursor: "next", HasMore: true, Total: -1},
+		Page[int]{NextCursor: "next", HasMore: true}.Info())
+	assert.Equal(t, Info{Total: 3}, Page[int]{Total: &total}.Info())
+}
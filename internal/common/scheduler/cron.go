@@ -0,0 +1,289 @@
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule returns the next time of the run after t.
+type Schedule interface {
+	Next(t time.Time) time.Time
+}
+
+type every time.Duration
+
+func (e every) Next(t time.Time) time.Time {
+	return t.Truncate(time.Duration(e)).Add(time.Duration(e))
+}
+
+// Every runs the task with the fixed interval. Runs are aligned to the
+// multiples of the interval since the zero time, not to the start of the
+// scheduler, so replicas run the task at the same time and SingleReplica
+// tasks are run once. For example Every(10*time.Minute) runs the task at
+// 10:00, 10:10, 10:20 etc. Every panics if the interval is not positive.
+func Every(d time.Duration) Schedule {
+	if d <= 0 {
+		panic(fmt.Sprintf("scheduler: interval %s is not positive", d))
+	}
+
+	return every(d)
+}
+
+// cron is the parsed cron expression, each field is the bit set of the
+// allowed values.
+type cron struct {
+	second, minute, hour, dom, month, dow uint64
+	// if both day fields are restricted, the day matches either of them.
+	domAny, dowAny bool
+	loc            *time.Location
+}
+
+type bounds struct {
+	min, max int
+	names    map[string]int
+}
+
+var (
+	seconds = bounds{min: 0, max: 59}
+	minutes = bounds{min: 0, max: 59}
+	hours   = bounds{min: 0, max: 23}
+	doms    = bounds{min: 1, max: 31}
+	months  = bounds{min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	dows = bounds{min: 0, max: 6, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// Cron parses the cron expression in the local time zone. Standard five
+// fields (minute hour day-of-month month day-of-week) or six fields with
+// seconds first are supported, as well as lists, ranges, steps, names of
+// months and days, descriptors (@daily, @hourly etc.) and "@every 10m".
+//
+// Example:
+//
+//	scheduler.Cron("*/15 * * * *")       // every 15 minutes
+//	scheduler.Cron("30 0 9 * * MON-FRI") // 09:00:30 on weekdays
+func Cron(expr string) (Schedule, error) {
+	return CronIn(expr, time.Local)
+}
+
+// CronIn is like Cron, but in the time zone.
+func CronIn(expr string, loc *time.Location) (Schedule, error) {
+	expr = strings.TrimSpace(expr)
+
+	if d, ok := strings.CutPrefix(expr, "@every "); ok {
+		interval, err := time.ParseDuration(strings.TrimSpace(d))
+		if err != nil || interval <= 0 {
+			return nil, fmt.Errorf("invalid interval of %q", expr)
+		}
+
+		return Every(interval), nil
+	}
+
+	if d, ok := descriptors[strings.ToLower(expr)]; ok {
+		expr = d
+	}
+
+	fields := strings.Fields(expr)
+
+	switch len(fields) {
+	case 5:
+		fields = append([]string{"0"}, fields...)
+	case 6:
+	default:
+		return nil, fmt.Errorf("cron %q must have 5 or 6 fields", expr)
+	}
+
+	c := &cron{loc: loc}
+
+	var err error
+
+	sets := []struct {
+		dst *uint64
+		b   bounds
+	}{
+		{&c.second, seconds}, {&c.minute, minutes}, {&c.hour, hours},
+		{&c.dom, doms}, {&c.month, months}, {&c.dow, dows},
+	}
+
+	for i, s := range sets {
+		if *s.dst, err = parseField(fields[i], s.b); err != nil {
+			return nil, fmt.Errorf("cron %q: %w", expr, err)
+		}
+	}
+
+	c.domAny = fields[3] == "*" || fields[3] == "?"
+	c.dowAny = fields[5] == "*" || fields[5] == "?"
+
+	return c, nil
+}
+
+// MustCron is like Cron, but panics on the invalid expression. Used for the
+// expressions known at compile time.
+func MustCron(expr string) Schedule {
+	s, err := Cron(expr)
+	if err != nil {
+		panic(err)
+	}
+
+	return s
+}
+
+func parseField(field string, b bounds) (uint64, error) {
+	var set uint64
+
+	for _, part := range strings.Split(field, ",") {
+		bits, err := parsePart(part, b)
+		if err != nil {
+			return 0, err
+		}
+
+		set |= bits
+	}
+
+	return set, nil
+}
+
+// parsePart parses "*", "?", "5", "1-5", "*/15", "1-30/5" or "MON".
+func parsePart(part string, b bounds) (uint64, error) {
+	rng, stepStr, hasStep := strings.Cut(part, "/")
+
+	step := 1
+
+	if hasStep {
+		var err error
+
+		if step, err = strconv.Atoi(stepStr); err != nil || step <= 0 {
+			return 0, fmt.Errorf("invalid step in %q", part)
+		}
+	}
+
+	lo, hi := b.min, b.max
+
+	if rng != "*" && rng != "?" {
+		from, to, isRange := strings.Cut(rng, "-")
+
+		var err error
+
+		if lo, err = b.value(from); err != nil {
+			return 0, err
+		}
+
+		hi = lo
+
+		if isRange {
+			if hi, err = b.value(to); err != nil {
+				return 0, err
+			}
+		} else if hasStep {
+			hi = b.max
+		}
+	}
+
+	if lo > hi {
+		return 0, fmt.Errorf("invalid range %q", part)
+	}
+
+	var bits uint64
+
+	for v := lo; v <= hi; v += step {
+		bits |= 1 << uint(v)
+	}
+
+	return bits, nil
+}
+
+func (b bounds) value(s string) (int, error) {
+	if v, ok := b.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+
+	// 7 is Sunday too.
+	if b.max == 6 && v == 7 {
+		v = 0
+	}
+
+	if v < b.min || v > b.max {
+		return 0, fmt.Errorf("value %d is out of range [%d, %d]", v, b.min, b.max)
+	}
+
+	return v, nil
+}
+
+// maxYears limits the search of the next time, for example for 30th of
+// February.
+const maxYears = 5
+
+// Next returns the next matching time after t, zero time if there is none.
+//
+//revive:disable:cognitive-complexity
+func (c *cron) Next(t time.Time) time.Time {
+	orig := t.Location()
+
+	t = t.In(c.loc).Truncate(time.Second).Add(time.Second)
+	limit := t.AddDate(maxYears, 0, 0)
+
+	for t.Before(limit) {
+		switch {
+		case !has(c.month, int(t.Month())):
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, c.loc)
+		case !c.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, c.loc)
+		// hours and minutes are added to the absolute time, time.Date resolves
+		// the hour repeated at the end of DST to its first occurrence, and
+		// the next time would be before t.
+		case !has(c.hour, t.Hour()):
+			t = t.Truncate(time.Minute).Add(time.Duration(60-t.Minute()) * time.Minute)
+		case !has(c.minute, t.Minute()):
+			t = t.Truncate(time.Minute).Add(time.Minute)
+		case !has(c.second, t.Second()):
+			t = t.Add(time.Second)
+		default:
+			return t.In(orig)
+		}
+	}
+
+	return time.Time{}
+}
+
+//revive:enable:cognitive-complexity
+
+func (c *cron) dayMatches(t time.Time) bool {
+	dom := has(c.dom, t.Day())
+	dow := has(c.dow, int(t.Weekday()))
+
+	switch {
+	case c.domAny && c.dowAny:
+		return true
+	case c.domAny:
+		return dow
+	case c.dowAny:
+		return dom
+	default:
+		return dom || dow
+	}
+}
+
+func has(set uint64, v int) bool {
+	return set&(1<<uint(v)) != 0
+}
@@ -0,0 +1,276 @@
+// Package scheduler runs periodic tasks by cron expressions or fixed
+// intervals instead of hand-written time.Ticker goroutines. Runs of the same
+// task never overlap, panics are recovered and logged. Tasks can be run by a
+// single replica under the Postgres advisory lock.
+//
+// Example:
+//
+//	s := scheduler.New(scheduler.WithLocker(c.Locks))
+//
+//	s.Add("cleanup-sessions", scheduler.MustCron("0 */10 * * * *"), cleanup,
+//		scheduler.SingleReplica(),
+//		scheduler.Jitter(5*time.Second),
+//	)
+//
+//	err := s.Run(ctx)
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Melenium2/go-template/internal/common/lock"
+)
+
+const defaultShutdownTimeout = 30 * time.Second
+
+// lockHold is the minimum time the lock of the single replica task is held
+// after the scheduled time, so other replicas do not repeat the fast run
+// because of the clock skew.
+const lockHold = time.Second
+
+var ErrNoLocker = errors.New("single replica task requires locker, see WithLocker")
+
+// Task is the periodic task. The context is canceled if the task is still
+// running after the shutdown timeout.
+type Task func(ctx context.Context) error
+
+type entry struct {
+	name          string
+	schedule      Schedule
+	task          Task
+	jitter        time.Duration
+	timeout       time.Duration
+	singleReplica bool
+
+	running atomic.Bool
+}
+
+// TaskOption configures the task.
+type TaskOption func(e *entry)
+
+// Jitter delays each run by the random duration up to d, so replicas and
+// tasks with the same schedule do not start at the same moment.
+func Jitter(d time.Duration) TaskOption {
+	return func(e *entry) {
+		e.jitter = d
+	}
+}
+
+// Timeout cancels the context of the run after d.
+func Timeout(d time.Duration) TaskOption {
+	return func(e *entry) {
+		e.timeout = d
+	}
+}
+
+// SingleReplica runs the task only on the replica that takes the advisory
+// lock by the name of the task, other replicas skip the run. The lock is
+// held for the scheduled time only, so the schedule must give the same times
+// on all replicas, as Cron and Every do.
+func SingleReplica() TaskOption {
+	return func(e *entry) {
+		e.singleReplica = true
+	}
+}
+
+// Scheduler runs the tasks.
+type Scheduler struct {
+	locker          *lock.Locker
+	shutdownTimeout time.Duration
+
+	entries []*entry
+}
+
+// Option configures the scheduler.
+type Option func(s *Scheduler)
+
+// WithLocker sets the locker for SingleReplica tasks.
+func WithLocker(l *lock.Locker) Option {
+	return func(s *Scheduler) {
+		s.locker = l
+	}
+}
+
+// WithShutdownTimeout sets the time for running tasks to finish after the
+// scheduler is stopped, then their contexts are canceled.
+//
+// Default: defaultShutdownTimeout.
+func WithShutdownTimeout(d time.Duration) Option {
+	return func(s *Scheduler) {
+		s.shutdownTimeout = d
+	}
+}
+
+func New(opts ...Option) *Scheduler {
+	s := &Scheduler{shutdownTimeout: defaultShutdownTimeout}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Add registers the task, tasks must be added before Run. The name is used
+// in logs and as the key of the lock.
+func (s *Scheduler) Add(name string, schedule Schedule, task Task, opts ...TaskOption) {
+	e := &entry{name: name, schedule: schedule, task: task}
+
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	s.entries = append(s.entries, e)
+}
+
+// Run runs the tasks until the context is canceled, then waits for the
+// running tasks. Can be used as the worker of the container.
+func (s *Scheduler) Run(ctx context.Context) error {
+	for _, e := range s.entries {
+		if e.singleReplica && s.locker == nil {
+			return fmt.Errorf("task %s: %w", e.name, ErrNoLocker)
+		}
+	}
+
+	// tasks are not canceled with the scheduler, they have the time to
+	// finish after the shutdown.
+	taskCtx, cancelTasks := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelTasks()
+
+	var (
+		loops sync.WaitGroup
+		runs  sync.WaitGroup
+	)
+
+	loops.Add(len(s.entries))
+
+	for _, e := range s.entries {
+		go func() {
+			defer loops.Done()
+
+			s.loop(ctx, taskCtx, e, &runs)
+		}()
+	}
+
+	loops.Wait()
+
+	done := make(chan struct{})
+
+	go func() {
+		runs.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(s.shutdownTimeout):
+		slog.Warn("scheduler tasks are canceled after shutdown timeout")
+
+		cancelTasks()
+		<-done
+	}
+
+	return nil
+}
+
+func (s *Scheduler) loop(ctx, taskCtx context.Context, e *entry, runs *sync.WaitGroup) {
+	next := e.schedule.Next(time.Now())
+
+	for !next.IsZero() {
+		scheduled := next
+
+		delay := time.Until(scheduled)
+		if e.jitter > 0 {
+			delay += rand.N(e.jitter) //nolint:gosec
+		}
+
+		timer := time.NewTimer(delay)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return
+		case <-timer.C:
+		}
+
+		// the previous run is still in progress, the run is skipped.
+		if e.running.CompareAndSwap(false, true) {
+			runs.Add(1)
+
+			go func() {
+				defer runs.Done()
+				defer e.running.Store(false)
+
+				s.run(taskCtx, e, scheduled)
+			}()
+		} else {
+			slog.Warn("scheduled task is skipped, previous run is in progress", slog.String("task", e.name))
+		}
+
+		next = e.schedule.Next(time.Now())
+	}
+}
+
+func (s *Scheduler) run(ctx context.Context, e *entry, scheduled time.Time) {
+	if e.singleReplica {
+		lk, ok, err := s.locker.TryLock(ctx, "scheduler:"+e.name)
+		if err != nil {
+			slog.Error("can not take lock of task", slog.String("task", e.name), slog.String("error", err.Error()))
+
+			return
+		}
+
+		// another replica runs the task.
+		if !ok {
+			return
+		}
+
+		defer func() {
+			hold := time.Until(scheduled.Add(e.jitter + lockHold))
+
+			time.AfterFunc(max(hold, 0), func() {
+				_ = lk.Unlock(context.Background())
+			})
+		}()
+	}
+
+	if e.timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, e.timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+
+	if err := safeRun(ctx, e.task); err != nil {
+		slog.Error("scheduled task is failed",
+			slog.String("task", e.name),
+			slog.Duration("duration", time.Since(start)),
+			slog.String("error", err.Error()),
+		)
+
+		return
+	}
+
+	slog.Debug("scheduled task is finished", slog.String("task", e.name), slog.Duration("duration", time.Since(start)))
+}
+
+func safeRun(ctx context.Context, task Task) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("recovered after panic in task, %v\n%s", p, debug.Stack())
+		}
+	}()
+
+	return task(ctx)
+}
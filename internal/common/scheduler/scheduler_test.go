@@ -0,0 +1,132 @@
+package scheduler
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCron_Next(t *testing.T) {
+	from := time.Date(2024, 1, 31, 10, 15, 30, 0, time.UTC)
+
+	tests := []struct {
+		expr string
+		next time.Time
+	}{
+		{expr: "* * * * *", next: time.Date(2024, 1, 31, 10, 16, 0, 0, time.UTC)},
+		{expr: "*/20 * * * * *", next: time.Date(2024, 1, 31, 10, 15, 40, 0, time.UTC)},
+		{expr: "0 9 * * MON-FRI", next: time.Date(2024, 2, 1, 9, 0, 0, 0, time.UTC)},
+		{expr: "30 0 9 * * sat,sun", next: time.Date(2024, 2, 3, 9, 0, 30, 0, time.UTC)},
+		{expr: "0 0 29 2 *", next: time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{expr: "0 0 1 * 1", next: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{expr: "10-20/5 10 * * *", next: time.Date(2024, 1, 31, 10, 20, 0, 0, time.UTC)},
+		{expr: "@monthly", next: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{expr: "@every 90s", next: time.Date(2024, 1, 31, 10, 16, 30, 0, time.UTC)},
+		{expr: "0 0 30 2 *", next: time.Time{}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.expr, func(t *testing.T) {
+			s, err := CronIn(tc.expr, time.UTC)
+			require.NoError(t, err)
+			assert.Equal(t, tc.next, s.Next(from))
+		})
+	}
+}
+
+func TestEvery_Should_align_runs_to_interval(t *testing.T) {
+	s := Every(10 * time.Minute)
+
+	// replicas started at different times run the task at the same time.
+	for _, from := range []time.Time{
+		time.Date(2024, 1, 31, 10, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 31, 10, 3, 12, 0, time.UTC),
+		time.Date(2024, 1, 31, 10, 9, 59, 999, time.UTC),
+	} {
+		assert.Equal(t, time.Date(2024, 1, 31, 10, 10, 0, 0, time.UTC), s.Next(from), from)
+	}
+}
+
+func TestCron_Next_Should_not_go_back_at_end_of_DST(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	s, err := CronIn("*/5 * * * *", loc)
+	require.NoError(t, err)
+
+	// 01:40 EST is the second 01:40 of the day, the clock is set back at 02:00 EDT.
+	from := time.Date(2024, 11, 3, 6, 40, 0, 0, time.UTC)
+
+	next := s.Next(from)
+	assert.Equal(t, time.Date(2024, 11, 3, 6, 45, 0, 0, time.UTC), next.UTC())
+
+	// the repeated hour is run in both occurrences.
+	from = time.Date(2024, 11, 3, 5, 55, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2024, 11, 3, 6, 0, 0, 0, time.UTC), s.Next(from).UTC())
+}
+
+func TestEvery_Should_panic_if_interval_is_not_positive(t *testing.T) {
+	assert.Panics(t, func() { Every(0) })
+	assert.Panics(t, func() { Every(-time.Second) })
+}
+
+func TestCron_Should_return_error_for_invalid_expression(t *testing.T) {
+	for _, expr := range []string{"", "* * *", "60 * * * *", "* * * * 8", "5-1 * * * *", "*/0 * * * *", "@every -1s"} {
+		_, err := Cron(expr)
+		assert.Error(t, err, expr)
+	}
+}
+
+func TestScheduler_Should_skip_overlapping_runs_and_recover_panics(t *testing.T) {
+	var runs, panics atomic.Int32
+
+	s := New(WithShutdownTimeout(time.Second))
+
+	s.Add("slow", Every(10*time.Millisecond), func(ctx context.Context) error {
+		runs.Add(1)
+		time.Sleep(55 * time.Millisecond)
+
+		return nil
+	})
+	s.Add("panic", Every(10*time.Millisecond), func(ctx context.Context) error {
+		panics.Add(1)
+		panic("boom")
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	require.NoError(t, s.Run(ctx))
+	assert.LessOrEqual(t, runs.Load(), int32(2))
+	assert.Greater(t, panics.Load(), int32(2))
+}
+
+func TestScheduler_Should_cancel_tasks_after_shutdown_timeout(t *testing.T) {
+	var canceled atomic.Bool
+
+	s := New(WithShutdownTimeout(10 * time.Millisecond))
+
+	s.Add("endless", Every(time.Millisecond), func(ctx context.Context) error {
+		<-ctx.Done()
+		canceled.Store(true)
+
+		return ctx.Err()
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	require.NoError(t, s.Run(ctx))
+	assert.True(t, canceled.Load())
+}
+
+func TestScheduler_Should_require_locker_for_single_replica_tasks(t *testing.T) {
+	s := New()
+	s.Add("task", Every(time.Second), func(context.Context) error { return nil }, SingleReplica())
+
+	assert.ErrorIs(t, s.Run(context.Background()), ErrNoLocker)
+}
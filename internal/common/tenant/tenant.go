@@ -0,0 +1,135 @@
+// Package tenant carries the tenant ID of the request in the context.
+// The ID is extracted by the HTTP middleware or from the gRPC metadata and
+// used by tx.Manager() to switch the database connection to the tenant.
+package tenant
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// Header is the default name of the HTTP header and the gRPC metadata key
+// with the tenant ID.
+const Header = "X-Tenant-ID"
+
+var (
+	ErrNoTenant      = errors.New("tenant is not set")
+	ErrInvalidTenant = errors.New("invalid tenant")
+)
+
+// valid tenant ID can be used as part of the schema name.
+var validID = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,48}$`)
+
+type tenantCtxKey uint8
+
+const tenantKey tenantCtxKey = 1 << 5
+
+// WithID returns the context with the tenant ID. ErrInvalidTenant is
+// returned if the ID contains other characters than letters, digits, '_'
+// and '-'.
+func WithID(ctx context.Context, id string) (context.Context, error) {
+	if !validID.MatchString(id) {
+		return ctx, ErrInvalidTenant
+	}
+
+	return context.WithValue(ctx, tenantKey, id), nil
+}
+
+// ID returns the tenant ID from the context.
+func ID(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(tenantKey).(string)
+
+	return id, ok
+}
+
+// MustID returns the tenant ID from the context or ErrNoTenant.
+func MustID(ctx context.Context) (string, error) {
+	id, ok := ID(ctx)
+	if !ok {
+		return "", ErrNoTenant
+	}
+
+	return id, nil
+}
+
+// Scope returns the key prefixed by the tenant ID of the context, so the
+// keys of shared storages, for example caches, do not collide between
+// tenants. The key is returned as is if the tenant is not set.
+func Scope(ctx context.Context, key string) string {
+	id, ok := ID(ctx)
+	if !ok {
+		return key
+	}
+
+	return "tenant:" + id + ":" + key
+}
+
+// Middleware extracts the tenant ID from the HTTP header, Header by default.
+// Requests without the header are passed as is, the queries of such requests
+// are rejected by tx.TenantGuard. Requests with invalid ID are rejected with
+// 400 Bad Request.
+func Middleware(header ...string) func(http.Handler) http.Handler {
+	name := Header
+
+	if len(header) > 0 && header[0] != "" {
+		name = header[0]
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := r.Header.Get(name)
+			if id == "" {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			ctx, err := WithID(r.Context(), id)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// FromMetadata returns the context with the tenant ID from the gRPC
+// metadata (metadata.MD has the same type). Keys of the gRPC metadata are in
+// lower case. If the metadata does not contain the key, ctx is returned as is.
+// Usage in the unary interceptor:
+//
+//	func TenantInterceptor(
+//		ctx context.Context,
+//		req any,
+//		_ *grpc.UnaryServerInfo,
+//		handler grpc.UnaryHandler,
+//	) (any, error) {
+//		md, _ := metadata.FromIncomingContext(ctx)
+//
+//		ctx, err := tenant.FromMetadata(ctx, md)
+//		if err != nil {
+//			return nil, status.Error(codes.InvalidArgument, err.Error())
+//		}
+//
+//		return handler(ctx, req)
+//	}
+func FromMetadata(ctx context.Context, md map[string][]string, key ...string) (context.Context, error) {
+	name := strings.ToLower(Header)
+
+	if len(key) > 0 && key[0] != "" {
+		name = strings.ToLower(key[0])
+	}
+
+	values := md[name]
+	if len(values) == 0 || values[0] == "" {
+		return ctx, nil
+	}
+
+	return WithID(ctx, values[0])
+}
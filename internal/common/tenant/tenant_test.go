@@ -0,0 +1,73 @@
+package tenant
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithID_Should_validate_tenant(t *testing.T) {
+	ctx, err := WithID(context.Background(), "acme_1")
+	require.NoError(t, err)
+
+	id, err := MustID(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "acme_1", id)
+
+	_, err = WithID(context.Background(), `acme"; DROP SCHEMA public`)
+	assert.ErrorIs(t, err, ErrInvalidTenant)
+
+	_, err = MustID(context.Background())
+	assert.ErrorIs(t, err, ErrNoTenant)
+}
+
+func TestScope_Should_prefix_key_by_tenant(t *testing.T) {
+	ctx, err := WithID(context.Background(), "acme")
+	require.NoError(t, err)
+
+	assert.Equal(t, "tenant:acme:orders:1", Scope(ctx, "orders:1"))
+	assert.Equal(t, "orders:1", Scope(context.Background(), "orders:1"))
+}
+
+func TestMiddleware_Should_put_tenant_from_header_to_context(t *testing.T) {
+	var got string
+
+	handler := Middleware()(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		got, _ = ID(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(Header, "acme")
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "acme", got)
+
+	req.Header.Set(Header, "a c m e")
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestFromMetadata(t *testing.T) {
+	ctx, err := FromMetadata(context.Background(), map[string][]string{"x-tenant-id": {"acme"}})
+	require.NoError(t, err)
+
+	id, ok := ID(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, "acme", id)
+
+	ctx, err = FromMetadata(context.Background(), map[string][]string{})
+	require.NoError(t, err)
+
+	_, ok = ID(ctx)
+	assert.False(t, ok)
+}
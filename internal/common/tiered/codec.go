@@ -0,0 +1,73 @@
+package tiered
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+)
+
+// Codec serializes the values for the second level.
+type Codec[T any] interface {
+	Marshal(value T) ([]byte, error)
+	Unmarshal(data []byte) (T, error)
+}
+
+type jsonCodec[T any] struct{}
+
+// JSON encodes the values by encoding/json.
+func JSON[T any]() Codec[T] {
+	return jsonCodec[T]{}
+}
+
+func (jsonCodec[T]) Marshal(value T) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+func (jsonCodec[T]) Unmarshal(data []byte) (T, error) {
+	var value T
+
+	err := json.Unmarshal(data, &value)
+
+	return value, err
+}
+
+type gobCodec[T any] struct{}
+
+// Gob encodes the values by encoding/gob, it keeps unexported types of
+// interfaces if they are registered by gob.Register.
+func Gob[T any]() Codec[T] {
+	return gobCodec[T]{}
+}
+
+func (gobCodec[T]) Marshal(value T) ([]byte, error) {
+	var buf bytes.Buffer
+
+	if err := gob.NewEncoder(&buf).Encode(value); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (gobCodec[T]) Unmarshal(data []byte) (T, error) {
+	var value T
+
+	err := gob.NewDecoder(bytes.NewReader(data)).Decode(&value)
+
+	return value, err
+}
+
+type bytesCodec struct{}
+
+// Bytes stores the values as is.
+func Bytes() Codec[[]byte] {
+	return bytesCodec{}
+}
+
+func (bytesCodec) Marshal(value []byte) ([]byte, error) {
+	return value, nil
+}
+
+func (bytesCodec) Unmarshal(data []byte) ([]byte, error) {
+	return data, nil
+}
@@ -0,0 +1,113 @@
+package tiered
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Melenium2/go-template/pkg/psql"
+)
+
+const defaultInvalidationChannel = "cache_invalidation"
+
+// Invalidation is the message about the deleted keys of the cache.
+type Invalidation struct {
+	Cache string   `json:"cache"`
+	Keys  []string `json:"keys"`
+}
+
+// Invalidator delivers invalidations to all replicas, including the sender.
+type Invalidator interface {
+	Publish(ctx context.Context, inv Invalidation) error
+	Subscribe(fn func(inv Invalidation)) (unsubscribe func())
+}
+
+// Notifier is the Invalidator over LISTEN/NOTIFY. The payload of the
+// notification is limited by Postgres to 8000 bytes, so keys are sent in
+// the batches.
+type Notifier struct {
+	channel  psql.Channel[Invalidation]
+	listener *psql.Listener
+	conn     psql.Execer
+}
+
+// NewNotifier returns the invalidator that publishes by the conn and
+// receives by the listener.
+func NewNotifier(l *psql.Listener, conn psql.Execer) *Notifier {
+	return &Notifier{
+		channel:  psql.NewChannel[Invalidation](defaultInvalidationChannel),
+		listener: l,
+		conn:     conn,
+	}
+}
+
+// notifyBatch is the number of keys in the single notification.
+const notifyBatch = 50
+
+func (n *Notifier) Publish(ctx context.Context, inv Invalidation) error {
+	for keys := range chunks(inv.Keys, notifyBatch) {
+		if err := n.channel.Notify(ctx, n.conn, Invalidation{Cache: inv.Cache, Keys: keys}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (n *Notifier) Subscribe(fn func(inv Invalidation)) (unsubscribe func()) {
+	return n.channel.Subscribe(n.listener, func(_ context.Context, inv Invalidation) {
+		fn(inv)
+	})
+}
+
+func chunks(keys []string, size int) func(yield func([]string) bool) {
+	return func(yield func([]string) bool) {
+		for len(keys) > 0 {
+			n := min(size, len(keys))
+
+			if !yield(keys[:n]) {
+				return
+			}
+
+			keys = keys[n:]
+		}
+	}
+}
+
+// Local is the Invalidator inside the process, for the single replica and
+// tests.
+type Local struct {
+	mu   sync.RWMutex
+	next int
+	subs map[int]func(inv Invalidation)
+}
+
+func NewLocal() *Local {
+	return &Local{subs: make(map[int]func(inv Invalidation))}
+}
+
+func (l *Local) Publish(_ context.Context, inv Invalidation) error {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	for _, fn := range l.subs {
+		fn(inv)
+	}
+
+	return nil
+}
+
+func (l *Local) Subscribe(fn func(inv Invalidation)) (unsubscribe func()) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	id := l.next
+	l.next++
+	l.subs[id] = fn
+
+	return func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		delete(l.subs, id)
+	}
+}
@@ -0,0 +1,100 @@
+package tiered
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jmoiron/sqlx"
+)
+
+const defaultTable = "cache_entries"
+
+// Postgres is the Backend over the unlogged table, it is faster than the
+// regular table, but the values are lost after the crash of Postgres. The
+// values are not deleted after the TTL, only hidden, call Purge
+// periodically.
+type Postgres struct {
+	db    *sqlx.DB
+	table string
+}
+
+// NewPostgres returns the backend over the table, may be schema qualified.
+//
+// Default: cache_entries.
+func NewPostgres(db *sqlx.DB, table ...string) *Postgres {
+	name := defaultTable
+	if len(table) > 0 && table[0] != "" {
+		name = table[0]
+	}
+
+	return &Postgres{
+		db:    db,
+		table: pgx.Identifier(strings.Split(name, ".")).Sanitize(),
+	}
+}
+
+func (p *Postgres) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	var value []byte
+
+	query := fmt.Sprintf(`SELECT value FROM %s
+		WHERE key = $1 AND (expires_at IS NULL OR expires_at > now())`, p.table)
+
+	err := p.db.GetContext(ctx, &value, query, key)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	return value, true, nil
+}
+
+func (p *Postgres) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	var expiresAt *time.Time
+
+	if ttl > 0 {
+		t := time.Now().Add(ttl)
+		expiresAt = &t
+	}
+
+	query := fmt.Sprintf(`INSERT INTO %s (key, value, expires_at) VALUES ($1, $2, $3)
+		ON CONFLICT (key) DO UPDATE SET value = excluded.value, expires_at = excluded.expires_at`, p.table)
+
+	_, err := p.db.ExecContext(ctx, query, key, value, expiresAt)
+
+	return err
+}
+
+func (p *Postgres) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	placeholders := make([]string, 0, len(keys))
+	args := make([]any, 0, len(keys))
+
+	for _, k := range keys {
+		args = append(args, k)
+		placeholders = append(placeholders, fmt.Sprintf("$%d", len(args)))
+	}
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE key IN (%s)", p.table, strings.Join(placeholders, ", "))
+
+	_, err := p.db.ExecContext(ctx, query, args...)
+
+	return err
+}
+
+// Purge deletes expired values. Can be used as the task of the scheduler.
+func (p *Postgres) Purge(ctx context.Context) error {
+	_, err := p.db.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s WHERE expires_at <= now()", p.table))
+
+	return err
+}
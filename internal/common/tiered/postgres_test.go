@@ -0,0 +1,108 @@
+package tiered
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/Melenium2/go-template/pkg/psql"
+)
+
+type PostgresSuite struct {
+	suite.Suite
+
+	sqlMock sqlmock.Sqlmock
+	db      *sqlx.DB
+	backend *Postgres
+}
+
+func (suite *PostgresSuite) SetupTest() {
+	db, mock, _ := sqlmock.New()
+
+	suite.sqlMock = mock
+	suite.db = sqlx.NewDb(db, "postgres")
+	suite.backend = NewPostgres(suite.db, "public.cache_entries")
+}
+
+func (suite *PostgresSuite) TearDownTest() {
+	suite.Assert().NoError(suite.sqlMock.ExpectationsWereMet())
+}
+
+func (suite *PostgresSuite) TestGet_Should_return_value_that_is_not_expired() {
+	suite.sqlMock.ExpectQuery(`SELECT value FROM "public"."cache_entries" WHERE key = \$1 AND \(expires_at IS NULL OR expires_at > now\(\)\)`).
+		WithArgs("users:1").
+		WillReturnRows(sqlmock.NewRows([]string{"value"}).AddRow([]byte("bob")))
+
+	value, ok, err := suite.backend.Get(context.Background(), "users:1")
+	suite.Require().NoError(err)
+	suite.Assert().True(ok)
+	suite.Assert().Equal([]byte("bob"), value)
+}
+
+func (suite *PostgresSuite) TestGet_Should_return_false_if_not_found() {
+	suite.sqlMock.ExpectQuery(`SELECT value FROM`).WithArgs("users:1").
+		WillReturnRows(sqlmock.NewRows([]string{"value"}))
+
+	_, ok, err := suite.backend.Get(context.Background(), "users:1")
+	suite.Require().NoError(err)
+	suite.Assert().False(ok)
+}
+
+func (suite *PostgresSuite) TestSet_Should_upsert_value_with_expiration() {
+	suite.sqlMock.ExpectExec(`INSERT INTO "public"."cache_entries" \(key, value, expires_at\) VALUES \(\$1, \$2, \$3\) ON CONFLICT \(key\) DO UPDATE`).
+		WithArgs("users:1", []byte("bob"), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.sqlMock.ExpectExec(`INSERT INTO`).
+		WithArgs("users:2", []byte("alice"), nil).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	suite.Require().NoError(suite.backend.Set(context.Background(), "users:1", []byte("bob"), time.Minute))
+	suite.Require().NoError(suite.backend.Set(context.Background(), "users:2", []byte("alice"), 0))
+}
+
+func (suite *PostgresSuite) TestDelete_Should_delete_keys() {
+	suite.sqlMock.ExpectExec(`DELETE FROM "public"."cache_entries" WHERE key IN \(\$1, \$2\)`).
+		WithArgs("users:1", "users:2").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	suite.Require().NoError(suite.backend.Delete(context.Background(), "users:1", "users:2"))
+	suite.Require().NoError(suite.backend.Delete(context.Background()))
+}
+
+func (suite *PostgresSuite) TestPurge_Should_delete_expired_values() {
+	suite.sqlMock.ExpectExec(`DELETE FROM "public"."cache_entries" WHERE expires_at <= now\(\)`).
+		WillReturnResult(sqlmock.NewResult(0, 5))
+
+	suite.Require().NoError(suite.backend.Purge(context.Background()))
+}
+
+func (suite *PostgresSuite) TestNotifier_Publish_Should_send_keys_in_batches() {
+	keys := make([]string, notifyBatch+1)
+
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key-%d", i)
+	}
+
+	for _, batch := range [][]string{keys[:notifyBatch], keys[notifyBatch:]} {
+		payload, _ := json.Marshal(Invalidation{Cache: "users", Keys: batch})
+
+		suite.sqlMock.ExpectExec(`SELECT pg_notify`).
+			WithArgs(defaultInvalidationChannel, string(payload)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+
+	n := NewNotifier(psql.NewListener(nil), suite.db)
+
+	err := n.Publish(context.Background(), Invalidation{Cache: "users", Keys: keys})
+	suite.Require().NoError(err)
+}
+
+func TestPostgresSuite(t *testing.T) {
+	suite.Run(t, new(PostgresSuite))
+}
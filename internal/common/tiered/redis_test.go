@@ -0,0 +1,50 @@
+package tiered
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redismock/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedis_Should_set_get_and_delete_values(t *testing.T) {
+	var (
+		ctx          = context.Background()
+		client, mock = redismock.NewClientMock()
+		backend      = NewRedis(client)
+	)
+
+	mock.ExpectSet("users:1", []byte("bob"), time.Minute).SetVal("OK")
+	mock.ExpectGet("users:1").SetVal("bob")
+	mock.ExpectGet("users:2").RedisNil()
+	mock.ExpectDel("users:1", "users:2").SetVal(1)
+
+	require.NoError(t, backend.Set(ctx, "users:1", []byte("bob"), time.Minute))
+
+	value, ok, err := backend.Get(ctx, "users:1")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []byte("bob"), value)
+
+	_, ok, err = backend.Get(ctx, "users:2")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, backend.Delete(ctx, "users:1", "users:2"))
+	require.NoError(t, backend.Delete(ctx))
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRedis_Should_return_error(t *testing.T) {
+	client, mock := redismock.NewClientMock()
+	mock.ExpectGet("users:1").SetErr(errors.New("NOAUTH Authentication required"))
+
+	_, ok, err := NewRedis(client).Get(context.Background(), "users:1")
+	require.ErrorContains(t, err, "NOAUTH")
+	assert.False(t, ok)
+}
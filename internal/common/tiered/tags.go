@@ -0,0 +1,111 @@
+package tiered
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/Melenium2/go-template/internal/common/tenant"
+	"github.com/Melenium2/go-template/internal/common/tx"
+)
+
+const (
+	tagsCache = "tags"
+	// tagTTL is the TTL of the tag versions, the values with the expired tag
+	// are loaded again.
+	tagTTL = 24 * time.Hour
+)
+
+// Tags is the registry of the tag versions. The cached value keeps versions
+// of its tags, the value is stale if any version is changed. Invalidation of
+// the tag changes its version, so all values with the tag are stale at once.
+// Tags are scoped by the tenant of the context, tenants do not invalidate
+// values of each other.
+type Tags struct {
+	versions *Cache[string]
+}
+
+// NewTags returns the registry over the backend and the invalidator, both
+// are optional as for the Cache.
+func NewTags(backend Backend, invalidator Invalidator) *Tags {
+	opts := []Option{WithTTL(tagTTL)}
+
+	if backend != nil {
+		opts = append(opts, WithBackend(backend))
+	}
+
+	if invalidator != nil {
+		opts = append(opts, WithInvalidator(invalidator))
+	}
+
+	return &Tags{
+		versions: New(tagsCache, JSON[string](), opts...),
+	}
+}
+
+// Versions returns the current versions of the tags, new versions are
+// created for unknown tags.
+func (t *Tags) Versions(ctx context.Context, tags ...string) (map[string]string, error) {
+	versions := make(map[string]string, len(tags))
+
+	for _, tag := range tags {
+		v, err := t.versions.GetOrLoad(ctx, tenant.Scope(ctx, tag), func(context.Context) (string, error) {
+			return newVersion(), nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		versions[tag] = v
+	}
+
+	return versions, nil
+}
+
+// Invalidate makes the values with the tags stale. Inside the transaction the
+// tags are invalidated only after commit, errors are logged.
+func (t *Tags) Invalidate(ctx context.Context, tags ...string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	scoped := make([]string, 0, len(tags))
+
+	for _, tag := range tags {
+		scoped = append(scoped, tenant.Scope(ctx, tag))
+	}
+
+	err := tx.AfterCommit(ctx, func(ctx context.Context) {
+		if err := t.invalidate(ctx, scoped); err != nil {
+			slog.Error("can not invalidate cache tags", slog.Any("tags", tags), slog.String("error", err.Error()))
+		}
+	})
+	if errors.Is(err, tx.ErrTxNotFound) {
+		return t.invalidate(ctx, scoped)
+	}
+
+	return err
+}
+
+// invalidate sets new versions instead of deleting the old ones, so replicas
+// do not create different versions of the same tag. Set removes the old
+// versions from other replicas.
+func (t *Tags) invalidate(ctx context.Context, tags []string) error {
+	for _, tag := range tags {
+		if err := t.versions.Set(ctx, tag, newVersion()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func newVersion() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,353 @@
+// Package tiered is the two-level cache shared by replicas. The first level
+// is the in-memory LRU of the replica (helper/cache), the second level is the
+// Backend shared by all replicas: the unlogged Postgres table (see
+// db/migrations/*_cache.up.sql) or Redis. Values are serialized by the
+// Codec.
+//
+// Concurrent loads of the same key on the replica wait for the single
+// loader, and TTLs of the second level are jittered, so keys set at the same
+// time do not expire together. Set and deleted keys are removed from the
+// first level of other replicas by the Invalidator.
+//
+// Example:
+//
+//	users := tiered.New("users", tiered.JSON[User](),
+//		tiered.WithBackend(c.CacheBackend),
+//		tiered.WithInvalidator(c.CacheInvalidator),
+//		tiered.WithTTL(time.Hour),
+//	)
+//
+//	user, err := users.GetOrLoad(ctx, id, func(ctx context.Context) (User, error) {
+//		return repo.Get(ctx, id)
+//	})
+package tiered
+
+import (
+	"context"
+	"hash/maphash"
+	"log/slog"
+	"math/rand/v2"
+	"sync/atomic"
+	"time"
+
+	"github.com/Melenium2/go-template/internal/common/helper/cache"
+)
+
+const (
+	defaultTTL        = 10 * time.Minute
+	defaultLocalTTL   = time.Minute
+	defaultLocalLimit = 1000
+	// ttlJitter is the part of the TTL of the second level that is randomly
+	// added or subtracted.
+	ttlJitter = 0.1
+	// generations is the number of the counters of key changes, keys share
+	// the counters by the hash.
+	generations = 256
+)
+
+// Backend is the second level of the cache shared by replicas.
+type Backend interface {
+	// Get returns false if the key is not found or expired.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set sets the value, zero ttl means the value is not expired.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+}
+
+type options struct {
+	backend     Backend
+	invalidator Invalidator
+	ttl         time.Duration
+	localTTL    time.Duration
+	localLimit  int
+}
+
+// Option configures the cache.
+type Option func(o *options)
+
+// WithBackend sets the second level, without it the cache is the first level
+// only.
+func WithBackend(b Backend) Option {
+	return func(o *options) {
+		o.backend = b
+	}
+}
+
+// WithInvalidator removes set and deleted keys from the first level of other
+// replicas.
+func WithInvalidator(i Invalidator) Option {
+	return func(o *options) {
+		o.invalidator = i
+	}
+}
+
+// WithTTL sets the TTL of the values in the second level.
+//
+// Default: defaultTTL.
+func WithTTL(d time.Duration) Option {
+	return func(o *options) {
+		o.ttl = d
+	}
+}
+
+// WithLocal sets the TTL and the number of keys of the first level. The TTL
+// limits staleness if the invalidation is lost.
+//
+// Default: defaultLocalTTL, defaultLocalLimit.
+func WithLocal(ttl time.Duration, limit int) Option {
+	return func(o *options) {
+		o.localTTL = ttl
+		o.localLimit = limit
+	}
+}
+
+// Cache is the two-level cache of the values T.
+type Cache[T any] struct {
+	name  string
+	codec Codec[T]
+	opts  options
+
+	local       *cache.Cache[string, T]
+	unsubscribe func()
+
+	// gens are changed before the keys are set or deleted, so GetOrLoad
+	// finds that the loaded value may be stale.
+	gens [generations]atomic.Uint64
+	seed maphash.Seed
+}
+
+// New returns the cache, the name must be unique, it is the prefix of the
+// keys in the second level.
+func New[T any](name string, codec Codec[T], opts ...Option) *Cache[T] {
+	o := options{
+		ttl:        defaultTTL,
+		localTTL:   defaultLocalTTL,
+		localLimit: defaultLocalLimit,
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	c := &Cache[T]{
+		name:        name,
+		codec:       codec,
+		opts:        o,
+		local:       cache.NewCache[string, T](o.localLimit).WithTTL(min(o.localTTL, o.ttl)),
+		unsubscribe: func() {},
+		seed:        maphash.MakeSeed(),
+	}
+
+	if o.invalidator != nil {
+		c.unsubscribe = o.invalidator.Subscribe(func(inv Invalidation) {
+			if inv.Cache != c.name {
+				return
+			}
+
+			for _, key := range inv.Keys {
+				c.gen(key).Add(1)
+				c.local.Delete(key)
+			}
+		})
+	}
+
+	return c
+}
+
+// Close stops receiving invalidations.
+func (c *Cache[T]) Close() {
+	c.unsubscribe()
+}
+
+// Get returns the value from the first or the second level.
+func (c *Cache[T]) Get(ctx context.Context, key string) (T, bool, error) {
+	if value, ok := c.local.Get(key); ok {
+		return value, true, nil
+	}
+
+	return c.remote(ctx, key)
+}
+
+func (c *Cache[T]) remote(ctx context.Context, key string) (T, bool, error) {
+	var zero T
+
+	if c.opts.backend == nil {
+		return zero, false, nil
+	}
+
+	raw, ok, err := c.opts.backend.Get(ctx, c.key(key))
+	if err != nil || !ok {
+		return zero, false, err
+	}
+
+	value, err := c.codec.Unmarshal(raw)
+	if err != nil {
+		return zero, false, err
+	}
+
+	c.local.Set(key, value)
+
+	return value, true, nil
+}
+
+// Set sets the value in both levels and removes the old value from the first
+// level of other replicas.
+func (c *Cache[T]) Set(ctx context.Context, key string, value T) error {
+	c.gen(key).Add(1)
+
+	if c.opts.backend != nil {
+		raw, err := c.codec.Marshal(value)
+		if err != nil {
+			return err
+		}
+
+		if err = c.opts.backend.Set(ctx, c.key(key), raw, jitter(c.opts.ttl)); err != nil {
+			c.local.Delete(key)
+
+			return err
+		}
+	}
+
+	// the invalidation is delivered to the sender too, so the value is set to
+	// the first level after it is published.
+	if c.opts.invalidator != nil {
+		if err := c.opts.invalidator.Publish(ctx, Invalidation{Cache: c.name, Keys: []string{key}}); err != nil {
+			c.local.Delete(key)
+
+			return err
+		}
+	}
+
+	c.local.Set(key, value)
+
+	return nil
+}
+
+// Delete removes the keys from both levels and from the first level of other
+// replicas.
+func (c *Cache[T]) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	for _, key := range keys {
+		c.gen(key).Add(1)
+		c.local.Delete(key)
+	}
+
+	if c.opts.backend != nil {
+		remote := make([]string, 0, len(keys))
+
+		for _, key := range keys {
+			remote = append(remote, c.key(key))
+		}
+
+		if err := c.opts.backend.Delete(ctx, remote...); err != nil {
+			return err
+		}
+	}
+
+	if c.opts.invalidator == nil {
+		return nil
+	}
+
+	return c.opts.invalidator.Publish(ctx, Invalidation{Cache: c.name, Keys: keys})
+}
+
+// GetOrLoad returns the cached value or calls the loader and caches its
+// value. Concurrent calls for the same key on the replica wait for the single
+// loader with the context of the first call. Errors of the second level are
+// logged, the value is loaded as if it is not found.
+//
+// If the key is set, deleted or invalidated during the load, the loaded
+// value is returned, but it is removed from both levels, because it may be
+// older than the change.
+func (c *Cache[T]) GetOrLoad(ctx context.Context, key string, loader func(ctx context.Context) (T, error)) (T, error) {
+	gen := c.gen(key).Load()
+
+	value, err := c.local.GetOrLoad(key, func() (T, error) {
+		value, ok, err := c.remote(ctx, key)
+		if err != nil {
+			slog.Warn("can not get value from cache backend",
+				slog.String("cache", c.name),
+				slog.String("error", err.Error()),
+			)
+		}
+
+		if ok {
+			return value, nil
+		}
+
+		value, err = loader(ctx)
+		if err != nil {
+			return value, err
+		}
+
+		if c.opts.backend == nil {
+			return value, nil
+		}
+
+		raw, err := c.codec.Marshal(value)
+		if err != nil {
+			return value, err
+		}
+
+		if err = c.opts.backend.Set(ctx, c.key(key), raw, jitter(c.opts.ttl)); err != nil {
+			slog.Warn("can not set value to cache backend",
+				slog.String("cache", c.name),
+				slog.String("error", err.Error()),
+			)
+		}
+
+		return value, nil
+	})
+	if err != nil {
+		return value, err
+	}
+
+	// the generation is changed before the key is deleted, so the value set
+	// after this check is removed by Delete itself.
+	if c.gen(key).Load() != gen {
+		c.evict(ctx, key)
+	}
+
+	return value, nil
+}
+
+// evict removes the key from both levels without the invalidation of other
+// replicas.
+func (c *Cache[T]) evict(ctx context.Context, key string) {
+	c.local.Delete(key)
+
+	if c.opts.backend == nil {
+		return
+	}
+
+	if err := c.opts.backend.Delete(ctx, c.key(key)); err != nil {
+		slog.Warn("can not delete stale value from cache backend",
+			slog.String("cache", c.name),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+func (c *Cache[T]) gen(key string) *atomic.Uint64 {
+	return &c.gens[maphash.String(c.seed, key)%generations]
+}
+
+func (c *Cache[T]) key(key string) string {
+	return c.name + ":" + key
+}
+
+func jitter(ttl time.Duration) time.Duration {
+	if ttl <= 0 {
+		return ttl
+	}
+
+	delta := time.Duration(float64(ttl) * ttlJitter)
+	if delta <= 0 {
+		return ttl
+	}
+
+	return ttl - delta + rand.N(2*delta) //nolint:gosec
+}
@@ -0,0 +1,255 @@
+package tiered
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memory is the Backend shared by the caches of the test replicas.
+type memory struct {
+	mu     sync.Mutex
+	values map[string][]byte
+	ttls   map[string]time.Duration
+	err    error
+}
+
+func newMemory() *memory {
+	return &memory{values: make(map[string][]byte), ttls: make(map[string]time.Duration)}
+}
+
+func (m *memory) Get(_ context.Context, key string) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	value, ok := m.values[key]
+
+	return value, ok, m.err
+}
+
+func (m *memory) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.values[key] = value
+	m.ttls[key] = ttl
+
+	return m.err
+}
+
+func (m *memory) Delete(_ context.Context, keys ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, k := range keys {
+		delete(m.values, k)
+	}
+
+	return m.err
+}
+
+type user struct {
+	Name string `json:"name"`
+}
+
+func TestCache_GetOrLoad_Should_share_value_between_replicas(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		backend = newMemory()
+		loads   atomic.Int32
+	)
+
+	replica1 := New("users", JSON[user](), WithBackend(backend), WithTTL(time.Hour))
+	replica2 := New("users", JSON[user](), WithBackend(backend), WithTTL(time.Hour))
+
+	loader := func(context.Context) (user, error) {
+		loads.Add(1)
+
+		return user{Name: "bob"}, nil
+	}
+
+	value, err := replica1.GetOrLoad(ctx, "1", loader)
+	require.NoError(t, err)
+	assert.Equal(t, user{Name: "bob"}, value)
+
+	assert.Equal(t, []byte(`{"name":"bob"}`), backend.values["users:1"])
+	assert.InDelta(t, time.Hour, backend.ttls["users:1"], float64(time.Hour)*ttlJitter)
+
+	value, err = replica2.GetOrLoad(ctx, "1", loader)
+	require.NoError(t, err)
+	assert.Equal(t, user{Name: "bob"}, value)
+	assert.Equal(t, int32(1), loads.Load())
+
+	// the second replica caches the value of the second level locally.
+	backend.values["users:1"] = []byte(`{"name":"alice"}`)
+
+	value, ok, err := replica2.Get(ctx, "1")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, user{Name: "bob"}, value)
+}
+
+func TestCache_GetOrLoad_Should_load_once_concurrent(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		c       = New("users", JSON[user](), WithBackend(newMemory()))
+		loads   atomic.Int32
+		release = make(chan struct{})
+		wg      sync.WaitGroup
+	)
+
+	wg.Add(10)
+
+	for range 10 {
+		go func() {
+			defer wg.Done()
+
+			value, err := c.GetOrLoad(ctx, "1", func(context.Context) (user, error) {
+				loads.Add(1)
+				<-release
+
+				return user{Name: "bob"}, nil
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, "bob", value.Name)
+		}()
+	}
+
+	assert.Eventually(t, func() bool { return loads.Load() == 1 }, time.Second, time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), loads.Load())
+}
+
+func TestCache_GetOrLoad_Should_not_keep_value_deleted_during_load(t *testing.T) {
+	var (
+		ctx         = context.Background()
+		backend     = newMemory()
+		invalidator = NewLocal()
+	)
+
+	replica1 := New("users", JSON[user](), WithBackend(backend), WithInvalidator(invalidator))
+	replica2 := New("users", JSON[user](), WithBackend(backend), WithInvalidator(invalidator))
+
+	for _, deleter := range []*Cache[user]{replica1, replica2} {
+		value, err := replica1.GetOrLoad(ctx, "1", func(ctx context.Context) (user, error) {
+			// the user is changed and deleted from the cache after the load.
+			require.NoError(t, deleter.Delete(ctx, "1"))
+
+			return user{Name: "bob"}, nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "bob", value.Name)
+
+		assert.NotContains(t, backend.values, "users:1")
+
+		_, ok, err := replica1.Get(ctx, "1")
+		require.NoError(t, err)
+		assert.False(t, ok)
+	}
+}
+
+func TestCache_GetOrLoad_Should_load_if_backend_fails(t *testing.T) {
+	backend := newMemory()
+	backend.err = errors.New("unavailable")
+
+	c := New("users", JSON[user](), WithBackend(backend))
+
+	value, err := c.GetOrLoad(context.Background(), "1", func(context.Context) (user, error) {
+		return user{Name: "bob"}, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "bob", value.Name)
+
+	_, _, err = c.Get(context.Background(), "2")
+	assert.ErrorIs(t, err, backend.err)
+}
+
+func TestCache_Delete_Should_invalidate_other_replicas(t *testing.T) {
+	var (
+		ctx         = context.Background()
+		backend     = newMemory()
+		invalidator = NewLocal()
+	)
+
+	replica1 := New("users", Gob[user](), WithBackend(backend), WithInvalidator(invalidator))
+	replica2 := New("users", Gob[user](), WithBackend(backend), WithInvalidator(invalidator))
+	other := New("groups", Gob[user](), WithInvalidator(invalidator))
+
+	require.NoError(t, replica1.Set(ctx, "1", user{Name: "bob"}))
+	require.NoError(t, other.Set(ctx, "1", user{Name: "admins"}))
+
+	_, ok, err := replica2.Get(ctx, "1")
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	require.NoError(t, replica1.Delete(ctx, "1"))
+
+	for _, c := range []*Cache[user]{replica1, replica2} {
+		_, ok, err = c.Get(ctx, "1")
+		require.NoError(t, err)
+		assert.False(t, ok)
+	}
+
+	_, ok, _ = other.Get(ctx, "1")
+	assert.True(t, ok)
+
+	replica2.Close()
+	require.NoError(t, replica2.Set(ctx, "1", user{Name: "bob"}))
+	require.NoError(t, replica1.Delete(ctx, "1"))
+
+	// the closed cache does not receive invalidations.
+	_, ok, _ = replica2.Get(ctx, "1")
+	assert.True(t, ok)
+}
+
+func TestCache_Set_Should_invalidate_other_replicas(t *testing.T) {
+	var (
+		ctx         = context.Background()
+		backend     = newMemory()
+		invalidator = NewLocal()
+	)
+
+	replica1 := New("users", Gob[user](), WithBackend(backend), WithInvalidator(invalidator))
+	replica2 := New("users", Gob[user](), WithBackend(backend), WithInvalidator(invalidator))
+
+	require.NoError(t, replica1.Set(ctx, "1", user{Name: "bob"}))
+
+	_, ok, err := replica2.Get(ctx, "1")
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	require.NoError(t, replica1.Set(ctx, "1", user{Name: "alice"}))
+
+	for _, c := range []*Cache[user]{replica1, replica2} {
+		value, ok, err := c.Get(ctx, "1")
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, "alice", value.Name)
+	}
+}
+
+func TestCodec_Should_round_trip_values(t *testing.T) {
+	for name, codec := range map[string]Codec[user]{"json": JSON[user](), "gob": Gob[user]()} {
+		t.Run(name, func(t *testing.T) {
+			raw, err := codec.Marshal(user{Name: "bob"})
+			require.NoError(t, err)
+
+			value, err := codec.Unmarshal(raw)
+			require.NoError(t, err)
+			assert.Equal(t, user{Name: "bob"}, value)
+		})
+	}
+
+	raw, err := Bytes().Marshal([]byte("raw"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("raw"), raw)
+}
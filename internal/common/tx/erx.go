@@ -2,4 +2,7 @@ package tx
 
 import "errors"
 
-var ErrTxNotFound = errors.New("can not find transaction inside context")
+var (
+	ErrTxNotFound   = errors.New("can not find transaction inside context")
+	ErrConnNotFound = errors.New("can not find connection of transaction inside context")
+)
@@ -8,7 +8,11 @@ import (
 
 type txCtxKey uint8
 
-const txKey txCtxKey = 1 << 7
+const (
+	txKey    txCtxKey = 1 << 7
+	connKey  txCtxKey = 1 << 6
+	hooksKey txCtxKey = 1 << 5
+)
 
 func extractTx(ctx context.Context) (*sqlx.Tx, error) {
 	tx, ok := ctx.Value(txKey).(*sqlx.Tx)
@@ -22,3 +26,38 @@ func extractTx(ctx context.Context) (*sqlx.Tx, error) {
 func injectTx(ctx context.Context, tx *sqlx.Tx) context.Context {
 	return context.WithValue(ctx, txKey, tx)
 }
+
+func extractConn(ctx context.Context) (*sqlx.Conn, error) {
+	conn, ok := ctx.Value(connKey).(*sqlx.Conn)
+	if !ok {
+		return nil, ErrTxNotFound
+	}
+
+	return conn, nil
+}
+
+func injectConn(ctx context.Context, conn *sqlx.Conn) context.Context {
+	return context.WithValue(ctx, connKey, conn)
+}
+
+func extractHooks(ctx context.Context) (*hooks, error) {
+	h, ok := ctx.Value(hooksKey).(*hooks)
+	if !ok {
+		return nil, ErrTxNotFound
+	}
+
+	return h, nil
+}
+
+func injectHooks(ctx context.Context, h *hooks) context.Context {
+	return context.WithValue(ctx, hooksKey, h)
+}
+
+// withoutTx returns the context without the finished transaction, so the
+// hooks run queries outside of it.
+func withoutTx(ctx context.Context) context.Context {
+	ctx = context.WithValue(ctx, txKey, nil)
+	ctx = context.WithValue(ctx, connKey, nil)
+
+	return context.WithValue(ctx, hooksKey, nil)
+}
@@ -3,6 +3,7 @@ package tx
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"runtime/debug"
 	"sync"
@@ -46,11 +47,27 @@ type ManagerTx interface {
 	//		...
 	//	}
 	Conn(ctx context.Context) sqlx.ExtContext
+	// Raw returns the connection for the native API of the driver, for
+	// example COPY of pgx (see psql.CopyFrom). Inside the transaction it is
+	// the connection of the transaction, so the native calls are the part of
+	// it. Outside the transaction the connection is taken from the pool for
+	// each call. Extensions are not applied to the raw connection.
+	//
+	// Example:
+	//	n, err := psql.CopyFrom(ctx, tx.Manager().Raw(ctx), "orders", columns, rows)
+	Raw(ctx context.Context) RawConn
+}
+
+// RawConn gives access to the driver connection, implemented by *sql.Conn.
+type RawConn interface {
+	Raw(f func(driverConn any) error) error
 }
 
 type manager struct {
 	db         *sqlx.DB
 	decorators []Extension
+	// tenancy is set by SetupTenantManager.
+	tenancy *TenantConfig
 }
 
 func SetupManager(db *sqlx.DB, extensions ...Extension) {
@@ -77,12 +94,25 @@ func (m *manager) StartTx(ctx context.Context, opts ...sql.TxOptions) (context.C
 		txOpts = opts[0]
 	}
 
-	tx, err := m.db.BeginTxx(ctx, &txOpts)
+	// the transaction is started on the dedicated connection, so the native
+	// API of the driver can be used inside the transaction by Raw.
+	conn, err := m.db.Connx(ctx)
 	if err != nil {
 		return ctx, err
 	}
 
-	return injectTx(ctx, tx), nil
+	tx, err := conn.BeginTxx(ctx, &txOpts)
+	if err != nil {
+		return ctx, errors.Join(err, conn.Close())
+	}
+
+	if m.tenancy != nil {
+		if err = m.tenancy.switchTenant(ctx, tx); err != nil {
+			return ctx, errors.Join(err, tx.Rollback(), conn.Close())
+		}
+	}
+
+	return injectHooks(injectConn(injectTx(ctx, tx), conn), &hooks{}), nil
 }
 
 func (m *manager) Commit(ctx context.Context) error {
@@ -91,7 +121,15 @@ func (m *manager) Commit(ctx context.Context) error {
 		return ErrTxNotFound
 	}
 
-	return tx.Commit()
+	if err = errors.Join(tx.Commit(), releaseConn(ctx)); err != nil {
+		return err
+	}
+
+	if h, err := extractHooks(ctx); err == nil {
+		h.run(withoutTx(ctx))
+	}
+
+	return nil
 }
 
 func (m *manager) Rollback(ctx context.Context) error {
@@ -100,7 +138,21 @@ func (m *manager) Rollback(ctx context.Context) error {
 		return ErrTxNotFound
 	}
 
-	return tx.Rollback()
+	return errors.Join(tx.Rollback(), releaseConn(ctx))
+}
+
+// releaseConn returns the connection of the finished transaction to the pool.
+func releaseConn(ctx context.Context) error {
+	conn, err := extractConn(ctx)
+	if err != nil {
+		return nil
+	}
+
+	if err = conn.Close(); err != nil && !errors.Is(err, sql.ErrConnDone) {
+		return err
+	}
+
+	return nil
 }
 
 func (m *manager) Conn(ctx context.Context) sqlx.ExtContext {
@@ -114,6 +166,45 @@ func (m *manager) Conn(ctx context.Context) sqlx.ExtContext {
 	return m.applyDecorators(conn)
 }
 
+func (m *manager) Raw(ctx context.Context) RawConn {
+	if conn, err := extractConn(ctx); err == nil {
+		return conn
+	}
+
+	// the transaction without the connection can not be used by Raw, the
+	// connection from the pool would run outside the transaction.
+	if _, err := extractTx(ctx); err == nil {
+		return rawError{err: ErrConnNotFound}
+	}
+
+	return poolConn{ctx: ctx, db: m.db}
+}
+
+// poolConn takes the connection from the pool for each Raw call.
+type poolConn struct {
+	ctx context.Context
+	db  *sqlx.DB
+}
+
+func (c poolConn) Raw(f func(driverConn any) error) error {
+	conn, err := c.db.Conn(c.ctx)
+	if err != nil {
+		return err
+	}
+
+	defer conn.Close()
+
+	return conn.Raw(f)
+}
+
+type rawError struct {
+	err error
+}
+
+func (c rawError) Raw(func(driverConn any) error) error {
+	return c.err
+}
+
 func (m *manager) applyDecorators(conn sqlx.ExtContext) sqlx.ExtContext {
 	for _, decorator := range m.decorators {
 		conn = decorator(conn)
@@ -160,3 +251,59 @@ func (m *manager) Do(
 
 	return nil
 }
+
+// InTx reports whether the context contains the transaction.
+func InTx(ctx context.Context) bool {
+	_, err := extractTx(ctx)
+
+	return err == nil
+}
+
+// AfterCommit registers fn that is called after the transaction from the
+// context is committed, hooks are not called after rollback. Hooks are
+// called in the order of registration with the context without the
+// transaction, panics of hooks are recovered. Returns ErrTxNotFound if the
+// context has no transaction, so the caller can run fn immediately.
+//
+// Example:
+//
+//	err := tx.AfterCommit(ctx, func(ctx context.Context) {
+//		_ = orders.Delete(ctx, id.String())
+//	})
+func AfterCommit(ctx context.Context, fn func(ctx context.Context)) error {
+	h, err := extractHooks(ctx)
+	if err != nil {
+		return ErrTxNotFound
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.fns = append(h.fns, fn)
+
+	return nil
+}
+
+type hooks struct {
+	mu  sync.Mutex
+	fns []func(ctx context.Context)
+}
+
+func (h *hooks) run(ctx context.Context) {
+	h.mu.Lock()
+	fns := h.fns
+	h.fns = nil
+	h.mu.Unlock()
+
+	for _, fn := range fns {
+		func() {
+			defer func() {
+				if p := recover(); p != nil {
+					debug.PrintStack()
+				}
+			}()
+
+			fn(ctx)
+		}()
+	}
+}
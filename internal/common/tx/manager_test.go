@@ -105,6 +105,47 @@ func (suite *ManagerSuite) TestConn_Should_return_default_conn() {
 	suite.Assert().Equal(suite.db, conn)
 }
 
+func (suite *ManagerSuite) TestRaw_Should_return_conn_of_transaction_until_commit() {
+	suite.sqlMock.ExpectBegin()
+
+	ctx, err := managerOnce.StartTx(context.Background())
+	suite.Require().NoError(err)
+
+	conn, err := extractConn(ctx)
+	suite.Require().NoError(err)
+	suite.Assert().Equal(conn, Manager().Raw(ctx))
+	suite.Assert().NoError(Manager().Raw(ctx).Raw(func(driverConn any) error {
+		suite.Assert().NotNil(driverConn)
+
+		return nil
+	}))
+
+	suite.sqlMock.ExpectCommit()
+
+	suite.Require().NoError(managerOnce.Commit(ctx))
+	suite.Assert().ErrorIs(Manager().Raw(ctx).Raw(func(any) error { return nil }), sql.ErrConnDone)
+	suite.Assert().NoError(suite.sqlMock.ExpectationsWereMet())
+}
+
+func (suite *ManagerSuite) TestRaw_Should_not_escape_transaction_without_conn() {
+	ctx := injectTx(context.Background(), &sqlx.Tx{})
+
+	err := Manager().Raw(ctx).Raw(func(any) error { return nil })
+	suite.Assert().ErrorIs(err, ErrConnNotFound)
+}
+
+func (suite *ManagerSuite) TestRaw_Should_take_conn_from_pool_outside_transaction() {
+	called := false
+
+	err := Manager().Raw(context.Background()).Raw(func(any) error {
+		called = true
+
+		return nil
+	})
+	suite.Assert().NoError(err)
+	suite.Assert().True(called)
+}
+
 func (suite *ManagerSuite) TestDo_Should_run_txFunc_with_new_transaction() {
 	suite.sqlMock.ExpectBegin()
 	suite.sqlMock.ExpectCommit()
@@ -193,6 +234,59 @@ func (suite *ManagerSuite) TestDo_Should_run_single_transaction_among_some_Do_fu
 	suite.Assert().NoError(suite.sqlMock.ExpectationsWereMet())
 }
 
+func (suite *ManagerSuite) TestAfterCommit_Should_run_hooks_after_commit_outside_transaction() {
+	suite.sqlMock.ExpectBegin()
+	suite.sqlMock.ExpectCommit()
+
+	var calls []string
+
+	err := Manager().Do(context.Background(), func(ctx context.Context) error {
+		suite.Assert().True(InTx(ctx))
+
+		for _, name := range []string{"first", "panic", "second"} {
+			err := AfterCommit(ctx, func(ctx context.Context) {
+				suite.Assert().False(InTx(ctx))
+
+				if name == "panic" {
+					panic("hook")
+				}
+
+				calls = append(calls, name)
+			})
+			suite.Require().NoError(err)
+		}
+
+		suite.Assert().Empty(calls)
+
+		return nil
+	})
+	suite.Assert().NoError(err)
+	suite.Assert().Equal([]string{"first", "second"}, calls)
+	suite.Assert().NoError(suite.sqlMock.ExpectationsWereMet())
+}
+
+func (suite *ManagerSuite) TestAfterCommit_Should_not_run_hooks_after_rollback() {
+	suite.sqlMock.ExpectBegin()
+	suite.sqlMock.ExpectRollback()
+
+	var called bool
+
+	err := Manager().Do(context.Background(), func(ctx context.Context) error {
+		_ = AfterCommit(ctx, func(context.Context) { called = true })
+
+		return errors.New("error")
+	})
+	suite.Assert().Error(err)
+	suite.Assert().False(called)
+	suite.Assert().NoError(suite.sqlMock.ExpectationsWereMet())
+}
+
+func (suite *ManagerSuite) TestAfterCommit_Should_return_error_outside_transaction() {
+	err := AfterCommit(context.Background(), func(context.Context) {})
+	suite.Assert().ErrorIs(err, ErrTxNotFound)
+	suite.Assert().False(InTx(context.Background()))
+}
+
 func TestManagerSuite(t *testing.T) {
 	suite.Run(t, new(ManagerSuite))
 }
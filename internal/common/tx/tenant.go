@@ -0,0 +1,215 @@
+package tx
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/Melenium2/go-template/internal/common/tenant"
+)
+
+const (
+	defaultSchemaPrefix  = "tenant_"
+	defaultTenantSetting = "app.tenant_id"
+)
+
+var ErrTenantOutsideTx = errors.New("tenant queries must run inside tx.Manager().Do")
+
+// TenantMode describes how the transaction is switched to the tenant.
+type TenantMode uint8
+
+const (
+	// TenantSearchPath sets search_path of the transaction to the schema of
+	// the tenant, see TenantConfig.Schema.
+	TenantSearchPath TenantMode = iota + 1
+	// TenantSetting sets the runtime setting of the transaction to the
+	// tenant ID. The setting is used by row-level security policies:
+	//
+	//	CREATE POLICY tenant_isolation ON orders
+	//		USING (tenant_id = current_setting('app.tenant_id'));
+	TenantSetting
+)
+
+// TenantConfig describes multi-tenancy of the database.
+type TenantConfig struct {
+	Mode TenantMode
+	// SchemaPrefix is prepended to the tenant ID to get the schema name.
+	//
+	// Default: tenant_.
+	SchemaPrefix string
+	// SharedSchemas are appended to search_path after the tenant schema, for
+	// example public with extensions.
+	//
+	// Optional.
+	SharedSchemas []string
+	// Setting is the name of the runtime setting for TenantSetting mode.
+	//
+	// Default: app.tenant_id.
+	Setting string
+}
+
+// Schema returns the schema name of the tenant.
+func (c TenantConfig) Schema(id string) string {
+	prefix := c.SchemaPrefix
+	if prefix == "" {
+		prefix = defaultSchemaPrefix
+	}
+
+	return prefix + id
+}
+
+// SetupTenantManager is like SetupManager, but each transaction started by
+// Do is switched to the tenant from the context, see tenant.WithID. Settings
+// are applied by set_config(..., true), so they live until the end of the
+// transaction and the connection is returned to the pool clean.
+//
+// Queries outside the transaction can not be switched safely, use
+// TenantGuard extension to reject them.
+func SetupTenantManager(db *sqlx.DB, cfg TenantConfig, extensions ...Extension) {
+	once.Do(func() {
+		managerOnce = &manager{
+			db:         db,
+			decorators: extensions,
+			tenancy:    &cfg,
+		}
+	})
+}
+
+// switchTenant applies the settings of the tenant from the context to the
+// transaction. Nothing is changed if the context does not contain tenant.
+func (c TenantConfig) switchTenant(ctx context.Context, tx *sqlx.Tx) error {
+	id, ok := tenant.ID(ctx)
+	if !ok {
+		return nil
+	}
+
+	var err error
+
+	switch c.Mode {
+	case TenantSearchPath:
+		path := []string{pgx.Identifier{c.Schema(id)}.Sanitize()}
+
+		for _, schema := range c.SharedSchemas {
+			path = append(path, pgx.Identifier{schema}.Sanitize())
+		}
+
+		_, err = tx.ExecContext(ctx, "SELECT set_config('search_path', $1, true)", strings.Join(path, ", "))
+	case TenantSetting:
+		setting := c.Setting
+		if setting == "" {
+			setting = defaultTenantSetting
+		}
+
+		_, err = tx.ExecContext(ctx, "SELECT set_config($1, $2, true)", setting, id)
+	default:
+		err = fmt.Errorf("unknown tenant mode %d", c.Mode)
+	}
+
+	if err != nil {
+		return fmt.Errorf("can not switch to tenant %s, %w", id, err)
+	}
+
+	return nil
+}
+
+// TenantGuard is the Extension that rejects queries without the tenant in
+// the context (tenant.ErrNoTenant) and queries outside the transaction
+// (ErrTenantOutsideTx).
+//
+// Example:
+//
+//	tx.SetupTenantManager(db, tx.TenantConfig{Mode: tx.TenantSetting}, tx.TenantGuard)
+func TenantGuard(conn sqlx.ExtContext) sqlx.ExtContext {
+	_, inTx := conn.(*sqlx.Tx)
+
+	return &guardedConn{ExtContext: conn, inTx: inTx}
+}
+
+type guardedConn struct {
+	sqlx.ExtContext
+	inTx bool
+}
+
+func (c *guardedConn) check(ctx context.Context) error {
+	if _, ok := tenant.ID(ctx); !ok {
+		return tenant.ErrNoTenant
+	}
+
+	if !c.inTx {
+		return ErrTenantOutsideTx
+	}
+
+	return nil
+}
+
+func (c *guardedConn) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	if err := c.check(ctx); err != nil {
+		return nil, err
+	}
+
+	return c.ExtContext.QueryContext(ctx, query, args...)
+}
+
+func (c *guardedConn) QueryxContext(ctx context.Context, query string, args ...any) (*sqlx.Rows, error) {
+	if err := c.check(ctx); err != nil {
+		return nil, err
+	}
+
+	return c.ExtContext.QueryxContext(ctx, query, args...)
+}
+
+func (c *guardedConn) QueryRowxContext(ctx context.Context, query string, args ...any) *sqlx.Row {
+	if err := c.check(ctx); err != nil {
+		// sqlx.Row can not be created with the error outside of sqlx, so the
+		// error is returned by the connection that always fails.
+		return rejectedDB(err).QueryRowxContext(ctx, query, args...)
+	}
+
+	return c.ExtContext.QueryRowxContext(ctx, query, args...)
+}
+
+func (c *guardedConn) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	if err := c.check(ctx); err != nil {
+		return nil, err
+	}
+
+	return c.ExtContext.ExecContext(ctx, query, args...)
+}
+
+var (
+	rejectedNoTenant  = sqlx.NewDb(sql.OpenDB(rejectConnector{err: tenant.ErrNoTenant}), "pgx")
+	rejectedOutsideTx = sqlx.NewDb(sql.OpenDB(rejectConnector{err: ErrTenantOutsideTx}), "pgx")
+)
+
+func rejectedDB(err error) *sqlx.DB {
+	if errors.Is(err, tenant.ErrNoTenant) {
+		return rejectedNoTenant
+	}
+
+	return rejectedOutsideTx
+}
+
+// rejectConnector fails every connection attempt with the error.
+type rejectConnector struct {
+	err error
+}
+
+func (c rejectConnector) Connect(context.Context) (driver.Conn, error) {
+	return nil, c.err
+}
+
+func (c rejectConnector) Driver() driver.Driver {
+	return rejectDriver(c)
+}
+
+type rejectDriver rejectConnector
+
+func (d rejectDriver) Open(string) (driver.Conn, error) {
+	return nil, d.err
+}
@@ -0,0 +1,81 @@
+package tx
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Melenium2/go-template/internal/common/tenant"
+)
+
+func TestStartTx_Should_switch_transaction_to_tenant(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  TenantConfig
+		args []driver.Value
+	}{
+		{
+			name: "search path",
+			cfg:  TenantConfig{Mode: TenantSearchPath, SharedSchemas: []string{"public"}},
+			args: []driver.Value{`"tenant_acme", "public"`},
+		},
+		{
+			name: "setting",
+			cfg:  TenantConfig{Mode: TenantSetting},
+			args: []driver.Value{"app.tenant_id", "acme"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+
+			m := &manager{db: sqlx.NewDb(db, "postgres"), tenancy: &tc.cfg}
+
+			ctx, err := tenant.WithID(context.Background(), "acme")
+			require.NoError(t, err)
+
+			mock.ExpectBegin()
+			mock.ExpectExec("SELECT set_config").WithArgs(tc.args...).WillReturnResult(sqlmock.NewResult(0, 1))
+
+			_, err = m.StartTx(ctx)
+			assert.NoError(t, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestTenantGuard_Should_reject_queries_without_tenant_or_outside_tx(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	conn := sqlx.NewDb(db, "postgres")
+	guarded := TenantGuard(conn)
+
+	var id int
+
+	err = guarded.QueryRowxContext(context.Background(), "SELECT 1").Scan(&id)
+	assert.ErrorIs(t, err, tenant.ErrNoTenant)
+
+	ctx, err := tenant.WithID(context.Background(), "acme")
+	require.NoError(t, err)
+
+	_, err = guarded.ExecContext(ctx, "DELETE FROM orders")
+	assert.ErrorIs(t, err, ErrTenantOutsideTx)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM orders").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	require.NoError(t, err)
+
+	_, err = TenantGuard(tx).ExecContext(ctx, "DELETE FROM orders")
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,319 @@
+package validate
+
+import (
+	"fmt"
+	"net/mail"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const tagName = "validate"
+
+var (
+	validatorType = reflect.TypeOf((*Validator)(nil)).Elem()
+
+	// parsed rules of each validated struct type.
+	fieldsCache sync.Map
+	// compiled patterns of the regex rule.
+	regexCache sync.Map
+)
+
+type checkFunc func(rv reflect.Value) (string, bool)
+
+type rule struct {
+	name  string
+	check checkFunc
+}
+
+type field struct {
+	index int
+	name  string
+	rules []rule
+}
+
+func fieldsOf(t reflect.Type) []field {
+	if cached, ok := fieldsCache.Load(t); ok {
+		return cached.([]field) //nolint:forcetypeassert
+	}
+
+	fields := make([]field, 0, t.NumField())
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		if !f.IsExported() {
+			continue
+		}
+
+		tag := f.Tag.Get(tagName)
+		if tag == "-" {
+			continue
+		}
+
+		rules, err := parseRules(tag)
+		if err != nil {
+			// invalid tag is a programmer error, so it must be found as soon as possible.
+			panic(fmt.Sprintf("validate: invalid tag of %s.%s, %s", t.Name(), f.Name, err))
+		}
+
+		fields = append(fields, field{
+			index: i,
+			name:  fieldName(f),
+			rules: rules,
+		})
+	}
+
+	fieldsCache.Store(t, fields)
+
+	return fields
+}
+
+// fieldName returns the name of the field from json tag, because the UI layers
+// render violations to clients that know only json names.
+func fieldName(f reflect.StructField) string {
+	if f.Anonymous {
+		return ""
+	}
+
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	if name != "" && name != "-" {
+		return name
+	}
+
+	return f.Name
+}
+
+func parseRules(tag string) ([]rule, error) {
+	if tag == "" {
+		return nil, nil
+	}
+
+	parts := splitTag(tag)
+	rules := make([]rule, 0, len(parts))
+
+	for _, part := range parts {
+		name, param, _ := strings.Cut(part, "=")
+
+		check, err := makeCheck(name, param)
+		if err != nil {
+			return nil, err
+		}
+
+		rules = append(rules, rule{name: name, check: check})
+	}
+
+	return rules, nil
+}
+
+// splitTag splits tag by commas, escaped commas ("\,") are kept.
+func splitTag(tag string) []string {
+	var (
+		parts []string
+		curr  strings.Builder
+	)
+
+	for i := 0; i < len(tag); i++ {
+		switch {
+		case tag[i] == '\\' && i+1 < len(tag) && tag[i+1] == ',':
+			curr.WriteByte(',')
+			i++
+		case tag[i] == ',':
+			parts = append(parts, curr.String())
+			curr.Reset()
+		default:
+			curr.WriteByte(tag[i])
+		}
+	}
+
+	return append(parts, curr.String())
+}
+
+//revive:disable:cyclomatic
+func makeCheck(name, param string) (checkFunc, error) {
+	switch name {
+	case "omitempty", "dive":
+		return nil, nil
+	case "required":
+		return required, nil
+	case "min", "max", "len":
+		n, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return nil, fmt.Errorf("rule %s requires numeric parameter, got %q", name, param)
+		}
+
+		return bounds(name, n), nil
+	case "oneof":
+		values := strings.Fields(param)
+		if len(values) == 0 {
+			return nil, fmt.Errorf("rule oneof requires at least one value")
+		}
+
+		return oneOf(values), nil
+	case "regex":
+		re, err := compile(param)
+		if err != nil {
+			return nil, err
+		}
+
+		return matches(re), nil
+	case "uuid":
+		return stringCheck("must be a valid UUID", func(s string) bool {
+			return uuid.Validate(s) == nil
+		}), nil
+	case "email":
+		return stringCheck("must be a valid email address", isEmail), nil
+	default:
+		return nil, fmt.Errorf("unknown rule %q", name)
+	}
+}
+
+//revive:enable:cyclomatic
+
+func compile(pattern string) (*regexp.Regexp, error) {
+	if re, ok := regexCache.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil //nolint:forcetypeassert
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	regexCache.Store(pattern, re)
+
+	return re, nil
+}
+
+func required(rv reflect.Value) (string, bool) {
+	switch rv.Kind() { //nolint:exhaustive
+	case reflect.Slice, reflect.Map:
+		if rv.Len() == 0 {
+			return "is required", false
+		}
+	default:
+		if rv.IsZero() {
+			return "is required", false
+		}
+	}
+
+	return "", true
+}
+
+//revive:disable:cognitive-complexity
+func bounds(name string, n float64) checkFunc {
+	return func(rv reflect.Value) (string, bool) {
+		rv = indirect(rv)
+		if !rv.IsValid() {
+			return "", true
+		}
+
+		var (
+			value float64
+			unit  string
+		)
+
+		switch rv.Kind() { //nolint:exhaustive
+		case reflect.String:
+			value, unit = float64(utf8.RuneCountInString(rv.String())), "characters"
+		case reflect.Slice, reflect.Map, reflect.Array:
+			value, unit = float64(rv.Len()), "elements"
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			value = float64(rv.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			value = float64(rv.Uint())
+		case reflect.Float32, reflect.Float64:
+			value = rv.Float()
+		default:
+			return "", true
+		}
+
+		num := strconv.FormatFloat(n, 'f', -1, 64)
+
+		switch {
+		case name == "min" && value < n && unit != "":
+			return fmt.Sprintf("must contain at least %s %s", num, unit), false
+		case name == "min" && value < n:
+			return fmt.Sprintf("must be greater than or equal to %s", num), false
+		case name == "max" && value > n && unit != "":
+			return fmt.Sprintf("must contain at most %s %s", num, unit), false
+		case name == "max" && value > n:
+			return fmt.Sprintf("must be less than or equal to %s", num), false
+		case name == "len" && value != n && unit != "":
+			return fmt.Sprintf("must contain exactly %s %s", num, unit), false
+		case name == "len" && value != n:
+			return fmt.Sprintf("must be equal to %s", num), false
+		}
+
+		return "", true
+	}
+}
+
+//revive:enable:cognitive-complexity
+
+func oneOf(values []string) checkFunc {
+	msg := fmt.Sprintf("must be one of [%s]", strings.Join(values, ", "))
+
+	return func(rv reflect.Value) (string, bool) {
+		rv = indirect(rv)
+		if !rv.IsValid() {
+			return "", true
+		}
+
+		s := fmt.Sprint(rv.Interface())
+
+		for _, v := range values {
+			if s == v {
+				return "", true
+			}
+		}
+
+		return msg, false
+	}
+}
+
+func matches(re *regexp.Regexp) checkFunc {
+	return stringCheck(fmt.Sprintf("must match %s", re.String()), re.MatchString)
+}
+
+func stringCheck(msg string, fn func(string) bool) checkFunc {
+	return func(rv reflect.Value) (string, bool) {
+		rv = indirect(rv)
+		if !rv.IsValid() || rv.Kind() != reflect.String || rv.String() == "" {
+			return "", true
+		}
+
+		if !fn(rv.String()) {
+			return msg, false
+		}
+
+		return "", true
+	}
+}
+
+func isEmail(s string) bool {
+	addr, err := mail.ParseAddress(s)
+	if err != nil {
+		return false
+	}
+
+	// ParseAddress accepts addresses with names like "Bob <bob@example.com>".
+	return addr.Address == s && strings.Contains(s[strings.LastIndexByte(s, '@'):], ".")
+}
+
+func indirect(rv reflect.Value) reflect.Value {
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return reflect.Value{}
+		}
+
+		rv = rv.Elem()
+	}
+
+	return rv
+}
@@ -0,0 +1,241 @@
+package validate
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/Melenium2/go-template/internal/common/helper/str"
+)
+
+const sanitizeTagName = "sanitize"
+
+var (
+	sanitizersMu sync.RWMutex
+	// steps of the "sanitize" tag by name.
+	sanitizers = map[string][]str.Step{
+		"trim":        {str.Func(strings.TrimSpace)},
+		"nfc":         {str.Func(str.NFC)},
+		"nfkc":        {str.Func(str.NFKC)},
+		"bidi":        {str.StripBidi()},
+		"invisible":   {str.Func(str.StripInvisible)},
+		"html":        {str.StripTags()},
+		"escape":      {str.EscapeHTML()},
+		"confusables": {str.FoldConfusables()},
+		"space":       {str.Func(func(s string) string { return str.CollapseSpace(s, str.DropNewLines) })},
+		"lines":       {str.Func(func(s string) string { return str.CollapseSpace(s, str.SingleNewLines) })},
+		"log":         {str.NeutralizeLog()},
+	}
+
+	// parsed sanitizers of each sanitized struct type.
+	sanitizeCache sync.Map
+)
+
+// RegisterSanitizer registers steps of the "sanitize" tag with the name, for
+// example the profanity mask with the project word list. Must be called
+// before the first Sanitize, for example in init().
+func RegisterSanitizer(name string, steps ...str.Step) {
+	sanitizersMu.Lock()
+	defer sanitizersMu.Unlock()
+
+	sanitizers[name] = steps
+}
+
+type sanitizeField struct {
+	index int
+	clean *str.Sanitizer
+}
+
+type sanitizePlan struct {
+	fields []sanitizeField
+	// needed is false if the struct has nothing to sanitize.
+	needed bool
+}
+
+// Sanitize returns the copy of v with strings cleaned by the "sanitize" struct
+// tags. The tag of the string, the pointer to the string or the slice of
+// strings lists the names of sanitizers that are applied in order, see
+// RegisterSanitizer. Nested structs, pointers and slices are sanitized
+// recursively, v itself is not changed. Maps and interfaces are kept as is.
+func Sanitize(v any) any {
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() || !needsSanitize(rv.Type(), nil) {
+		return v
+	}
+
+	out := reflect.New(rv.Type()).Elem()
+	out.Set(rv)
+
+	sanitizeValue(out, nil)
+
+	return out.Interface()
+}
+
+func sanitizeValue(rv reflect.Value, clean *str.Sanitizer) {
+	switch rv.Kind() { //nolint:exhaustive
+	case reflect.String:
+		if clean != nil {
+			rv.SetString(clean.Clean(rv.String()))
+		}
+	case reflect.Pointer:
+		if rv.IsNil() || !needsSanitize(rv.Type().Elem(), clean) {
+			return
+		}
+
+		cp := reflect.New(rv.Type().Elem())
+		cp.Elem().Set(rv.Elem())
+
+		sanitizeValue(cp.Elem(), clean)
+		rv.Set(cp)
+	case reflect.Slice:
+		if rv.IsNil() || !needsSanitize(rv.Type().Elem(), clean) {
+			return
+		}
+
+		cp := reflect.MakeSlice(rv.Type(), rv.Len(), rv.Len())
+		reflect.Copy(cp, rv)
+
+		for i := 0; i < cp.Len(); i++ {
+			sanitizeValue(cp.Index(i), clean)
+		}
+
+		rv.Set(cp)
+	case reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			sanitizeValue(rv.Index(i), clean)
+		}
+	case reflect.Struct:
+		for _, f := range planOf(rv.Type()).fields {
+			sanitizeValue(rv.Field(f.index), f.clean)
+		}
+	}
+}
+
+func needsSanitize(t reflect.Type, clean *str.Sanitizer) bool {
+	needed, _ := needs(t, clean, nil)
+
+	return needed
+}
+
+// noDep means that the result does not depend on the structs being planned.
+const noDep = math.MaxInt
+
+// needs reports whether the values of the type are changed by sanitizing.
+// The result of the struct that is being planned is unknown yet, dep is the
+// lowest depth of such structs the result depends on.
+func needs(t reflect.Type, clean *str.Sanitizer, visiting map[reflect.Type]int) (needed bool, dep int) {
+	switch t.Kind() { //nolint:exhaustive
+	case reflect.String:
+		return clean != nil, noDep
+	case reflect.Pointer, reflect.Slice, reflect.Array:
+		return needs(t.Elem(), clean, visiting)
+	case reflect.Struct:
+		if cached, ok := sanitizeCache.Load(t); ok {
+			return cached.(sanitizePlan).needed, noDep //nolint:forcetypeassert
+		}
+
+		if depth, ok := visiting[t]; ok {
+			return false, depth
+		}
+
+		if visiting == nil {
+			visiting = make(map[reflect.Type]int)
+		}
+
+		plan, dep := buildPlan(t, visiting)
+
+		return plan.needed, dep
+	default:
+		return false, noDep
+	}
+}
+
+func planOf(t reflect.Type) sanitizePlan {
+	if cached, ok := sanitizeCache.Load(t); ok {
+		return cached.(sanitizePlan) //nolint:forcetypeassert
+	}
+
+	plan, _ := buildPlan(t, make(map[reflect.Type]int))
+
+	return plan
+}
+
+// buildPlan builds the plan of the struct, visiting holds the structs being
+// planned by the depth of the recursion. Only the finished plan is cached:
+// the plan that depends on the structs being planned is built again when it
+// is needed, after their plans are cached.
+func buildPlan(t reflect.Type, visiting map[reflect.Type]int) (sanitizePlan, int) {
+	depth := len(visiting)
+	visiting[t] = depth
+
+	defer delete(visiting, t)
+
+	var (
+		plan, recursive sanitizePlan
+		dep             = noDep
+	)
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		if !f.IsExported() {
+			continue
+		}
+
+		clean, err := parseSanitizer(f.Tag.Get(sanitizeTagName))
+		if err != nil {
+			// invalid tag is a programmer error, so it must be found as soon as possible.
+			panic(fmt.Sprintf("validate: invalid sanitize tag of %s.%s, %s", t.Name(), f.Name, err))
+		}
+
+		needed, fieldDep := needs(f.Type, clean, visiting)
+		dep = min(dep, fieldDep)
+
+		switch {
+		case needed:
+			plan.fields = append(plan.fields, sanitizeField{index: i, clean: clean})
+		case fieldDep != noDep:
+			// the field refers to the struct being planned, for example the
+			// pointer or the slice of the struct itself.
+			recursive.fields = append(recursive.fields, sanitizeField{index: i, clean: clean})
+		}
+	}
+
+	// recursive fields are sanitized only if the struct has something to
+	// sanitize besides them.
+	if plan.needed = len(plan.fields) > 0; plan.needed {
+		plan.fields = append(plan.fields, recursive.fields...)
+	}
+
+	if dep < depth {
+		return plan, dep
+	}
+
+	cached, _ := sanitizeCache.LoadOrStore(t, plan)
+
+	return cached.(sanitizePlan), noDep //nolint:forcetypeassert
+}
+
+func parseSanitizer(tag string) (*str.Sanitizer, error) {
+	if tag == "" || tag == "-" {
+		return nil, nil
+	}
+
+	sanitizersMu.RLock()
+	defer sanitizersMu.RUnlock()
+
+	var steps []str.Step
+
+	for _, name := range strings.Split(tag, ",") {
+		s, ok := sanitizers[strings.TrimSpace(name)]
+		if !ok {
+			return nil, fmt.Errorf("unknown sanitizer %q", name)
+		}
+
+		steps = append(steps, s...)
+	}
+
+	return str.NewSanitizer(steps...), nil
+}
@@ -0,0 +1,182 @@
+package validate
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Melenium2/go-template/internal/common/helper/str"
+)
+
+type sanitizedItem struct {
+	Name  string `json:"name" sanitize:"trim,html"`
+	Count int    `json:"count"`
+}
+
+type sanitizedParameters struct {
+	Name    string            `json:"name" sanitize:"trim,bidi,space"`
+	Comment *string           `json:"comment" sanitize:"html,lines"`
+	Tags    []string          `json:"tags" sanitize:"trim"`
+	Items   []sanitizedItem   `json:"items"`
+	Main    *sanitizedItem    `json:"main"`
+	Raw     string            `json:"raw"`
+	Meta    map[string]string `json:"meta" sanitize:"trim"`
+}
+
+type node struct {
+	Name     string `sanitize:"trim"`
+	Children []node
+}
+
+// author and book refer to each other.
+type author struct {
+	Name  string `sanitize:"trim"`
+	Books []book
+}
+
+type book struct {
+	Title  string
+	Author *author
+}
+
+type plain struct {
+	Name  string
+	Items []string
+}
+
+func TestSanitize(t *testing.T) {
+	comment := "<b>hi</b>\n\n\nthere"
+	tags := []string{" a ", "b "}
+	items := []sanitizedItem{{Name: " <i>milk</i> ", Count: 1}}
+	main := &sanitizedItem{Name: " bread "}
+
+	p := sanitizedParameters{
+		Name:    "  Bob\u202e \t Smith ",
+		Comment: &comment,
+		Tags:    tags,
+		Items:   items,
+		Main:    main,
+		Raw:     " raw ",
+		Meta:    map[string]string{"k": " v "},
+	}
+
+	res, ok := Sanitize(p).(sanitizedParameters)
+	assert.True(t, ok)
+
+	assert.Equal(t, "Bob Smith", res.Name)
+	assert.Equal(t, "hi\nthere", *res.Comment)
+	assert.Equal(t, []string{"a", "b"}, res.Tags)
+	assert.Equal(t, []sanitizedItem{{Name: "milk", Count: 1}}, res.Items)
+	assert.Equal(t, "bread", res.Main.Name)
+	assert.Equal(t, " raw ", res.Raw)
+	assert.Equal(t, " v ", res.Meta["k"])
+
+	// the original parameters are not changed.
+	assert.Equal(t, "<b>hi</b>\n\n\nthere", comment)
+	assert.Equal(t, []string{" a ", "b "}, tags)
+	assert.Equal(t, " <i>milk</i> ", items[0].Name)
+	assert.Equal(t, " bread ", main.Name)
+}
+
+func TestSanitize_Should_sanitize_pointer_to_parameters(t *testing.T) {
+	p := &sanitizedParameters{Name: " Bob "}
+
+	res, ok := Sanitize(p).(*sanitizedParameters)
+	assert.True(t, ok)
+	assert.Equal(t, "Bob", res.Name)
+	assert.Equal(t, " Bob ", p.Name)
+}
+
+func TestSanitize_Should_sanitize_recursive_types(t *testing.T) {
+	n := node{Name: " a ", Children: []node{{Name: " b ", Children: []node{{Name: " c "}}}}}
+
+	res, ok := Sanitize(n).(node)
+	assert.True(t, ok)
+	assert.Equal(t, node{Name: "a", Children: []node{{Name: "b", Children: []node{{Name: "c"}}}}}, res)
+}
+
+func TestSanitize_Should_sanitize_mutually_recursive_types(t *testing.T) {
+	a := author{Name: " a ", Books: []book{{Title: " t ", Author: &author{Name: " b "}}}}
+
+	res, ok := Sanitize(a).(author)
+	assert.True(t, ok)
+	assert.Equal(t, "a", res.Name)
+	assert.Equal(t, " t ", res.Books[0].Title)
+	assert.Equal(t, "b", res.Books[0].Author.Name)
+
+	// the plan of the book is not cached incomplete while the author is planned.
+	b, ok := Sanitize(book{Author: &author{Name: " c "}}).(book)
+	assert.True(t, ok)
+	assert.Equal(t, "c", b.Author.Name)
+}
+
+func TestSanitize_Should_sanitize_on_concurrent_first_use(t *testing.T) {
+	type item struct {
+		Name string `sanitize:"trim"`
+	}
+
+	type parameters struct {
+		Name  string `sanitize:"trim"`
+		Items []item
+	}
+
+	var (
+		start = make(chan struct{})
+		wg    sync.WaitGroup
+	)
+
+	for range 16 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			<-start
+
+			res, ok := Sanitize(parameters{Name: " a ", Items: []item{{Name: " b "}}}).(parameters)
+			assert.True(t, ok)
+			assert.Equal(t, "a", res.Name)
+			assert.Equal(t, "b", res.Items[0].Name)
+		}()
+	}
+
+	close(start)
+	wg.Wait()
+}
+
+func TestSanitize_Should_return_value_without_tags_as_is(t *testing.T) {
+	p := plain{Name: " a ", Items: []string{" b "}}
+
+	res, ok := Sanitize(p).(plain)
+	assert.True(t, ok)
+	assert.Equal(t, p, res)
+	assert.Same(t, &p.Items[0], &res.Items[0])
+
+	assert.Nil(t, Sanitize(nil))
+	assert.Equal(t, 10, Sanitize(10))
+	assert.Nil(t, Sanitize((*sanitizedParameters)(nil)))
+}
+
+func TestRegisterSanitizer(t *testing.T) {
+	RegisterSanitizer("test_profanity", str.MaskProfanity([]string{"bad"}, '*'), str.Func(strings.ToUpper))
+
+	type parameters struct {
+		Text string `sanitize:"test_profanity"`
+	}
+
+	res, ok := Sanitize(parameters{Text: "bad word"}).(parameters)
+	assert.True(t, ok)
+	assert.Equal(t, "*** WORD", res.Text)
+}
+
+func TestSanitize_Should_panic_if_tag_is_invalid(t *testing.T) {
+	type invalid struct {
+		Name string `sanitize:"unknown"`
+	}
+
+	assert.Panics(t, func() {
+		_ = Sanitize(invalid{})
+	})
+}
@@ -0,0 +1,290 @@
+// Package validate validates ...Parameters structs of commands and queries
+// by struct tags and by Validate() method of the struct.
+//
+// Supported rules of the "validate" tag:
+//   - required - value must not be zero (empty string, nil slice/pointer etc.)
+//   - omitempty - skip other rules if value is zero
+//   - min=N, max=N - length of strings (in runes), slices and maps or value of numbers
+//   - len=N - exact length of strings (in runes), slices and maps or value of numbers
+//   - oneof=a b c - value must be one of the listed values
+//   - regex=pattern - string must match the pattern, commas must be escaped as "\,"
+//   - uuid - string must be a valid UUID
+//   - email - string must be a valid email address
+//   - dive - following rules are applied to each element of the slice
+//
+// Nested structs, pointers to structs and slices of structs are validated
+// recursively. Rules that can not be described by tags should be placed
+// into Validate() method of the struct.
+//
+// Strings are cleaned before the validation by the "sanitize" tag, see
+// Sanitize. Built-in sanitizers:
+//   - trim - removes leading and trailing whitespace
+//   - nfc, nfkc - Unicode normalization, see str.NFC and str.NFKC
+//   - bidi - removes bidi controls (Trojan Source)
+//   - invisible - removes invisible characters, emojis are kept
+//   - html - removes HTML tags and decodes entities
+//   - escape - escapes HTML
+//   - confusables - folds Cyrillic and Greek letters that look like Latin ones
+//   - space - collapses whitespace and line breaks into single spaces
+//   - lines - collapses whitespace, keeps single line breaks
+//   - log - escapes line breaks and controls against log injection
+//
+// Example:
+//
+//	type CreateOrderParameters struct {
+//		ClientID string    `json:"client_id" validate:"required,uuid"`
+//		Comment  string    `json:"comment" sanitize:"nfc,bidi,invisible,html,lines" validate:"omitempty,max=255"`
+//		Status   string    `json:"status" validate:"oneof=new paid"`
+//		Tags     []string  `json:"tags" validate:"max=10,dive,min=1,max=32"`
+//		Items    []Item    `json:"items" validate:"required"`
+//		From     time.Time `json:"from"`
+//		To       time.Time `json:"to"`
+//	}
+//
+//	func (p CreateOrderParameters) Validate() error {
+//		if p.To.Before(p.From) {
+//			return validate.Errors{{Field: "to", Rule: "custom", Message: "must be after from"}}
+//		}
+//
+//		return nil
+//	}
+//
+//	params = validate.Sanitize(params).(CreateOrderParameters)
+//	err := validate.Params(params)
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/Melenium2/go-template/internal/common/erx"
+)
+
+// Violation describes the broken rule of the single field. Field is a path
+// to the field, for example "items[1].name".
+type Violation struct {
+	Field   string
+	Rule    string
+	Message string
+}
+
+// Errors is a list of violations. Errors always wraps erx.ErrInvalidArgument.
+type Errors []Violation
+
+func (e Errors) Error() string {
+	msgs := make([]string, 0, len(e))
+
+	for _, v := range e {
+		if v.Field == "" {
+			msgs = append(msgs, v.Message)
+
+			continue
+		}
+
+		msgs = append(msgs, fmt.Sprintf("%s: %s", v.Field, v.Message))
+	}
+
+	return fmt.Sprintf("%s: %s", erx.ErrInvalidArgument, strings.Join(msgs, "; "))
+}
+
+func (e Errors) Unwrap() error {
+	return erx.ErrInvalidArgument
+}
+
+// Fields groups messages of the violations by field. Can be used by the
+// UI layers for rendering per-field errors.
+func (e Errors) Fields() map[string][]string {
+	res := make(map[string][]string, len(e))
+
+	for _, v := range e {
+		res[v.Field] = append(res[v.Field], v.Message)
+	}
+
+	return res
+}
+
+// Violations extracts the list of violations from the error.
+func Violations(err error) (Errors, bool) {
+	var errs Errors
+
+	if errors.As(err, &errs) {
+		return errs, true
+	}
+
+	return nil, false
+}
+
+// Validator can be implemented by the struct for custom validation logic.
+type Validator interface {
+	Validate() error
+}
+
+// Params validates v by the "validate" struct tags and calls Validate() of
+// v if it implements Validator.
+func Params(v any) error {
+	var errs Errors
+
+	rv := reflect.ValueOf(v)
+
+	validateStruct(rv, "", &errs, false)
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// Struct validates v by the "validate" struct tags only. Nested structs are
+// validated recursively with their Validate() methods, but Validate() of v
+// itself is not called, so Struct is safe to call from it.
+func Struct(v any) error {
+	var errs Errors
+
+	rv := reflect.ValueOf(v)
+
+	validateStruct(rv, "", &errs, true)
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+func validateStruct(rv reflect.Value, path string, errs *Errors, root bool) {
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return
+		}
+
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() != reflect.Struct {
+		return
+	}
+
+	for _, f := range fieldsOf(rv.Type()) {
+		validateField(rv.Field(f.index), join(path, f.name), f.rules, errs)
+	}
+
+	if !root {
+		callValidator(rv, path, errs)
+	}
+}
+
+func callValidator(rv reflect.Value, path string, errs *Errors) {
+	var validator Validator
+
+	switch {
+	case rv.CanInterface() && rv.Type().Implements(validatorType):
+		validator, _ = rv.Interface().(Validator)
+	case rv.CanAddr() && rv.Addr().CanInterface() && rv.Addr().Type().Implements(validatorType):
+		validator, _ = rv.Addr().Interface().(Validator)
+	default:
+		return
+	}
+
+	err := validator.Validate()
+	if err == nil {
+		return
+	}
+
+	if nested, ok := Violations(err); ok {
+		for _, v := range nested {
+			v.Field = join(path, v.Field)
+			*errs = append(*errs, v)
+		}
+
+		return
+	}
+
+	*errs = append(*errs, Violation{Field: path, Rule: "custom", Message: err.Error()})
+}
+
+func validateField(rv reflect.Value, path string, rules []rule, errs *Errors) {
+	for i, r := range rules {
+		switch r.name {
+		case "omitempty":
+			if rv.IsZero() {
+				return
+			}
+
+			continue
+		case "dive":
+			validateElements(rv, path, rules[i+1:], errs)
+
+			return
+		}
+
+		if msg, ok := r.check(rv); !ok {
+			*errs = append(*errs, Violation{Field: path, Rule: r.name, Message: msg})
+
+			// there is no reason to check other rules of the missing value.
+			if r.name == "required" {
+				return
+			}
+		}
+	}
+
+	validateNested(rv, path, errs)
+}
+
+func validateElements(rv reflect.Value, path string, rules []rule, errs *Errors) {
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return
+	}
+
+	for i := 0; i < rv.Len(); i++ {
+		validateField(rv.Index(i), fmt.Sprintf("%s[%d]", path, i), rules, errs)
+	}
+}
+
+func validateNested(rv reflect.Value, path string, errs *Errors) {
+	t := rv.Type()
+
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() { //nolint:exhaustive
+	case reflect.Struct:
+		validateStruct(rv, path, errs, false)
+	case reflect.Slice, reflect.Array:
+		elem := t.Elem()
+
+		for elem.Kind() == reflect.Pointer {
+			elem = elem.Elem()
+		}
+
+		if elem.Kind() != reflect.Struct {
+			return
+		}
+
+		for rv.Kind() == reflect.Pointer {
+			if rv.IsNil() {
+				return
+			}
+
+			rv = rv.Elem()
+		}
+
+		for i := 0; i < rv.Len(); i++ {
+			validateStruct(rv.Index(i), fmt.Sprintf("%s[%d]", path, i), errs, false)
+		}
+	}
+}
+
+func join(path, field string) string {
+	switch {
+	case path == "":
+		return field
+	case field == "":
+		return path
+	default:
+		return path + "." + field
+	}
+}
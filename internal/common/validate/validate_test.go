@@ -0,0 +1,202 @@
+package validate
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Melenium2/go-template/internal/common/erx"
+)
+
+type item struct {
+	Name  string `json:"name" validate:"required,max=5"`
+	Count int    `json:"count" validate:"min=1,max=10"`
+}
+
+type period struct {
+	From int `json:"from"`
+	To   int `json:"to"`
+}
+
+func (p period) Validate() error {
+	if p.To < p.From {
+		return errors.New("to must be greater than from")
+	}
+
+	return nil
+}
+
+type parameters struct {
+	ID      string   `json:"id" validate:"required,uuid"`
+	Email   string   `json:"email" validate:"omitempty,email"`
+	Status  string   `json:"status" validate:"oneof=new paid"`
+	Code    string   `json:"code" validate:"omitempty,regex=^[a-z]{2\\,3}$"`
+	Phone   string   `validate:"omitempty,len=11"`
+	Tags    []string `json:"tags" validate:"max=2,dive,required,max=3"`
+	Items   []item   `json:"items" validate:"required"`
+	Main    *item    `json:"main"`
+	Period  period   `json:"period"`
+	Comment *string  `json:"comment" validate:"omitempty,min=2"`
+}
+
+func (p parameters) Validate() error {
+	if p.Status == "paid" && p.Email == "" {
+		return Errors{{Field: "email", Rule: "custom", Message: "is required for paid orders"}}
+	}
+
+	return nil
+}
+
+func validParameters() parameters {
+	return parameters{
+		ID:     "0b9f7b5c-2a5e-4e8a-9a3e-1c8c4e6c2f11",
+		Status: "new",
+		Items:  []item{{Name: "milk", Count: 1}},
+	}
+}
+
+func TestParams(t *testing.T) {
+	short := "a"
+
+	tests := []struct {
+		name     string
+		modify   func(p *parameters)
+		expected map[string][]string
+	}{
+		{
+			name:     "should return nil if parameters are valid",
+			modify:   func(_ *parameters) {},
+			expected: nil,
+		},
+		{
+			name: "should return required violation only once",
+			modify: func(p *parameters) {
+				p.ID = ""
+			},
+			expected: map[string][]string{"id": {"is required"}},
+		},
+		{
+			name: "should validate uuid, email, enum and regex",
+			modify: func(p *parameters) {
+				p.ID = "not-uuid"
+				p.Email = "Bob <bob@example.com>"
+				p.Status = "canceled"
+				p.Code = "abcd"
+			},
+			expected: map[string][]string{
+				"id":     {"must be a valid UUID"},
+				"email":  {"must be a valid email address"},
+				"status": {"must be one of [new, paid]"},
+				"code":   {"must match ^[a-z]{2,3}$"},
+			},
+		},
+		{
+			name: "should use go field name if json tag is absent",
+			modify: func(p *parameters) {
+				p.Phone = "123"
+			},
+			expected: map[string][]string{"Phone": {"must contain exactly 11 characters"}},
+		},
+		{
+			name: "should validate slice length and each element",
+			modify: func(p *parameters) {
+				p.Tags = []string{"a", "", "long"}
+			},
+			expected: map[string][]string{
+				"tags":    {"must contain at most 2 elements"},
+				"tags[1]": {"is required"},
+				"tags[2]": {"must contain at most 3 characters"},
+			},
+		},
+		{
+			name: "should validate nested structs, pointers and slices of structs",
+			modify: func(p *parameters) {
+				p.Items = []item{{Name: "milk", Count: 1}, {Name: "", Count: 11}}
+				p.Main = &item{Name: "bread!", Count: 0}
+			},
+			expected: map[string][]string{
+				"items[1].name":  {"is required"},
+				"items[1].count": {"must be less than or equal to 10"},
+				"main.name":      {"must contain at most 5 characters"},
+				"main.count":     {"must be greater than or equal to 1"},
+			},
+		},
+		{
+			name: "should call Validate of nested structs and root struct",
+			modify: func(p *parameters) {
+				p.Status = "paid"
+				p.Period = period{From: 2, To: 1}
+			},
+			expected: map[string][]string{
+				"period": {"to must be greater than from"},
+				"email":  {"is required for paid orders"},
+			},
+		},
+		{
+			name: "should validate value under the pointer",
+			modify: func(p *parameters) {
+				p.Comment = &short
+			},
+			expected: map[string][]string{"comment": {"must contain at least 2 characters"}},
+		},
+	}
+
+	t.Parallel()
+
+	for _, tc := range tests {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			p := validParameters()
+			tc.modify(&p)
+
+			err := Params(p)
+			if tc.expected == nil {
+				assert.NoError(t, err)
+
+				return
+			}
+
+			assert.ErrorIs(t, err, erx.ErrInvalidArgument)
+
+			violations, ok := Violations(err)
+			assert.True(t, ok)
+			assert.Equal(t, tc.expected, violations.Fields())
+		})
+	}
+}
+
+func TestStruct_Should_not_call_Validate_of_root_struct(t *testing.T) {
+	p := validParameters()
+	p.Status = "paid"
+
+	assert.NoError(t, Struct(p))
+	assert.Error(t, Params(p))
+}
+
+func TestParams_Should_skip_non_struct_values(t *testing.T) {
+	assert.NoError(t, Params(10))
+	assert.NoError(t, Params(nil))
+	assert.NoError(t, Params((*parameters)(nil)))
+}
+
+func TestParams_Should_word_len_of_number_as_value(t *testing.T) {
+	type parameters struct {
+		Digits int `json:"digits" validate:"len=5"`
+	}
+
+	violations, ok := Violations(Params(parameters{Digits: 4}))
+	assert.True(t, ok)
+	assert.Equal(t, map[string][]string{"digits": {"must be equal to 5"}}, violations.Fields())
+}
+
+func TestParams_Should_panic_if_tag_is_invalid(t *testing.T) {
+	type invalid struct {
+		Name string `validate:"unknown"`
+	}
+
+	assert.Panics(t, func() {
+		_ = Params(invalid{})
+	})
+}
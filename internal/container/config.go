@@ -19,10 +19,23 @@ var (
 type Config struct {
 	Environment Env    `env:"ENVIRONMENT" envDefault:"dev"`
 	Branch      string `env:"BRANCH"`
-	HTTPPort    string `env:"HTTP_PORT" envDefault:"4000"`
+	// scaffold:http:begin
+	HTTPPort string `env:"HTTP_PORT" envDefault:"4000"`
+	// scaffold:http:end
 
-	DB   DB
+	// PaginationSecret signs the cursors of the pages. Must be the same for
+	// all replicas, otherwise cursors are rejected by other replicas. The
+	// service does not start without it, cursors signed by the empty key can
+	// be forged by clients. It is checked by NewContainer, so the migrate
+	// subcommand works without it.
+	PaginationSecret string `env:"PAGINATION_SECRET"`
+
+	Cache Cache
+
+	DB DB
+	// scaffold:amqp:begin
 	Amqp Amqp
+	// scaffold:amqp:end
 }
 
 type DB struct {
@@ -34,8 +47,30 @@ type DB struct {
 	Password             string        `env:"PGPASSWORD" envDefault:"postgres"`
 	MaxOpenedConnections int           `env:"DATABASE_MAX_OPENED_CONNECTIONS" envDefault:"10"`
 	MaxIdleTimeout       time.Duration `env:"DATABASE_MAX_IDLE_TIMEOUT" envDefault:"5m"`
+	// AutoMigrate applies pending migrations on startup of the application.
+	AutoMigrate bool `env:"DATABASE_AUTO_MIGRATE" envDefault:"true"`
+	// MigrationLockTimeout is the maximum time for waiting while other replica
+	// applies migrations.
+	MigrationLockTimeout time.Duration `env:"DATABASE_MIGRATION_LOCK_TIMEOUT" envDefault:"1m"`
+	// MigrationsPath is used only for creating new migration files. The
+	// application applies migrations embedded into the binary.
+	MigrationsPath string `env:"DATABASE_MIGRATIONS_PATH" envDefault:"db/migrations"`
+	// TenantMode switches each transaction to the tenant of the request:
+	// "schema" sets search_path to the schema of the tenant, "setting" sets
+	// app.tenant_id for row-level security policies. Empty value disables
+	// multi-tenancy.
+	TenantMode string `env:"DATABASE_TENANT_MODE"`
 }
 
+type Cache struct {
+	// RedisAddr switches the second level of the caches from the Postgres
+	// table to Redis.
+	RedisAddr     string `env:"CACHE_REDIS_ADDR"`
+	RedisPassword string `env:"CACHE_REDIS_PASSWORD"`
+	RedisDB       int    `env:"CACHE_REDIS_DB"`
+}
+
+// scaffold:amqp:begin
 type Amqp struct {
 	AmqpHost     string `env:"AMQP_HOST" envDefault:"localhost"`
 	AmqpVhost    string `env:"AMQP_VHOST" envDefault:"/"`
@@ -44,6 +79,8 @@ type Amqp struct {
 	AmqpPassword string `env:"AMQP_PASSWORD" envDefault:"guest"`
 }
 
+// scaffold:amqp:end
+
 func NewConfig() Config {
 	// init envs from .env.example file
 	_ = godotenv.Load()
@@ -2,31 +2,73 @@
 package container
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log"
 	"net/http"
-
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/Melenium2/go-template/internal/api/bus"
+	"github.com/Melenium2/go-template/internal/common/jobs"
+	"github.com/Melenium2/go-template/internal/common/lock"
+	"github.com/Melenium2/go-template/internal/common/pagination"
+	"github.com/Melenium2/go-template/internal/common/scheduler"
+	"github.com/Melenium2/go-template/internal/common/tenant"
+	"github.com/Melenium2/go-template/internal/common/tiered"
 	"github.com/Melenium2/go-template/pkg/logger"
+	"github.com/Melenium2/go-template/pkg/psql"
 )
 
 type Container struct {
 	Config Config
 
+	// Cursors encodes cursors of the pages, pass it to the repositories with
+	// paginated lists.
+	Cursors *pagination.Codec
+	// Listener receives LISTEN/NOTIFY notifications of other replicas, the
+	// connection is taken only if there are subscriptions.
+	Listener *psql.Listener
+	// Locks takes distributed locks and runs the leader election.
+	Locks *lock.Locker
+	// Jobs is the queue of background jobs, handlers are registered in
+	// makeJobs.
+	Jobs *jobs.Queue
+	// Scheduler runs periodic tasks, tasks are added in makeScheduler.
+	Scheduler *scheduler.Scheduler
+	// CacheBackend is the second level of the caches shared by replicas and
+	// CacheInvalidator removes deleted keys from the caches of other
+	// replicas, pass them to tiered.New.
+	CacheBackend     tiered.Backend
+	CacheInvalidator tiered.Invalidator
+	// CacheTags invalidates values of storage.Cached by tags.
+	CacheTags *tiered.Tags
+
 	Apps        *Apps
 	Clients     *Clients
 	Storages    *Storages
 	Services    *Services
 	AppServices *ApplicationServices
-	Databus     *Broker
+	// scaffold:amqp:begin
+	Databus *Broker
+	// scaffold:amqp:end
+	Dispatcher *bus.Dispatcher
 }
 
 type Apps struct {
 	// Application layer.
 }
 
+// scaffold:amqp:begin
 type Broker struct {
 	// Broker.
 }
 
+// scaffold:amqp:end
+
 type Clients struct {
 	// Other client.
 }
@@ -46,25 +88,142 @@ type ApplicationServices struct {
 func NewContainer() *Container {
 	cfg := NewConfig()
 
-	conn := setupDatabase(cfg.DB)
-	setupMigrations(conn)
+	if cfg.PaginationSecret == "" {
+		log.Fatal("PAGINATION_SECRET is required to sign cursors of the pages")
+	}
+
+	conn, pool := setupDatabase(cfg.DB)
+
+	if cfg.DB.AutoMigrate {
+		setupMigrations(conn, cfg.DB)
+	}
 
 	logger.SetupLogger()
 
-	container := &Container{Config: cfg}
+	container := &Container{
+		Config:   cfg,
+		Cursors:  pagination.NewCodec([]byte(cfg.PaginationSecret)),
+		Listener: psql.NewListener(pool),
+		Locks:    lock.New(conn),
+	}
+
+	container.CacheBackend = makeCacheBackend(container, conn)
+	container.CacheInvalidator = tiered.NewNotifier(container.Listener, conn)
+	container.CacheTags = tiered.NewTags(container.CacheBackend, container.CacheInvalidator)
 
+	// scaffold:amqp:begin
 	container.Databus = makeDatabus(cfg.Amqp, cfg.Environment, cfg.Branch)
+	// scaffold:amqp:end
 	container.Clients = makeClients(container, cfg)
 	container.Storages = makeStorages(container)
 	container.Services = makeServices(container)
 	container.Apps = makeApps(container)
 	container.AppServices = makeAppServices(container, cfg)
+	// handlers of the jobs and tasks use the applications, so they are made last.
+	container.Jobs = makeJobs(container, conn)
+	container.Scheduler = makeScheduler(container)
+	container.Dispatcher = makeDispatcher(container)
 
 	return container
 }
 
+// Worker is a long-running component of the application, for example
+// http server. Worker must return after the context is canceled.
+type Worker func(ctx context.Context) error
+
+// Run starts all the workers of the application and blocks until
+// SIGINT/SIGTERM is received or one of the workers fails.
 func (c *Container) Run() error {
-	p := fmt.Sprintf(":%s", c.Config.HTTPPort)
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// the handlers are registered by now, so the queue is woken by the
+	// notifications of all of them.
+	defer c.Jobs.Subscribe(c.Listener)()
+
+	workers := []Worker{c.Listener.Listen, c.Jobs.Run, c.Scheduler.Run}
+
+	// scaffold:http:begin
+	workers = append(workers, c.runHTTP)
+	// scaffold:http:end
+
+	return runWorkers(ctx, workers...)
+}
+
+// runWorkers runs each worker in separate goroutine. If one of the workers
+// fails, the others are stopped. The first error is returned.
+func runWorkers(ctx context.Context, workers ...Worker) error {
+	if len(workers) == 0 {
+		<-ctx.Done()
+
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
 
-	return http.ListenAndServe(p, nil) //nolint:gosec
+	wg.Add(len(workers))
+
+	for _, w := range workers {
+		go func() {
+			defer wg.Done()
+
+			if err := w(ctx); err != nil {
+				once.Do(func() {
+					firstErr = err
+
+					cancel()
+				})
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return firstErr
+}
+
+// scaffold:http:begin
+func (c *Container) runHTTP(ctx context.Context) error {
+	srv := &http.Server{
+		Addr:              fmt.Sprintf(":%s", c.Config.HTTPPort),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	if c.Config.DB.TenantMode != "" {
+		srv.Handler = tenant.Middleware()(http.DefaultServeMux)
+	}
+
+	errs := make(chan error, 1)
+
+	go func() {
+		errs <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+
+	if err := <-errs; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
 }
+
+// scaffold:http:end
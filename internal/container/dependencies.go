@@ -4,15 +4,23 @@ import (
 	"context"
 	"log"
 	"strconv"
+	"time"
 
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/jmoiron/sqlx"
+	"github.com/redis/go-redis/v9"
 
+	"github.com/Melenium2/go-template/db"
+	"github.com/Melenium2/go-template/internal/api/bus"
+	"github.com/Melenium2/go-template/internal/common/jobs"
+	"github.com/Melenium2/go-template/internal/common/scheduler"
+	"github.com/Melenium2/go-template/internal/common/tiered"
 	"github.com/Melenium2/go-template/internal/common/tx"
 	"github.com/Melenium2/go-template/pkg/migration"
 	"github.com/Melenium2/go-template/pkg/psql"
 )
 
-func setupDatabase(cfg DB) *sqlx.DB {
+func setupDatabase(cfg DB) (*sqlx.DB, *pgxpool.Pool) {
 	port, _ := strconv.Atoi(cfg.Port)
 
 	c := psql.Config{
@@ -29,7 +37,7 @@ func setupDatabase(cfg DB) *sqlx.DB {
 		},
 	}
 
-	conn, err := psql.Connect(c)
+	conn, pool, err := psql.ConnectPool(c)
 	if err != nil {
 		log.Fatalf("error connecting database, %s", err.Error())
 	}
@@ -38,28 +46,68 @@ func setupDatabase(cfg DB) *sqlx.DB {
 		log.Fatalf("could not connect to database, %s", err.Error())
 	}
 
-	tx.SetupManager(conn)
+	setupManager(conn, cfg)
 
-	return conn
+	return conn, pool
 }
 
-func setupMigrations(conn *sqlx.DB) {
-	m := migration.New()
+func setupManager(conn *sqlx.DB, cfg DB) {
+	switch cfg.TenantMode {
+	case "":
+		tx.SetupManager(conn)
+	case "schema":
+		tx.SetupTenantManager(conn, tx.TenantConfig{
+			Mode:          tx.TenantSearchPath,
+			SharedSchemas: []string{cfg.Schema},
+		}, tx.TenantGuard)
+	case "setting":
+		tx.SetupTenantManager(conn, tx.TenantConfig{Mode: tx.TenantSetting}, tx.TenantGuard)
+	default:
+		log.Fatalf("unknown tenant mode %q", cfg.TenantMode)
+	}
+}
 
-	err := m.Setup(context.TODO(), conn.DB, "db/migrations")
+func setupMigrations(conn *sqlx.DB, cfg DB) {
+	m, err := newMigrationClient(conn)
 	if err != nil {
 		log.Fatalf("can not setup migrations, err: %s", err)
 	}
 
-	if err = m.Up(); err != nil {
+	if err = m.UpLocked(context.TODO(), cfg.MigrationLockTimeout); err != nil {
 		log.Fatalf("error making migrations, %s", err)
 	}
 }
 
+func newMigrationClient(conn *sqlx.DB) (*migration.Client, error) {
+	m := migration.New()
+
+	if err := m.Register(db.GoMigrations()...); err != nil {
+		return nil, err
+	}
+
+	err := m.SetupFS(context.TODO(), conn.DB, db.Migrations())
+	if err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// NewMigrationClient connects to the database and setups migration client
+// without starting the application. Used by the migrate subcommands.
+func NewMigrationClient(cfg Config) (*migration.Client, error) {
+	conn, _ := setupDatabase(cfg.DB)
+
+	return newMigrationClient(conn)
+}
+
+// scaffold:amqp:begin
 func makeDatabus(_ Amqp, _ Env, _ string) *Broker {
 	return &Broker{}
 }
 
+// scaffold:amqp:end
+
 func makeApps(_ *Container) *Apps {
 	return &Apps{}
 }
@@ -79,3 +127,61 @@ func makeServices(_ *Container) *Services {
 func makeAppServices(_ *Container, _ Config) *ApplicationServices {
 	return &ApplicationServices{}
 }
+
+// makeJobs creates the queue of background jobs. It is called after the
+// applications are made, register handlers of the jobs here, for example:
+//
+//	jobs.Handle(q, command.SendEmailJob, c.Apps.Mailer.Send)
+func makeJobs(c *Container, conn *sqlx.DB) *jobs.Queue {
+	q := jobs.New(conn, jobs.Config{
+		// the table is qualified, so it is found from the tenant transactions.
+		Table: c.Config.DB.Schema + ".jobs",
+	})
+
+	return q
+}
+
+// makeScheduler creates the scheduler of periodic tasks. Add tasks here, for
+// example:
+//
+//	s.Add("cleanup-sessions", scheduler.MustCron("*/10 * * * *"), c.Apps.Sessions.Cleanup,
+//		scheduler.SingleReplica(),
+//	)
+func makeScheduler(c *Container) *scheduler.Scheduler {
+	s := scheduler.New(scheduler.WithLocker(c.Locks))
+
+	if pg, ok := c.CacheBackend.(*tiered.Postgres); ok {
+		s.Add("cache-purge", scheduler.Every(10*time.Minute), pg.Purge, scheduler.SingleReplica())
+	}
+
+	return s
+}
+
+// makeCacheBackend returns Redis if it is configured, otherwise the unlogged
+// Postgres table.
+func makeCacheBackend(c *Container, conn *sqlx.DB) tiered.Backend {
+	if c.Config.Cache.RedisAddr != "" {
+		return tiered.NewRedis(redis.NewClient(&redis.Options{
+			Addr:     c.Config.Cache.RedisAddr,
+			Password: c.Config.Cache.RedisPassword,
+			DB:       c.Config.Cache.RedisDB,
+		}))
+	}
+
+	return tiered.NewPostgres(conn, c.Config.DB.Schema+".cache_entries")
+}
+
+func makeDispatcher(c *Container) *bus.Dispatcher {
+	d := bus.NewDispatcher(
+		bus.Logging(),
+		bus.Validation(),
+		bus.Transaction(),
+	)
+
+	// tenant queries are allowed only inside the transaction.
+	if c.Config.DB.TenantMode != "" {
+		d.Use(bus.ReadOnly())
+	}
+
+	return d
+}
@@ -0,0 +1,224 @@
+// Package builder builds Postgres queries with $n placeholders for the
+// dynamic filters of the repositories. The builder is safe by default:
+// identifiers are quoted and values are always passed as arguments. Raw
+// SQL fragments are allowed only through Raw, never pass user input there.
+//
+// Example:
+//
+//	query, args, err := builder.Select("id", "status").
+//		From("orders").
+//		Where(builder.Eq("client_id", clientID)).
+//		WhereIf(len(statuses) > 0, builder.In("status", statuses)).
+//		OrderBy("created_at", builder.Desc).
+//		Limit(20).
+//		Build()
+//
+//	SELECT "id", "status" FROM "orders" WHERE "client_id" = $1 AND "status" IN ($2, $3)
+//	ORDER BY "created_at" DESC LIMIT $4
+package builder
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrNoTable  = errors.New("table is not set")
+	ErrNoValues = errors.New("values are not set")
+	// ErrUnconditional is returned for UPDATE and DELETE without WHERE, use
+	// All() if the whole table must be changed.
+	ErrUnconditional = errors.New("query without where condition")
+)
+
+// Builder is the query that can be built to SQL and arguments.
+type Builder interface {
+	Build() (string, []any, error)
+	write(buf *buffer) error
+}
+
+// buffer accumulates SQL and arguments, placeholders are numbered by the
+// position of the argument, so subqueries share the numbering.
+type buffer struct {
+	sql  strings.Builder
+	args []any
+}
+
+func (b *buffer) WriteString(s string) {
+	b.sql.WriteString(s)
+}
+
+// arg adds the argument and writes its placeholder.
+func (b *buffer) arg(v any) {
+	b.args = append(b.args, v)
+	b.sql.WriteString("$" + strconv.Itoa(len(b.args)))
+}
+
+// idents writes quoted identifiers separated by comma.
+func (b *buffer) idents(names []string) {
+	for i, name := range names {
+		if i > 0 {
+			b.sql.WriteString(", ")
+		}
+
+		b.sql.WriteString(Ident(name))
+	}
+}
+
+func build(q Builder) (string, []any, error) {
+	var buf buffer
+
+	if err := q.write(&buf); err != nil {
+		return "", nil, err
+	}
+
+	return buf.sql.String(), buf.args, nil
+}
+
+// Ident quotes the identifier. Schema qualified names and aliases are
+// supported, for example "public.orders o" or "orders AS o". Star is kept
+// as is: "*", "o.*".
+func Ident(name string) string {
+	fields := strings.Fields(name)
+
+	switch {
+	case len(fields) == 2:
+		return quote(fields[0]) + " AS " + quote(fields[1])
+	case len(fields) == 3 && strings.EqualFold(fields[1], "as"):
+		return quote(fields[0]) + " AS " + quote(fields[2])
+	default:
+		return quote(name)
+	}
+}
+
+func quote(name string) string {
+	parts := strings.Split(name, ".")
+
+	for i, p := range parts {
+		if p == "*" && i == len(parts)-1 {
+			continue
+		}
+
+		parts[i] = `"` + strings.ReplaceAll(p, `"`, `""`) + `"`
+	}
+
+	return strings.Join(parts, ".")
+}
+
+// Expr is the raw SQL fragment. Question marks are replaced by the
+// placeholders of the arguments, use "??" for the literal question mark.
+type Expr struct {
+	sql  string
+	args []any
+}
+
+// Raw returns the raw SQL fragment with the arguments.
+//
+// Example:
+//
+//	builder.Raw("created_at > now() - ?::interval", "1 day")
+func Raw(sql string, args ...any) Expr {
+	return Expr{sql: sql, args: args}
+}
+
+func (e Expr) write(buf *buffer) error {
+	var (
+		n   int
+		sql = e.sql
+	)
+
+	for {
+		i := strings.IndexByte(sql, '?')
+		if i < 0 {
+			buf.WriteString(sql)
+
+			break
+		}
+
+		buf.WriteString(sql[:i])
+
+		if i+1 < len(sql) && sql[i+1] == '?' {
+			buf.WriteString("?")
+
+			sql = sql[i+2:]
+
+			continue
+		}
+
+		if n >= len(e.args) {
+			return errors.New("not enough arguments for expression " + e.sql)
+		}
+
+		if sub, ok := e.args[n].(Builder); ok {
+			buf.WriteString("(")
+
+			if err := sub.write(buf); err != nil {
+				return err
+			}
+
+			buf.WriteString(")")
+		} else {
+			buf.arg(e.args[n])
+		}
+
+		n++
+		sql = sql[i+1:]
+	}
+
+	if n != len(e.args) {
+		return errors.New("too many arguments for expression " + e.sql)
+	}
+
+	return nil
+}
+
+// cte is the common table expression.
+type cte struct {
+	name  string
+	query Builder
+}
+
+func writeWith(buf *buffer, ctes []cte) error {
+	if len(ctes) == 0 {
+		return nil
+	}
+
+	buf.WriteString("WITH ")
+
+	for i, c := range ctes {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+
+		buf.WriteString(quote(c.name) + " AS (")
+
+		if err := c.query.write(buf); err != nil {
+			return err
+		}
+
+		buf.WriteString(")")
+	}
+
+	buf.WriteString(" ")
+
+	return nil
+}
+
+func writeWhere(buf *buffer, conds []Cond) error {
+	if len(conds) == 0 {
+		return nil
+	}
+
+	buf.WriteString(" WHERE ")
+
+	return And(conds...).write(buf)
+}
+
+func writeReturning(buf *buffer, columns []string) {
+	if len(columns) == 0 {
+		return
+	}
+
+	buf.WriteString(" RETURNING ")
+	buf.idents(columns)
+}
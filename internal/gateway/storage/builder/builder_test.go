@@ -0,0 +1,149 @@
+package builder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuild(t *testing.T) {
+	var (
+		noStatuses []string
+		statuses   = []string{"new", "paid"}
+	)
+
+	tests := []struct {
+		name  string
+		query Builder
+		sql   string
+		args  []any
+	}{
+		{
+			name: "select with conditional where",
+			query: Select("id", "status").
+				From("orders").
+				Where(Eq("client_id", 7)).
+				WhereIf(len(statuses) > 0, In("status", statuses)).
+				WhereIf(false, IsNull("deleted_at")).
+				OrderBy("created_at", Desc).
+				OrderBy("id").
+				Limit(20).
+				Offset(40),
+			sql: `SELECT "id", "status" FROM "orders" WHERE "client_id" = $1 AND "status" IN ($2, $3) ` +
+				`ORDER BY "created_at" DESC, "id" LIMIT $4 OFFSET $5`,
+			args: []any{7, "new", "paid", 20, 40},
+		},
+		{
+			name:  "empty in matches nothing",
+			query: Select().From("orders").Where(In("status", noStatuses), NotIn("id", []int{})),
+			sql:   `SELECT * FROM "orders" WHERE FALSE AND TRUE`,
+		},
+		{
+			name: "nested conditions and join",
+			query: Select("o.id", "c.name AS client").
+				From("orders o").
+				LeftJoin("clients c", Eq("c.id", Column("o.client_id"))).
+				Where(
+					Or(Eq("o.status", "new"), And(Gt("o.amount", 10), Not(IsNotNull("o.paid_at")))),
+					Raw("o.created_at > now() - ?::interval", "1 day"),
+				),
+			sql: `SELECT "o"."id", "c"."name" AS "client" FROM "orders" AS "o" ` +
+				`LEFT JOIN "clients" AS "c" ON "c"."id" = "o"."client_id" ` +
+				`WHERE ("o"."status" = $1 OR ("o"."amount" > $2 AND NOT ("o"."paid_at" IS NOT NULL))) ` +
+				`AND (o.created_at > now() - $3::interval)`,
+			args: []any{"new", 10, "1 day"},
+		},
+		{
+			name: "cte and subquery share placeholders",
+			query: Select("id").
+				With("paid", Select("order_id").From("payments").Where(Gte("amount", 100))).
+				From("orders").
+				Where(Eq("status", "new"), In("id", Select("order_id").From("paid"))).
+				ColumnExpr(Raw("count(*) OVER () AS total")),
+			sql: `WITH "paid" AS (SELECT "order_id" FROM "payments" WHERE "amount" >= $1) ` +
+				`SELECT "id", count(*) OVER () AS total FROM "orders" ` +
+				`WHERE "status" = $2 AND "id" IN (SELECT "order_id" FROM "paid")`,
+			args: []any{100, "new"},
+		},
+		{
+			name: "insert on conflict do update",
+			query: Insert("orders").
+				Columns("id", "status").
+				Values(1, "new").
+				Values(2, Raw("DEFAULT")).
+				OnConflict("id").
+				DoUpdate("status").
+				Returning("id"),
+			sql: `INSERT INTO "orders" ("id", "status") VALUES ($1, $2), ($3, DEFAULT) ` +
+				`ON CONFLICT ("id") DO UPDATE SET "status" = EXCLUDED."status" RETURNING "id"`,
+			args: []any{1, "new", 2},
+		},
+		{
+			name:  "insert map on conflict do nothing",
+			query: Insert("public.orders").SetMap(map[string]any{"status": "new", "id": 1}).OnConflict(),
+			sql:   `INSERT INTO "public"."orders" ("id", "status") VALUES ($1, $2) ON CONFLICT DO NOTHING`,
+			args:  []any{1, "new"},
+		},
+		{
+			name: "update",
+			query: Update("orders").
+				Set("status", "paid").
+				SetIf(false, "comment", "skipped").
+				Set("updated_at", Raw("now()")).
+				Where(Eq("id", 1)).
+				Returning("id", "status"),
+			sql:  `UPDATE "orders" SET "status" = $1, "updated_at" = now() WHERE "id" = $2 RETURNING "id", "status"`,
+			args: []any{"paid", 1},
+		},
+		{
+			name:  "delete",
+			query: Delete("orders").Where(Lt("created_at", "2024-01-01")),
+			sql:   `DELETE FROM "orders" WHERE "created_at" < $1`,
+			args:  []any{"2024-01-01"},
+		},
+		{
+			name:  "count ignores order and limit",
+			query: Select("id").From("orders").Where(Eq("status", "new")).OrderBy("id").Limit(10).Count(),
+			sql:   `WITH "q" AS (SELECT "id" FROM "orders" WHERE "status" = $1) SELECT count(*) FROM "q"`,
+			args:  []any{"new"},
+		},
+		{
+			name:  "identifiers are quoted",
+			query: Select(`id"; DROP TABLE orders; --`).From("orders"),
+			sql:   `SELECT "id""; DROP TABLE orders; --" FROM "orders"`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			sql, args, err := tc.query.Build()
+			require.NoError(t, err)
+			assert.Equal(t, tc.sql, sql)
+			assert.Equal(t, tc.args, args)
+		})
+	}
+}
+
+func TestBuild_Should_return_error_for_unsafe_or_incomplete_queries(t *testing.T) {
+	_, _, err := Delete("orders").Build()
+	assert.ErrorIs(t, err, ErrUnconditional)
+
+	_, _, err = Update("orders").Set("status", "paid").Build()
+	assert.ErrorIs(t, err, ErrUnconditional)
+
+	_, _, err = Delete("orders").All().Build()
+	assert.NoError(t, err)
+
+	_, _, err = Select("id").Build()
+	assert.ErrorIs(t, err, ErrNoTable)
+
+	_, _, err = Insert("orders").Build()
+	assert.ErrorIs(t, err, ErrNoValues)
+
+	_, _, err = Insert("orders").Columns("id", "status").Values(1).Build()
+	assert.Error(t, err)
+
+	_, _, err = Select().From("orders").Where(Raw("id = ? AND status = ?", 1)).Build()
+	assert.Error(t, err)
+}
@@ -0,0 +1,223 @@
+package builder
+
+import (
+	"reflect"
+)
+
+// Cond is the condition of WHERE or JOIN clauses. Expr is the condition too.
+type Cond interface {
+	write(buf *buffer) error
+}
+
+type compare struct {
+	column string
+	op     string
+	value  any
+}
+
+func (c compare) write(buf *buffer) error {
+	buf.WriteString(Ident(c.column) + " " + c.op + " ")
+
+	return writeValue(buf, c.value)
+}
+
+// Column is the value that is written as the quoted identifier instead of
+// the argument, used to compare columns:
+//
+//	builder.Eq("c.id", builder.Column("o.client_id"))
+type Column string
+
+// Eq is "column = value".
+func Eq(column string, value any) Cond { return compare{column: column, op: "=", value: value} }
+
+// NotEq is "column <> value".
+func NotEq(column string, value any) Cond { return compare{column: column, op: "<>", value: value} }
+
+// Gt is "column > value".
+func Gt(column string, value any) Cond { return compare{column: column, op: ">", value: value} }
+
+// Gte is "column >= value".
+func Gte(column string, value any) Cond { return compare{column: column, op: ">=", value: value} }
+
+// Lt is "column < value".
+func Lt(column string, value any) Cond { return compare{column: column, op: "<", value: value} }
+
+// Lte is "column <= value".
+func Lte(column string, value any) Cond { return compare{column: column, op: "<=", value: value} }
+
+// Like is "column LIKE pattern".
+func Like(column string, pattern string) Cond {
+	return compare{column: column, op: "LIKE", value: pattern}
+}
+
+// ILike is "column ILIKE pattern".
+func ILike(column string, pattern string) Cond {
+	return compare{column: column, op: "ILIKE", value: pattern}
+}
+
+type null struct {
+	column string
+	not    bool
+}
+
+func (c null) write(buf *buffer) error {
+	buf.WriteString(Ident(c.column) + " IS ")
+
+	if c.not {
+		buf.WriteString("NOT ")
+	}
+
+	buf.WriteString("NULL")
+
+	return nil
+}
+
+// IsNull is "column IS NULL".
+func IsNull(column string) Cond { return null{column: column} }
+
+// IsNotNull is "column IS NOT NULL".
+func IsNotNull(column string) Cond { return null{column: column, not: true} }
+
+type in struct {
+	column string
+	values any
+	not    bool
+}
+
+func (c in) write(buf *buffer) error {
+	op := " IN ("
+	if c.not {
+		op = " NOT IN ("
+	}
+
+	if sub, ok := c.values.(Builder); ok {
+		buf.WriteString(Ident(c.column) + op)
+
+		if err := sub.write(buf); err != nil {
+			return err
+		}
+
+		buf.WriteString(")")
+
+		return nil
+	}
+
+	v := reflect.ValueOf(c.values)
+
+	_, bytes := c.values.([]byte)
+	if bytes || (v.Kind() != reflect.Slice && v.Kind() != reflect.Array) {
+		if c.not {
+			return NotEq(c.column, c.values).write(buf)
+		}
+
+		return Eq(c.column, c.values).write(buf)
+	}
+
+	// "IN ()" is invalid SQL, the empty list matches nothing.
+	if v.Len() == 0 {
+		if c.not {
+			buf.WriteString("TRUE")
+		} else {
+			buf.WriteString("FALSE")
+		}
+
+		return nil
+	}
+
+	buf.WriteString(Ident(c.column) + op)
+
+	for i := 0; i < v.Len(); i++ {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+
+		buf.arg(v.Index(i).Interface())
+	}
+
+	buf.WriteString(")")
+
+	return nil
+}
+
+// In is "column IN (values...)". Values is the slice or the subquery.
+// The empty slice matches nothing.
+func In(column string, values any) Cond { return in{column: column, values: values} }
+
+// NotIn is "column NOT IN (values...)". The empty slice matches everything.
+func NotIn(column string, values any) Cond { return in{column: column, values: values, not: true} }
+
+type group struct {
+	op    string
+	conds []Cond
+}
+
+func (g group) write(buf *buffer) error {
+	conds := make([]Cond, 0, len(g.conds))
+
+	for _, c := range g.conds {
+		if c != nil {
+			conds = append(conds, c)
+		}
+	}
+
+	if len(conds) == 0 {
+		buf.WriteString("TRUE")
+
+		return nil
+	}
+
+	if len(conds) == 1 {
+		return conds[0].write(buf)
+	}
+
+	for i, c := range conds {
+		if i > 0 {
+			buf.WriteString(" " + g.op + " ")
+		}
+
+		// raw expressions and groups can contain operators with lower
+		// precedence, for example OR inside AND.
+		_, isGroup := c.(group)
+		_, isExpr := c.(Expr)
+
+		nested := isGroup || isExpr
+		if nested {
+			buf.WriteString("(")
+		}
+
+		if err := c.write(buf); err != nil {
+			return err
+		}
+
+		if nested {
+			buf.WriteString(")")
+		}
+	}
+
+	return nil
+}
+
+// And joins the conditions by AND, nil conditions are skipped.
+func And(conds ...Cond) Cond { return group{op: "AND", conds: conds} }
+
+// Or joins the conditions by OR, nil conditions are skipped.
+func Or(conds ...Cond) Cond { return group{op: "OR", conds: conds} }
+
+type not struct {
+	cond Cond
+}
+
+func (n not) write(buf *buffer) error {
+	buf.WriteString("NOT (")
+
+	if err := n.cond.write(buf); err != nil {
+		return err
+	}
+
+	buf.WriteString(")")
+
+	return nil
+}
+
+// Not negates the condition.
+func Not(cond Cond) Cond { return not{cond: cond} }
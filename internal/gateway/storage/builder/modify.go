@@ -0,0 +1,425 @@
+package builder
+
+import (
+	"fmt"
+	"sort"
+)
+
+// InsertBuilder builds INSERT queries.
+type InsertBuilder struct {
+	ctes      []cte
+	table     string
+	columns   []string
+	rows      [][]any
+	conflict  *onConflict
+	returning []string
+}
+
+type onConflict struct {
+	columns    []string
+	constraint string
+	update     []string
+}
+
+// Insert starts INSERT query into the table.
+func Insert(table string) *InsertBuilder {
+	return &InsertBuilder{table: table}
+}
+
+// With adds the common table expression.
+func (b *InsertBuilder) With(name string, query Builder) *InsertBuilder {
+	b.ctes = append(b.ctes, cte{name: name, query: query})
+
+	return b
+}
+
+// Columns sets the inserted columns.
+func (b *InsertBuilder) Columns(columns ...string) *InsertBuilder {
+	b.columns = columns
+
+	return b
+}
+
+// Values adds the row, values are in order of Columns. Call it several
+// times to insert many rows.
+func (b *InsertBuilder) Values(values ...any) *InsertBuilder {
+	b.rows = append(b.rows, values)
+
+	return b
+}
+
+// SetMap sets the columns and the single row from the map, columns are
+// sorted by name.
+func (b *InsertBuilder) SetMap(values map[string]any) *InsertBuilder {
+	columns := make([]string, 0, len(values))
+
+	for c := range values {
+		columns = append(columns, c)
+	}
+
+	sort.Strings(columns)
+
+	row := make([]any, 0, len(columns))
+
+	for _, c := range columns {
+		row = append(row, values[c])
+	}
+
+	b.columns = columns
+	b.rows = [][]any{row}
+
+	return b
+}
+
+// OnConflict sets the conflict target columns. Without DoUpdate the
+// conflicting rows are skipped (DO NOTHING).
+func (b *InsertBuilder) OnConflict(columns ...string) *InsertBuilder {
+	b.conflict = &onConflict{columns: columns}
+
+	return b
+}
+
+// OnConflictConstraint sets the conflict target by the constraint name.
+func (b *InsertBuilder) OnConflictConstraint(name string) *InsertBuilder {
+	b.conflict = &onConflict{constraint: name}
+
+	return b
+}
+
+// DoUpdate updates the columns of the conflicting row by the inserted
+// values: "DO UPDATE SET column = EXCLUDED.column".
+func (b *InsertBuilder) DoUpdate(columns ...string) *InsertBuilder {
+	if b.conflict == nil {
+		b.conflict = &onConflict{}
+	}
+
+	b.conflict.update = columns
+
+	return b
+}
+
+// Returning adds RETURNING clause.
+func (b *InsertBuilder) Returning(columns ...string) *InsertBuilder {
+	b.returning = columns
+
+	return b
+}
+
+// Build returns SQL and the arguments of the query.
+func (b *InsertBuilder) Build() (string, []any, error) {
+	return build(b)
+}
+
+func (b *InsertBuilder) write(buf *buffer) error {
+	if b.table == "" {
+		return ErrNoTable
+	}
+
+	if len(b.rows) == 0 || len(b.columns) == 0 {
+		return ErrNoValues
+	}
+
+	if err := writeWith(buf, b.ctes); err != nil {
+		return err
+	}
+
+	buf.WriteString("INSERT INTO " + Ident(b.table) + " (")
+	buf.idents(b.columns)
+	buf.WriteString(") VALUES ")
+
+	for i, row := range b.rows {
+		if len(row) != len(b.columns) {
+			return fmt.Errorf("row %d has %d values, expected %d", i, len(row), len(b.columns))
+		}
+
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+
+		buf.WriteString("(")
+
+		for j, v := range row {
+			if j > 0 {
+				buf.WriteString(", ")
+			}
+
+			if err := writeValue(buf, v); err != nil {
+				return err
+			}
+		}
+
+		buf.WriteString(")")
+	}
+
+	b.writeConflict(buf)
+	writeReturning(buf, b.returning)
+
+	return nil
+}
+
+func (b *InsertBuilder) writeConflict(buf *buffer) {
+	if b.conflict == nil {
+		return
+	}
+
+	buf.WriteString(" ON CONFLICT")
+
+	switch {
+	case b.conflict.constraint != "":
+		buf.WriteString(" ON CONSTRAINT " + quote(b.conflict.constraint))
+	case len(b.conflict.columns) > 0:
+		buf.WriteString(" (")
+		buf.idents(b.conflict.columns)
+		buf.WriteString(")")
+	}
+
+	if len(b.conflict.update) == 0 {
+		buf.WriteString(" DO NOTHING")
+
+		return
+	}
+
+	buf.WriteString(" DO UPDATE SET ")
+
+	for i, c := range b.conflict.update {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+
+		buf.WriteString(quote(c) + " = EXCLUDED." + quote(c))
+	}
+}
+
+type set struct {
+	column string
+	value  any
+}
+
+// UpdateBuilder builds UPDATE queries.
+type UpdateBuilder struct {
+	ctes      []cte
+	table     string
+	sets      []set
+	from      string
+	where     []Cond
+	all       bool
+	returning []string
+}
+
+// Update starts UPDATE query of the table.
+func Update(table string) *UpdateBuilder {
+	return &UpdateBuilder{table: table}
+}
+
+// With adds the common table expression.
+func (b *UpdateBuilder) With(name string, query Builder) *UpdateBuilder {
+	b.ctes = append(b.ctes, cte{name: name, query: query})
+
+	return b
+}
+
+// Set sets the column to the value. Value can be Expr, for example
+// Raw("now()") or Raw("amount + ?", 1).
+func (b *UpdateBuilder) Set(column string, value any) *UpdateBuilder {
+	b.sets = append(b.sets, set{column: column, value: value})
+
+	return b
+}
+
+// SetIf sets the column only if ok is true, used for partial updates.
+func (b *UpdateBuilder) SetIf(ok bool, column string, value any) *UpdateBuilder {
+	if ok {
+		b.Set(column, value)
+	}
+
+	return b
+}
+
+// From adds FROM clause, for example the name of CTE.
+func (b *UpdateBuilder) From(table string) *UpdateBuilder {
+	b.from = table
+
+	return b
+}
+
+// Where adds the conditions joined by AND, nil conditions are skipped.
+func (b *UpdateBuilder) Where(conds ...Cond) *UpdateBuilder {
+	b.where = appendConds(b.where, conds)
+
+	return b
+}
+
+// WhereIf adds the conditions only if ok is true.
+func (b *UpdateBuilder) WhereIf(ok bool, conds ...Cond) *UpdateBuilder {
+	if ok {
+		b.Where(conds...)
+	}
+
+	return b
+}
+
+// All allows the query without WHERE that updates the whole table.
+func (b *UpdateBuilder) All() *UpdateBuilder {
+	b.all = true
+
+	return b
+}
+
+// Returning adds RETURNING clause.
+func (b *UpdateBuilder) Returning(columns ...string) *UpdateBuilder {
+	b.returning = columns
+
+	return b
+}
+
+// Build returns SQL and the arguments of the query.
+func (b *UpdateBuilder) Build() (string, []any, error) {
+	return build(b)
+}
+
+func (b *UpdateBuilder) write(buf *buffer) error {
+	if b.table == "" {
+		return ErrNoTable
+	}
+
+	if len(b.sets) == 0 {
+		return ErrNoValues
+	}
+
+	if len(b.where) == 0 && !b.all {
+		return ErrUnconditional
+	}
+
+	if err := writeWith(buf, b.ctes); err != nil {
+		return err
+	}
+
+	buf.WriteString("UPDATE " + Ident(b.table) + " SET ")
+
+	for i, s := range b.sets {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+
+		buf.WriteString(quote(s.column) + " = ")
+
+		if err := writeValue(buf, s.value); err != nil {
+			return err
+		}
+	}
+
+	if b.from != "" {
+		buf.WriteString(" FROM " + Ident(b.from))
+	}
+
+	if err := writeWhere(buf, b.where); err != nil {
+		return err
+	}
+
+	writeReturning(buf, b.returning)
+
+	return nil
+}
+
+// DeleteBuilder builds DELETE queries.
+type DeleteBuilder struct {
+	ctes      []cte
+	table     string
+	where     []Cond
+	all       bool
+	returning []string
+}
+
+// Delete starts DELETE query from the table.
+func Delete(table string) *DeleteBuilder {
+	return &DeleteBuilder{table: table}
+}
+
+// With adds the common table expression.
+func (b *DeleteBuilder) With(name string, query Builder) *DeleteBuilder {
+	b.ctes = append(b.ctes, cte{name: name, query: query})
+
+	return b
+}
+
+// Where adds the conditions joined by AND, nil conditions are skipped.
+func (b *DeleteBuilder) Where(conds ...Cond) *DeleteBuilder {
+	b.where = appendConds(b.where, conds)
+
+	return b
+}
+
+// WhereIf adds the conditions only if ok is true.
+func (b *DeleteBuilder) WhereIf(ok bool, conds ...Cond) *DeleteBuilder {
+	if ok {
+		b.Where(conds...)
+	}
+
+	return b
+}
+
+// All allows the query without WHERE that deletes all the rows.
+func (b *DeleteBuilder) All() *DeleteBuilder {
+	b.all = true
+
+	return b
+}
+
+// Returning adds RETURNING clause.
+func (b *DeleteBuilder) Returning(columns ...string) *DeleteBuilder {
+	b.returning = columns
+
+	return b
+}
+
+// Build returns SQL and the arguments of the query.
+func (b *DeleteBuilder) Build() (string, []any, error) {
+	return build(b)
+}
+
+func (b *DeleteBuilder) write(buf *buffer) error {
+	if b.table == "" {
+		return ErrNoTable
+	}
+
+	if len(b.where) == 0 && !b.all {
+		return ErrUnconditional
+	}
+
+	if err := writeWith(buf, b.ctes); err != nil {
+		return err
+	}
+
+	buf.WriteString("DELETE FROM " + Ident(b.table))
+
+	if err := writeWhere(buf, b.where); err != nil {
+		return err
+	}
+
+	writeReturning(buf, b.returning)
+
+	return nil
+}
+
+// writeValue writes the placeholder of the value, Expr, Column and
+// subqueries are written as is.
+func writeValue(buf *buffer, v any) error {
+	switch v := v.(type) {
+	case Column:
+		buf.WriteString(Ident(string(v)))
+	case Expr:
+		return v.write(buf)
+	case Builder:
+		buf.WriteString("(")
+
+		if err := v.write(buf); err != nil {
+			return err
+		}
+
+		buf.WriteString(")")
+	default:
+		buf.arg(v)
+	}
+
+	return nil
+}
@@ -0,0 +1,257 @@
+package builder
+
+import (
+	"strings"
+)
+
+// Direction of ORDER BY.
+type Direction uint8
+
+const (
+	Asc Direction = iota
+	Desc
+)
+
+type join struct {
+	kind  string
+	table string
+	on    Cond
+}
+
+type order struct {
+	column    string
+	direction Direction
+}
+
+// SelectBuilder builds SELECT queries.
+type SelectBuilder struct {
+	ctes    []cte
+	columns []Expr
+	from    string
+	joins   []join
+	where   []Cond
+	groupBy []string
+	orderBy []order
+	limit   *int
+	offset  *int
+}
+
+// Select starts SELECT query with the quoted columns. Without columns
+// "SELECT *" is built.
+func Select(columns ...string) *SelectBuilder {
+	b := &SelectBuilder{}
+
+	return b.Columns(columns...)
+}
+
+// With adds the common table expression.
+func (b *SelectBuilder) With(name string, query Builder) *SelectBuilder {
+	b.ctes = append(b.ctes, cte{name: name, query: query})
+
+	return b
+}
+
+// Columns adds the quoted columns.
+func (b *SelectBuilder) Columns(columns ...string) *SelectBuilder {
+	for _, c := range columns {
+		b.columns = append(b.columns, Raw(Ident(c)))
+	}
+
+	return b
+}
+
+// ColumnExpr adds the raw column expression, for example
+// Raw("count(*) AS total").
+func (b *SelectBuilder) ColumnExpr(expr Expr) *SelectBuilder {
+	b.columns = append(b.columns, expr)
+
+	return b
+}
+
+// From sets the table, alias is supported: "orders o".
+func (b *SelectBuilder) From(table string) *SelectBuilder {
+	b.from = table
+
+	return b
+}
+
+// Join adds INNER JOIN.
+func (b *SelectBuilder) Join(table string, on Cond) *SelectBuilder {
+	b.joins = append(b.joins, join{kind: "JOIN", table: table, on: on})
+
+	return b
+}
+
+// LeftJoin adds LEFT JOIN.
+func (b *SelectBuilder) LeftJoin(table string, on Cond) *SelectBuilder {
+	b.joins = append(b.joins, join{kind: "LEFT JOIN", table: table, on: on})
+
+	return b
+}
+
+// Where adds the conditions joined by AND, nil conditions are skipped.
+func (b *SelectBuilder) Where(conds ...Cond) *SelectBuilder {
+	b.where = appendConds(b.where, conds)
+
+	return b
+}
+
+// WhereIf adds the conditions only if ok is true.
+func (b *SelectBuilder) WhereIf(ok bool, conds ...Cond) *SelectBuilder {
+	if ok {
+		b.Where(conds...)
+	}
+
+	return b
+}
+
+// GroupBy adds the quoted columns to GROUP BY.
+func (b *SelectBuilder) GroupBy(columns ...string) *SelectBuilder {
+	b.groupBy = append(b.groupBy, columns...)
+
+	return b
+}
+
+// OrderBy adds the column to ORDER BY, Asc by default.
+func (b *SelectBuilder) OrderBy(column string, direction ...Direction) *SelectBuilder {
+	o := order{column: column}
+
+	if len(direction) > 0 {
+		o.direction = direction[0]
+	}
+
+	b.orderBy = append(b.orderBy, o)
+
+	return b
+}
+
+// Limit sets LIMIT, passed as the argument.
+func (b *SelectBuilder) Limit(limit int) *SelectBuilder {
+	b.limit = &limit
+
+	return b
+}
+
+// Offset sets OFFSET, passed as the argument.
+func (b *SelectBuilder) Offset(offset int) *SelectBuilder {
+	b.offset = &offset
+
+	return b
+}
+
+// Clone returns the copy of the query, used to build several queries from
+// the same filters.
+func (b *SelectBuilder) Clone() *SelectBuilder {
+	c := *b
+
+	c.ctes = append([]cte(nil), b.ctes...)
+	c.columns = append([]Expr(nil), b.columns...)
+	c.joins = append([]join(nil), b.joins...)
+	c.where = append([]Cond(nil), b.where...)
+	c.groupBy = append([]string(nil), b.groupBy...)
+	c.orderBy = append([]order(nil), b.orderBy...)
+
+	return &c
+}
+
+// Count returns the query that counts rows of the query without ORDER BY,
+// LIMIT and OFFSET: WITH "q" AS (...) SELECT count(*) FROM "q".
+func (b *SelectBuilder) Count() Builder {
+	c := b.Clone()
+
+	c.orderBy = nil
+	c.limit = nil
+	c.offset = nil
+
+	return Select().ColumnExpr(Raw("count(*)")).From("q").With("q", c)
+}
+
+// Build returns SQL and the arguments of the query.
+func (b *SelectBuilder) Build() (string, []any, error) {
+	return build(b)
+}
+
+//revive:disable:cognitive-complexity
+func (b *SelectBuilder) write(buf *buffer) error {
+	if b.from == "" {
+		return ErrNoTable
+	}
+
+	if err := writeWith(buf, b.ctes); err != nil {
+		return err
+	}
+
+	buf.WriteString("SELECT ")
+
+	if len(b.columns) == 0 {
+		buf.WriteString("*")
+	}
+
+	for i, c := range b.columns {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+
+		if err := c.write(buf); err != nil {
+			return err
+		}
+	}
+
+	buf.WriteString(" FROM " + Ident(b.from))
+
+	for _, j := range b.joins {
+		buf.WriteString(" " + j.kind + " " + Ident(j.table) + " ON ")
+
+		if err := j.on.write(buf); err != nil {
+			return err
+		}
+	}
+
+	if err := writeWhere(buf, b.where); err != nil {
+		return err
+	}
+
+	if len(b.groupBy) > 0 {
+		buf.WriteString(" GROUP BY ")
+		buf.idents(b.groupBy)
+	}
+
+	if len(b.orderBy) > 0 {
+		parts := make([]string, 0, len(b.orderBy))
+
+		for _, o := range b.orderBy {
+			part := Ident(o.column)
+			if o.direction == Desc {
+				part += " DESC"
+			}
+
+			parts = append(parts, part)
+		}
+
+		buf.WriteString(" ORDER BY " + strings.Join(parts, ", "))
+	}
+
+	if b.limit != nil {
+		buf.WriteString(" LIMIT ")
+		buf.arg(*b.limit)
+	}
+
+	if b.offset != nil {
+		buf.WriteString(" OFFSET ")
+		buf.arg(*b.offset)
+	}
+
+	return nil
+}
+
+//revive:enable:cognitive-complexity
+
+func appendConds(dst, conds []Cond) []Cond {
+	for _, c := range conds {
+		if c != nil {
+			dst = append(dst, c)
+		}
+	}
+
+	return dst
+}
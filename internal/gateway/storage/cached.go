@@ -0,0 +1,230 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Melenium2/go-template/internal/common/erx"
+	"github.com/Melenium2/go-template/internal/common/helper/cache"
+	"github.com/Melenium2/go-template/internal/common/tenant"
+	"github.com/Melenium2/go-template/internal/common/tiered"
+	"github.com/Melenium2/go-template/internal/common/tx"
+)
+
+// CacheConfig of the cached repository method.
+type CacheConfig[P any] struct {
+	// Name of the cache, must be unique.
+	Name string
+	// Key returns the key of the parameters.
+	//
+	// Default: JSON of the parameters.
+	Key func(params P) string
+	// Tags returns the tags of the result, the result is stale after any of
+	// them is invalidated by tiered.Tags.Invalidate.
+	Tags func(params P) []string
+	// NotFoundTTL caches erx.ErrNotFound of the method for the duration.
+	//
+	// Default: not found results are not cached.
+	NotFoundTTL time.Duration
+	// Options of the cache, for example the backend and the TTL.
+	Options []tiered.Option
+}
+
+type cachedEntry[T any] struct {
+	Value    T    `json:"value"`
+	NotFound bool `json:"not_found,omitempty"`
+	// ExpiresAt of the not found result.
+	ExpiresAt time.Time         `json:"expires_at,omitzero"`
+	Tags      map[string]string `json:"tags,omitempty"`
+}
+
+// Cached is the read-through cache of the repository method with the
+// parameters P. Values are serialized to JSON. Keys and tags are scoped by
+// the tenant of the context, so tenants do not share cached values.
+//
+// Inside the transaction the loaded values are cached only after commit, and
+// the cached values do not see changes of the transaction, so read the
+// database directly after changes in the same transaction.
+//
+// Example:
+//
+//	r.byID = storage.NewCached(c.CacheTags, r.get, storage.CacheConfig[uuid.UUID]{
+//		Name:        "orders",
+//		Tags:        func(id uuid.UUID) []string { return []string{"order:" + id.String()} },
+//		NotFoundTTL: time.Minute,
+//		Options:     []tiered.Option{tiered.WithBackend(c.CacheBackend)},
+//	})
+//
+//	// in the command, the cached order is stale after commit.
+//	err := c.CacheTags.Invalidate(ctx, "order:"+id.String())
+type Cached[P, T any] struct {
+	cfg   CacheConfig[P]
+	tags  *tiered.Tags
+	load  func(ctx context.Context, params P) (T, error)
+	cache *tiered.Cache[cachedEntry[T]]
+	loads cache.Flight[string, cachedEntry[T]]
+}
+
+func NewCached[P, T any](
+	tags *tiered.Tags,
+	load func(ctx context.Context, params P) (T, error),
+	cfg CacheConfig[P],
+) *Cached[P, T] {
+	return &Cached[P, T]{
+		cfg:   cfg,
+		tags:  tags,
+		load:  load,
+		cache: tiered.New(cfg.Name, tiered.JSON[cachedEntry[T]](), cfg.Options...),
+	}
+}
+
+// Get returns the cached result of the method or calls it. Concurrent calls
+// with the same parameters outside transactions wait for the single call.
+func (c *Cached[P, T]) Get(ctx context.Context, params P) (T, error) {
+	var zero T
+
+	key, err := c.key(ctx, params)
+	if err != nil {
+		return zero, err
+	}
+
+	if e, ok := c.lookup(ctx, key); ok {
+		return e.result()
+	}
+
+	// values of the transaction may be not committed, so they are not
+	// shared with other calls and are cached after commit.
+	if tx.InTx(ctx) {
+		e, cacheable, err := c.fetch(ctx, params)
+		if err != nil {
+			return zero, err
+		}
+
+		if cacheable {
+			_ = tx.AfterCommit(ctx, func(ctx context.Context) {
+				c.store(ctx, key, e)
+			})
+		}
+
+		return e.result()
+	}
+
+	e, err := c.loads.Do(key, func() (cachedEntry[T], error) {
+		e, cacheable, err := c.fetch(ctx, params)
+		if err != nil {
+			return e, err
+		}
+
+		if cacheable {
+			c.store(ctx, key, e)
+		}
+
+		return e, nil
+	})
+	if err != nil {
+		return zero, err
+	}
+
+	return e.result()
+}
+
+// fetch calls the method. The versions of the tags are taken before the
+// call, so the value is stale if the tags are invalidated during the call.
+// The value is not cacheable if the versions are unknown, it could not be
+// invalidated.
+func (c *Cached[P, T]) fetch(ctx context.Context, params P) (cachedEntry[T], bool, error) {
+	var (
+		e         cachedEntry[T]
+		err       error
+		cacheable = true
+	)
+
+	if c.cfg.Tags != nil {
+		if e.Tags, err = c.tags.Versions(ctx, c.cfg.Tags(params)...); err != nil {
+			slog.Warn("can not get cache tags", slog.String("cache", c.cfg.Name), slog.String("error", err.Error()))
+
+			cacheable = false
+		}
+	}
+
+	e.Value, err = c.load(ctx, params)
+
+	switch {
+	case err == nil:
+	case c.cfg.NotFoundTTL > 0 && errors.Is(err, erx.ErrNotFound):
+		e.NotFound = true
+		e.ExpiresAt = time.Now().Add(c.cfg.NotFoundTTL)
+	default:
+		return e, false, err
+	}
+
+	return e, cacheable, nil
+}
+
+func (c *Cached[P, T]) store(ctx context.Context, key string, e cachedEntry[T]) {
+	if err := c.cache.Set(ctx, key, e); err != nil {
+		slog.Warn("can not cache value", slog.String("cache", c.cfg.Name), slog.String("error", err.Error()))
+	}
+}
+
+// lookup returns the cached entry if its tags are not invalidated.
+func (c *Cached[P, T]) lookup(ctx context.Context, key string) (cachedEntry[T], bool) {
+	e, ok, err := c.cache.Get(ctx, key)
+	if err != nil {
+		slog.Warn("can not get cached value", slog.String("cache", c.cfg.Name), slog.String("error", err.Error()))
+
+		return e, false
+	}
+
+	if !ok || e.NotFound && !time.Now().Before(e.ExpiresAt) {
+		return e, false
+	}
+
+	if len(e.Tags) == 0 {
+		return e, true
+	}
+
+	tags := make([]string, 0, len(e.Tags))
+
+	for tag := range e.Tags {
+		tags = append(tags, tag)
+	}
+
+	versions, err := c.tags.Versions(ctx, tags...)
+	if err != nil {
+		return e, false
+	}
+
+	for tag, v := range e.Tags {
+		if versions[tag] != v {
+			return e, false
+		}
+	}
+
+	return e, true
+}
+
+func (c *Cached[P, T]) key(ctx context.Context, params P) (string, error) {
+	if c.cfg.Key != nil {
+		return tenant.Scope(ctx, c.cfg.Key(params)), nil
+	}
+
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return "", fmt.Errorf("can not make cache key, %w", err)
+	}
+
+	return tenant.Scope(ctx, string(raw)), nil
+}
+
+func (e cachedEntry[T]) result() (T, error) {
+	if e.NotFound {
+		return e.Value, fmt.Errorf("%w: cached", erx.ErrNotFound)
+	}
+
+	return e.Value, nil
+}
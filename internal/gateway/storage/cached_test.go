@@ -0,0 +1,224 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Melenium2/go-template/internal/common/erx"
+	"github.com/Melenium2/go-template/internal/common/tenant"
+	"github.com/Melenium2/go-template/internal/common/tiered"
+	"github.com/Melenium2/go-template/internal/common/tx"
+)
+
+type orderLoader struct {
+	calls  int
+	orders map[int64]order
+}
+
+func (l *orderLoader) get(_ context.Context, id int64) (order, error) {
+	l.calls++
+
+	o, ok := l.orders[id]
+	if !ok {
+		return order{}, fmt.Errorf("%w: order %d", erx.ErrNotFound, id)
+	}
+
+	return o, nil
+}
+
+func newCachedOrders(tags *tiered.Tags, l *orderLoader, notFoundTTL time.Duration) *Cached[int64, order] {
+	return NewCached(tags, l.get, CacheConfig[int64]{
+		Name:        "orders",
+		Tags:        func(id int64) []string { return []string{fmt.Sprintf("order:%d", id)} },
+		NotFoundTTL: notFoundTTL,
+	})
+}
+
+func (suite *StorageSuite) TestCached_Get_Should_cache_result_until_tag_is_invalidated() {
+	var (
+		ctx    = context.Background()
+		tags   = tiered.NewTags(nil, nil)
+		loader = &orderLoader{orders: map[int64]order{1: {ID: 1, Status: "new"}}}
+		orders = newCachedOrders(tags, loader, 0)
+	)
+
+	for range 2 {
+		res, err := orders.Get(ctx, 1)
+		suite.Require().NoError(err)
+		suite.Assert().Equal("new", res.Status)
+	}
+
+	suite.Assert().Equal(1, loader.calls)
+
+	loader.orders[1] = order{ID: 1, Status: "paid"}
+
+	suite.Require().NoError(tags.Invalidate(ctx, "order:2"))
+
+	res, _ := orders.Get(ctx, 1)
+	suite.Assert().Equal("new", res.Status)
+
+	suite.Require().NoError(tags.Invalidate(ctx, "order:1"))
+
+	res, _ = orders.Get(ctx, 1)
+	suite.Assert().Equal("paid", res.Status)
+	suite.Assert().Equal(2, loader.calls)
+}
+
+func (suite *StorageSuite) TestCached_Get_Should_cache_not_found() {
+	var (
+		ctx    = context.Background()
+		loader = &orderLoader{orders: map[int64]order{}}
+		orders = newCachedOrders(tiered.NewTags(nil, nil), loader, time.Hour)
+	)
+
+	for range 2 {
+		_, err := orders.Get(ctx, 1)
+		suite.Assert().ErrorIs(err, erx.ErrNotFound)
+	}
+
+	suite.Assert().Equal(1, loader.calls)
+
+	// without NotFoundTTL the error is not cached.
+	orders = NewCached(tiered.NewTags(nil, nil), loader.get, CacheConfig[int64]{Name: "orders"})
+
+	for range 2 {
+		_, err := orders.Get(ctx, 1)
+		suite.Assert().ErrorIs(err, erx.ErrNotFound)
+	}
+
+	suite.Assert().Equal(3, loader.calls)
+}
+
+func (suite *StorageSuite) TestCached_Get_Should_not_cache_error() {
+	var (
+		ctx    = context.Background()
+		errDB  = errors.New("db")
+		calls  int
+		orders = NewCached(tiered.NewTags(nil, nil), func(context.Context, int64) (order, error) {
+			calls++
+
+			return order{}, errDB
+		}, CacheConfig[int64]{Name: "orders", NotFoundTTL: time.Hour})
+	)
+
+	for range 2 {
+		_, err := orders.Get(ctx, 1)
+		suite.Assert().ErrorIs(err, errDB)
+	}
+
+	suite.Assert().Equal(2, calls)
+}
+
+func (suite *StorageSuite) TestCached_Get_Should_cache_value_of_transaction_after_commit() {
+	var (
+		ctx    = context.Background()
+		tags   = tiered.NewTags(nil, nil)
+		loader = &orderLoader{orders: map[int64]order{1: {ID: 1, Status: "new"}}}
+		orders = newCachedOrders(tags, loader, 0)
+	)
+
+	suite.sqlMock.ExpectBegin()
+	suite.sqlMock.ExpectRollback()
+
+	err := tx.Manager().Do(ctx, func(ctx context.Context) error {
+		_, err := orders.Get(ctx, 1)
+		suite.Require().NoError(err)
+
+		return errors.New("rollback")
+	})
+	suite.Require().Error(err)
+
+	suite.sqlMock.ExpectBegin()
+	suite.sqlMock.ExpectCommit()
+
+	err = tx.Manager().Do(ctx, func(ctx context.Context) error {
+		_, err := orders.Get(ctx, 1)
+
+		return err
+	})
+	suite.Require().NoError(err)
+
+	_, err = orders.Get(ctx, 1)
+	suite.Require().NoError(err)
+	suite.Assert().Equal(2, loader.calls)
+}
+
+func (suite *StorageSuite) TestCached_Get_Should_invalidate_tags_after_commit() {
+	var (
+		ctx    = context.Background()
+		tags   = tiered.NewTags(nil, tiered.NewLocal())
+		loader = &orderLoader{orders: map[int64]order{1: {ID: 1, Status: "new"}}}
+		orders = newCachedOrders(tags, loader, 0)
+	)
+
+	_, err := orders.Get(ctx, 1)
+	suite.Require().NoError(err)
+
+	suite.sqlMock.ExpectBegin()
+	suite.sqlMock.ExpectCommit()
+
+	err = tx.Manager().Do(ctx, func(ctx context.Context) error {
+		loader.orders[1] = order{ID: 1, Status: "paid"}
+
+		suite.Require().NoError(tags.Invalidate(ctx, "order:1"))
+
+		// the tag is invalidated only after commit.
+		res, err := orders.Get(context.Background(), 1)
+		suite.Assert().Equal("new", res.Status)
+
+		return err
+	})
+	suite.Require().NoError(err)
+
+	res, err := orders.Get(ctx, 1)
+	suite.Require().NoError(err)
+	suite.Assert().Equal("paid", res.Status)
+}
+
+func (suite *StorageSuite) TestCached_Get_Should_not_share_values_between_tenants() {
+	var (
+		tags   = tiered.NewTags(nil, nil)
+		calls  int
+		orders = NewCached(tags, func(ctx context.Context, id int64) (order, error) {
+			calls++
+
+			status, _ := tenant.ID(ctx)
+
+			return order{ID: id, Status: status}, nil
+		}, CacheConfig[int64]{
+			Name: "orders",
+			Tags: func(id int64) []string { return []string{fmt.Sprintf("order:%d", id)} },
+		})
+	)
+
+	acme, err := tenant.WithID(context.Background(), "acme")
+	suite.Require().NoError(err)
+
+	globex, err := tenant.WithID(context.Background(), "globex")
+	suite.Require().NoError(err)
+
+	for range 2 {
+		for _, ctx := range []context.Context{acme, globex} {
+			res, err := orders.Get(ctx, 1)
+			suite.Require().NoError(err)
+
+			id, _ := tenant.ID(ctx)
+			suite.Assert().Equal(id, res.Status)
+		}
+	}
+
+	suite.Assert().Equal(2, calls)
+
+	// the tag of one tenant does not invalidate values of another.
+	suite.Require().NoError(tags.Invalidate(acme, "order:1"))
+
+	_, err = orders.Get(globex, 1)
+	suite.Require().NoError(err)
+	suite.Assert().Equal(2, calls)
+
+	_, err = orders.Get(acme, 1)
+	suite.Require().NoError(err)
+	suite.Assert().Equal(3, calls)
+}
@@ -0,0 +1,151 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/jmoiron/sqlx/reflectx"
+
+	"github.com/Melenium2/go-template/internal/common/pagination"
+	"github.com/Melenium2/go-template/internal/gateway/storage/builder"
+)
+
+// mapper matches the default mapper of sqlx, so fields are found by the same
+// names as the columns are scanned.
+var mapper = reflectx.NewMapperFunc("db", strings.ToLower)
+
+// Paginate runs the query for the page of items. The sort is applied to the
+// query, so the query must not have ORDER BY, LIMIT and OFFSET. With the
+// cursor the keyset condition is added, otherwise the offset of the request
+// is used. Values of the cursor are taken from the fields of T by db tags.
+//
+// Example:
+//
+//	q := builder.Select("id", "created_at", "status").
+//		From("orders").
+//		Where(builder.Eq("client_id", clientID))
+//
+//	sort := pagination.Sort{pagination.Desc("created_at"), pagination.Asc("id")}
+//
+//	page, err := storage.Paginate[Order](ctx, q, sort, req, r.cursors)
+func Paginate[T any](
+	ctx context.Context,
+	q *builder.SelectBuilder,
+	sort pagination.Sort,
+	req pagination.Request,
+	codec *pagination.Codec,
+) (pagination.Page[T], error) {
+	var page pagination.Page[T]
+
+	if len(sort) == 0 {
+		return page, fmt.Errorf("pagination sort is not set")
+	}
+
+	limit := req.PageLimit()
+
+	pageQuery := q.Clone()
+
+	if req.Cursor != "" {
+		values, err := codec.Decode(sort, req.Cursor)
+		if err != nil {
+			return page, err
+		}
+
+		pageQuery.Where(keyset(sort, values))
+	} else if req.Offset > 0 {
+		pageQuery.Offset(req.Offset)
+	}
+
+	for _, s := range sort {
+		if s.Desc {
+			pageQuery.OrderBy(s.Column, builder.Desc)
+		} else {
+			pageQuery.OrderBy(s.Column)
+		}
+	}
+
+	// one more item shows that there is the next page.
+	items, err := SelectQuery[T](ctx, pageQuery.Limit(limit+1))
+	if err != nil {
+		return page, err
+	}
+
+	if len(items) > limit {
+		items = items[:limit]
+
+		page.HasMore = true
+		page.NextCursor, err = cursor(codec, sort, items[len(items)-1])
+		if err != nil {
+			return page, err
+		}
+	}
+
+	page.Items = items
+
+	if req.WithTotal {
+		total, err := GetQuery[int64](ctx, q.Count())
+		if err != nil {
+			return page, err
+		}
+
+		page.Total = &total
+	}
+
+	return page, nil
+}
+
+// keyset returns the condition of the rows after the cursor. For the sort
+// (a DESC, b ASC) it is: a < $1 OR (a = $1 AND b > $2).
+func keyset(sort pagination.Sort, values []any) builder.Cond {
+	conds := make([]builder.Cond, 0, len(sort))
+
+	for i, s := range sort {
+		and := make([]builder.Cond, 0, i+1)
+
+		for j := 0; j < i; j++ {
+			and = append(and, builder.Eq(sort[j].Column, values[j]))
+		}
+
+		if s.Desc {
+			and = append(and, builder.Lt(s.Column, values[i]))
+		} else {
+			and = append(and, builder.Gt(s.Column, values[i]))
+		}
+
+		if len(and) == 1 {
+			conds = append(conds, and[0])
+		} else {
+			conds = append(conds, builder.And(and...))
+		}
+	}
+
+	return builder.Or(conds...)
+}
+
+func cursor[T any](codec *pagination.Codec, sort pagination.Sort, item T) (string, error) {
+	v := reflect.Indirect(reflect.ValueOf(item))
+	if v.Kind() != reflect.Struct {
+		return "", fmt.Errorf("can not paginate %T, struct is expected", item)
+	}
+
+	fields := mapper.TypeMap(v.Type()).Names
+	values := make([]any, 0, len(sort))
+
+	for _, s := range sort {
+		name := s.Field
+		if name == "" {
+			name = s.Column[strings.LastIndex(s.Column, ".")+1:]
+		}
+
+		field, ok := fields[name]
+		if !ok {
+			return "", fmt.Errorf("field %q of sort column %q is not found in %T", name, s.Column, item)
+		}
+
+		values = append(values, reflectx.FieldByIndexesReadOnly(v, field.Index).Interface())
+	}
+
+	return codec.Encode(sort, values)
+}
@@ -0,0 +1,71 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/Melenium2/go-template/internal/common/pagination"
+	"github.com/Melenium2/go-template/internal/gateway/storage/builder"
+)
+
+func (suite *StorageSuite) TestPaginate_Should_return_pages_by_cursor() {
+	var (
+		ctx   = context.Background()
+		codec = pagination.NewCodec([]byte("secret"))
+		sort  = pagination.Sort{pagination.Asc("status"), pagination.Desc("id")}
+		query = builder.Select("id", "status").From("orders").Where(builder.Eq("client_id", 7))
+	)
+
+	suite.sqlMock.ExpectQuery(
+		`SELECT "id", "status" FROM "orders" WHERE "client_id" = \$1 ORDER BY "status", "id" DESC LIMIT \$2`,
+	).
+		WithArgs(7, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).
+			AddRow(3, "new").
+			AddRow(2, "new").
+			AddRow(1, "new"))
+
+	suite.sqlMock.ExpectQuery(`WITH "q" AS \(SELECT "id", "status" FROM "orders" WHERE "client_id" = \$1\) ` +
+		`SELECT count\(\*\) FROM "q"`).
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+	page, err := Paginate[order](ctx, query, sort, pagination.Request{Limit: 2, WithTotal: true}, codec)
+	suite.Require().NoError(err)
+	suite.Assert().Equal([]order{{ID: 3, Status: "new"}, {ID: 2, Status: "new"}}, page.Items)
+	suite.Assert().True(page.HasMore)
+	suite.Assert().NotEmpty(page.NextCursor)
+	suite.Require().NotNil(page.Total)
+	suite.Assert().Equal(int64(3), *page.Total)
+
+	suite.sqlMock.ExpectQuery(`SELECT "id", "status" FROM "orders" `+
+		`WHERE "client_id" = \$1 AND \("status" > \$2 OR \("status" = \$3 AND "id" < \$4\)\) `+
+		`ORDER BY "status", "id" DESC LIMIT \$5`).
+		WithArgs(7, "new", "new", int64(2), 3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).AddRow(1, "new"))
+
+	page, err = Paginate[order](ctx, query, sort, pagination.Request{Limit: 2, Cursor: page.NextCursor}, codec)
+	suite.Require().NoError(err)
+	suite.Assert().Equal([]order{{ID: 1, Status: "new"}}, page.Items)
+	suite.Assert().False(page.HasMore)
+	suite.Assert().Empty(page.NextCursor)
+	suite.Assert().Nil(page.Total)
+}
+
+func (suite *StorageSuite) TestPaginate_Should_use_offset_without_cursor() {
+	suite.sqlMock.ExpectQuery(`SELECT \* FROM "orders" ORDER BY "id" LIMIT \$1 OFFSET \$2`).
+		WithArgs(21, 40).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status"}))
+
+	page, err := Paginate[order](
+		context.Background(),
+		builder.Select().From("orders"),
+		pagination.Sort{pagination.Asc("id")},
+		pagination.Request{Offset: 40},
+		pagination.NewCodec(nil),
+	)
+	suite.Require().NoError(err)
+	suite.Assert().Empty(page.Items)
+	suite.Assert().False(page.HasMore)
+}
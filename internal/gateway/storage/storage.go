@@ -0,0 +1,154 @@
+// Package storage contains repositories of the application and generic
+// helpers for them. Helpers run queries on the connection from the context
+// (tx.Manager().Conn), so repositories work inside and outside transactions
+// the same way.
+//
+// Example:
+//
+//	func (r *OrderStorage) Get(ctx context.Context, id uuid.UUID) (Order, error) {
+//		return storage.Get[Order](ctx, "SELECT id, status FROM orders WHERE id = $1", id)
+//	}
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/Melenium2/go-template/internal/common/erx"
+	"github.com/Melenium2/go-template/internal/common/tx"
+	"github.com/Melenium2/go-template/pkg/psql"
+)
+
+// Postgres error codes, see https://www.postgresql.org/docs/current/errcodes-appendix.html.
+const (
+	uniqueViolation     = "23505"
+	foreignKeyViolation = "23503"
+)
+
+// Get scans the single row into T using sqlx struct scanning. Returns
+// erx.ErrNotFound if the query returns no rows.
+func Get[T any](ctx context.Context, query string, args ...any) (T, error) {
+	var dest T
+
+	err := sqlx.GetContext(ctx, tx.Manager().Conn(ctx), &dest, query, args...)
+
+	return dest, translate(err)
+}
+
+// Select scans all the rows into the slice of T. Empty slice is returned if
+// the query returns no rows.
+func Select[T any](ctx context.Context, query string, args ...any) ([]T, error) {
+	dest := make([]T, 0)
+
+	err := sqlx.SelectContext(ctx, tx.Manager().Conn(ctx), &dest, query, args...)
+	if err != nil {
+		return nil, translate(err)
+	}
+
+	return dest, nil
+}
+
+// Exec executes the query without returning rows.
+func Exec(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	res, err := tx.Manager().Conn(ctx).ExecContext(ctx, query, args...)
+
+	return res, translate(err)
+}
+
+// NamedExec executes the query with named parameters from the struct or map.
+//
+// Example:
+//
+//	storage.NamedExec(ctx, "INSERT INTO orders (id, status) VALUES (:id, :status)", order)
+func NamedExec(ctx context.Context, query string, arg any) (sql.Result, error) {
+	res, err := sqlx.NamedExecContext(ctx, tx.Manager().Conn(ctx), query, arg)
+
+	return res, translate(err)
+}
+
+// Notify sends the notification to the channel on the connection from the
+// context. Inside the transaction it is delivered only after commit.
+//
+// Example:
+//
+//	storage.Notify(ctx, ordersChanged, OrderChanged{ID: order.ID})
+func Notify[T any](ctx context.Context, ch psql.Channel[T], payload T) error {
+	return translate(ch.Notify(ctx, tx.Manager().Conn(ctx), payload))
+}
+
+// Query is the query built by the builder package.
+type Query interface {
+	Build() (string, []any, error)
+}
+
+// GetQuery is like Get, but runs the built query.
+func GetQuery[T any](ctx context.Context, q Query) (T, error) {
+	query, args, err := q.Build()
+	if err != nil {
+		var empty T
+
+		return empty, err
+	}
+
+	return Get[T](ctx, query, args...)
+}
+
+// SelectQuery is like Select, but runs the built query.
+func SelectQuery[T any](ctx context.Context, q Query) ([]T, error) {
+	query, args, err := q.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	return Select[T](ctx, query, args...)
+}
+
+// ExecQuery is like Exec, but runs the built query.
+func ExecQuery(ctx context.Context, q Query) (sql.Result, error) {
+	query, args, err := q.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	return Exec(ctx, query, args...)
+}
+
+// Constraint returns the name of the violated constraint, so the repository
+// can return the domain error for the specific constraint.
+func Constraint(err error) (string, bool) {
+	var pgErr *pgconn.PgError
+
+	if !errors.As(err, &pgErr) || pgErr.ConstraintName == "" {
+		return "", false
+	}
+
+	return pgErr.ConstraintName, true
+}
+
+// translate maps errors of the database to errors of the erx package. The
+// original error is kept in the chain.
+func translate(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: %w", erx.ErrNotFound, err)
+	}
+
+	var pgErr *pgconn.PgError
+
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case uniqueViolation, foreignKeyViolation:
+			return fmt.Errorf("%w: %w", erx.ErrConflict, err)
+		}
+	}
+
+	return err
+}
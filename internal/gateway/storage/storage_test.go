@@ -0,0 +1,105 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/Melenium2/go-template/internal/common/erx"
+	"github.com/Melenium2/go-template/internal/common/tx"
+	"github.com/Melenium2/go-template/internal/gateway/storage/builder"
+)
+
+type order struct {
+	ID     int64  `db:"id"`
+	Status string `db:"status"`
+}
+
+type StorageSuite struct {
+	suite.Suite
+
+	sqlMock sqlmock.Sqlmock
+}
+
+func (suite *StorageSuite) SetupSuite() {
+	db, mock, _ := sqlmock.New()
+
+	suite.sqlMock = mock
+	tx.SetupManager(sqlx.NewDb(db, "postgres"))
+}
+
+func (suite *StorageSuite) TearDownTest() {
+	suite.Assert().NoError(suite.sqlMock.ExpectationsWereMet())
+}
+
+func (suite *StorageSuite) TestGet_Should_scan_row_into_struct() {
+	suite.sqlMock.ExpectQuery("SELECT id, status FROM orders").WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).AddRow(1, "new"))
+
+	res, err := Get[order](context.Background(), "SELECT id, status FROM orders WHERE id = $1", 1)
+	suite.Require().NoError(err)
+	suite.Assert().Equal(order{ID: 1, Status: "new"}, res)
+}
+
+func (suite *StorageSuite) TestGet_Should_return_not_found_if_no_rows() {
+	suite.sqlMock.ExpectQuery("SELECT id, status FROM orders").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status"}))
+
+	_, err := Get[order](context.Background(), "SELECT id, status FROM orders WHERE id = $1", 1)
+	suite.Assert().ErrorIs(err, erx.ErrNotFound)
+	suite.Assert().ErrorIs(err, sql.ErrNoRows)
+}
+
+func (suite *StorageSuite) TestSelect_Should_return_empty_slice_if_no_rows() {
+	suite.sqlMock.ExpectQuery("SELECT id, status FROM orders").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status"}))
+
+	res, err := Select[order](context.Background(), "SELECT id, status FROM orders")
+	suite.Require().NoError(err)
+	suite.Assert().Empty(res)
+	suite.Assert().NotNil(res)
+}
+
+func (suite *StorageSuite) TestNamedExec_Should_translate_unique_violation_to_conflict() {
+	suite.sqlMock.ExpectExec("INSERT INTO orders").WithArgs(1, "new").
+		WillReturnError(&pgconn.PgError{Code: uniqueViolation, ConstraintName: "orders_pkey"})
+
+	_, err := NamedExec(context.Background(), "INSERT INTO orders (id, status) VALUES (:id, :status)", order{
+		ID:     1,
+		Status: "new",
+	})
+	suite.Assert().ErrorIs(err, erx.ErrConflict)
+
+	constraint, ok := Constraint(err)
+	suite.Assert().True(ok)
+	suite.Assert().Equal("orders_pkey", constraint)
+}
+
+func (suite *StorageSuite) TestExec_Should_translate_foreign_key_violation_to_conflict() {
+	suite.sqlMock.ExpectExec("DELETE FROM clients").
+		WillReturnError(&pgconn.PgError{Code: foreignKeyViolation})
+
+	_, err := Exec(context.Background(), "DELETE FROM clients WHERE id = $1", 1)
+	suite.Assert().ErrorIs(err, erx.ErrConflict)
+}
+
+func (suite *StorageSuite) TestSelectQuery_Should_run_built_query() {
+	suite.sqlMock.ExpectQuery(`SELECT "id", "status" FROM "orders" WHERE "status" IN \(\$1, \$2\)`).
+		WithArgs("new", "paid").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).AddRow(1, "new"))
+
+	res, err := SelectQuery[order](context.Background(), builder.Select("id", "status").
+		From("orders").
+		Where(builder.In("status", []string{"new", "paid"})))
+	suite.Require().NoError(err)
+	suite.Assert().Equal([]order{{ID: 1, Status: "new"}}, res)
+}
+
+func TestStorageSuite(t *testing.T) {
+	suite.Run(t, new(StorageSuite))
+}
@@ -0,0 +1,219 @@
+package migration
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+)
+
+var ErrChecksumMismatch = errors.New("applied migration file was changed")
+
+// Pending is a migration that is not applied yet.
+type Pending struct {
+	File
+	SQL string
+}
+
+// Pending returns up migrations that will be applied by Up(), so they can be
+// reviewed before applying (dry-run). SQL of Go migrations is empty.
+func (c *Client) Pending() ([]Pending, error) {
+	statuses, err := c.Status()
+	if err != nil {
+		return nil, err
+	}
+
+	var res []Pending
+
+	for _, s := range statuses {
+		if s.Applied {
+			continue
+		}
+
+		if s.Go {
+			res = append(res, Pending{File: s.File})
+
+			continue
+		}
+
+		content, err := fs.ReadFile(c.source, s.Up)
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, Pending{File: s.File, SQL: string(content)})
+	}
+
+	return res, nil
+}
+
+// checksumTable stores checksums of applied migration files. The table is
+// named after the migration table, for example schema_migrations_checksums.
+func (c *Client) checksumTable() string {
+	return quoteIdent(c.qualifiedTable() + "_checksums")
+}
+
+// qualifiedTable returns the migration table name with the schema if the
+// schema is set.
+func (c *Client) qualifiedTable() string {
+	if c.schema == "" {
+		return c.table
+	}
+
+	return c.schema + "." + c.table
+}
+
+func (c *Client) ensureChecksumTable(ctx context.Context) error {
+	_, err := c.db.ExecContext(ctx, fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		version bigint NOT NULL PRIMARY KEY,
+		name text NOT NULL,
+		checksum text NOT NULL,
+		applied_at timestamptz NOT NULL DEFAULT now()
+	)`, c.checksumTable()))
+
+	return err
+}
+
+// VerifyChecksums compares checksums of the applied migration files with the
+// stored ones. ErrChecksumMismatch is returned if at least one file was edited
+// after it was applied.
+func (c *Client) VerifyChecksums(ctx context.Context) error {
+	if c.migrator == nil {
+		return ErrNotSetup
+	}
+
+	if err := c.ensureChecksumTable(ctx); err != nil {
+		return err
+	}
+
+	stored, err := c.storedChecksums(ctx)
+	if err != nil {
+		return err
+	}
+
+	files, err := ListFiles(c.source)
+	if err != nil {
+		return err
+	}
+
+	var changed []string
+
+	for _, f := range files {
+		sum, ok := stored[f.Version]
+		if !ok || f.Up == "" {
+			continue
+		}
+
+		actual, err := fileChecksum(c.source, f.Up)
+		if err != nil {
+			return err
+		}
+
+		if actual != sum {
+			changed = append(changed, f.Up)
+		}
+	}
+
+	if len(changed) > 0 {
+		return fmt.Errorf("%w: %s", ErrChecksumMismatch, strings.Join(changed, ", "))
+	}
+
+	return nil
+}
+
+// SyncChecksums stores checksums of the applied migrations and removes checksums
+// of the migrations that were rolled back.
+func (c *Client) SyncChecksums(ctx context.Context) error {
+	statuses, err := c.Status()
+	if err != nil {
+		return err
+	}
+
+	if err = c.ensureChecksumTable(ctx); err != nil {
+		return err
+	}
+
+	version, _, err := c.Version()
+	if err != nil {
+		return err
+	}
+
+	deleteQuery := fmt.Sprintf("DELETE FROM %s WHERE version > $1", c.checksumTable())
+
+	if _, err = c.db.ExecContext(ctx, deleteQuery, int64(version)); err != nil { //nolint:gosec
+		return err
+	}
+
+	insertQuery := fmt.Sprintf(
+		"INSERT INTO %s (version, name, checksum) VALUES ($1, $2, $3) ON CONFLICT (version) DO NOTHING",
+		c.checksumTable(),
+	)
+
+	for _, s := range statuses {
+		if !s.Applied || s.Dirty || s.Up == "" {
+			continue
+		}
+
+		sum, err := fileChecksum(c.source, s.Up)
+		if err != nil {
+			return err
+		}
+
+		if _, err = c.db.ExecContext(ctx, insertQuery, int64(s.Version), s.Up, sum); err != nil { //nolint:gosec
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *Client) storedChecksums(ctx context.Context) (map[uint]string, error) {
+	rows, err := c.db.QueryContext(ctx, fmt.Sprintf("SELECT version, checksum FROM %s", c.checksumTable()))
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	res := make(map[uint]string)
+
+	for rows.Next() {
+		var (
+			version int64
+			sum     string
+		)
+
+		if err = rows.Scan(&version, &sum); err != nil {
+			return nil, err
+		}
+
+		res[uint(version)] = sum //nolint:gosec
+	}
+
+	return res, rows.Err()
+}
+
+func fileChecksum(filesystem fs.FS, name string) (string, error) {
+	content, err := fs.ReadFile(filesystem, name)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(content)
+
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// quoteIdent quotes postgres identifier, schema qualified names are supported.
+func quoteIdent(name string) string {
+	parts := strings.Split(name, ".")
+
+	for i, p := range parts {
+		parts[i] = `"` + strings.ReplaceAll(p, `"`, `""`) + `"`
+	}
+
+	return strings.Join(parts, ".")
+}
@@ -0,0 +1,81 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyChecksums_Should_return_error_if_applied_file_was_changed(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	filesystem := fstest.MapFS{
+		"1_create_orders.up.sql": {Data: []byte("CREATE TABLE orders (id int);")},
+		"2_add_index.up.sql":     {Data: []byte("CREATE INDEX ON orders (id);")},
+		"3_add_column.up.sql":    {Data: []byte("ALTER TABLE orders ADD COLUMN name text;")},
+	}
+
+	original, err := fileChecksum(filesystem, "1_create_orders.up.sql")
+	require.NoError(t, err)
+
+	c := &Client{migrator: &migrate.Migrate{}, source: filesystem, db: db, table: DefaultMigrationTable}
+
+	mock.ExpectExec(`CREATE TABLE IF NOT EXISTS "schema_migrations_checksums"`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT version, checksum FROM "schema_migrations_checksums"`).
+		WillReturnRows(sqlmock.NewRows([]string{"version", "checksum"}).
+			AddRow(1, original).
+			AddRow(2, "edited"))
+
+	err = c.VerifyChecksums(context.Background())
+	require.ErrorIs(t, err, ErrChecksumMismatch)
+	assert.Contains(t, err.Error(), "2_add_index.up.sql")
+	assert.NotContains(t, err.Error(), "1_create_orders.up.sql")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRunDown_Should_rollback_transaction_if_down_file_fails(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	filesystem := fstest.MapFS{
+		"2_add_index.down.sql": {Data: []byte("DROP INDEX orders_id_idx;")},
+	}
+
+	c := &Client{source: filesystem, db: db}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DROP INDEX orders_id_idx").WillReturnError(errors.New("index does not exist"))
+	mock.ExpectRollback()
+
+	err = c.runDown(context.Background(), File{Version: 2, Down: "2_add_index.down.sql"})
+	assert.ErrorContains(t, err, "index does not exist")
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	err = c.runDown(context.Background(), File{Version: 3})
+	assert.Error(t, err)
+}
+
+func TestParseRecoverStrategy(t *testing.T) {
+	for _, s := range []RecoverStrategy{RecoverForce, RecoverPrevious, RecoverDown} {
+		parsed, err := ParseRecoverStrategy(s.String())
+		require.NoError(t, err)
+		assert.Equal(t, s, parsed)
+	}
+
+	_, err := ParseRecoverStrategy("drop")
+	assert.Error(t, err)
+}
+
+func TestQuoteIdent(t *testing.T) {
+	assert.Equal(t, `"schema_migrations"`, quoteIdent("schema_migrations"))
+	assert.Equal(t, `"tenant"."schema_migrations"`, quoteIdent("tenant.schema_migrations"))
+	assert.Equal(t, `"a""b"`, quoteIdent(`a"b`))
+}
@@ -7,10 +7,12 @@ import (
 	"errors"
 	"fmt"
 	"io/fs"
+	"os"
 	"sync"
 
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
 	"github.com/golang-migrate/migrate/v4/source/iofs"
 )
@@ -19,9 +21,24 @@ import (
 
 const DefaultMigrationTable = "schema_migrations"
 
+var ErrNotSetup = errors.New("migrate is not setup, use Setup() first")
+
 type Client struct {
 	once     sync.Once
 	migrator *migrate.Migrate
+	// source contains migration files, used to list migrations.
+	source       fs.FS
+	db           *sql.DB
+	table        string
+	goMigrations map[uint]GoMigration
+	// schema where migrations are applied, empty means search_path of the
+	// connection.
+	schema string
+	conn   *sql.Conn
+
+	runMu sync.Mutex
+	// runCtx is the context of the running migrations, see run.
+	runCtx context.Context //nolint:containedctx
 }
 
 func New() *Client {
@@ -29,51 +46,54 @@ func New() *Client {
 }
 
 func (c *Client) Setup(ctx context.Context, db *sql.DB, path string, migrTable ...string) error {
-	var (
-		tableName = DefaultMigrationTable
-		setupErr  error
-	)
-
-	if len(migrTable) > 0 && migrTable[0] != "" {
-		tableName = migrTable[0]
-	}
+	var setupErr error
 
 	c.once.Do(func() {
-		post, err := c.postgres(ctx, db, tableName)
+		input, err := source.Open(fmt.Sprintf("file://%s", path))
 		if err != nil {
 			setupErr = err
 
 			return
 		}
 
-		filePath := fmt.Sprintf("file://%s", path)
+		setupErr = c.setup(ctx, db, input, os.DirFS(path), migrTable...)
+	})
+
+	return setupErr
+}
+
+func (c *Client) SetupFS(ctx context.Context, db *sql.DB, filesystem fs.FS, migrTable ...string) error {
+	var setupErr error
 
-		migr, err := migrate.NewWithDatabaseInstance(filePath, "postgres", post)
+	c.once.Do(func() {
+		input, err := iofs.New(filesystem, ".")
 		if err != nil {
 			setupErr = err
 
 			return
 		}
 
-		c.migrator = migr
+		setupErr = c.setup(ctx, db, input, filesystem, migrTable...)
 	})
 
 	return setupErr
 }
 
-//revive:disable:cognitive-complexity
-func (c *Client) SetupFS(ctx context.Context, db *sql.DB, filesystem fs.FS, migrTable ...string) error {
-	var (
-		tableName = DefaultMigrationTable
-		setupErr  error
-	)
-
-	if len(migrTable) > 0 && migrTable[0] != "" {
-		tableName = migrTable[0]
-	}
+// SetupSchema is like SetupFS, but applies migrations inside the schema. The
+// schema is created if it does not exist, the migration table is created
+// inside the schema. Used to apply the same migrations to the schema of
+// each tenant, see Tenants.
+func (c *Client) SetupSchema(
+	ctx context.Context,
+	db *sql.DB,
+	filesystem fs.FS,
+	schema string,
+	migrTable ...string,
+) error {
+	var setupErr error
 
 	c.once.Do(func() {
-		post, err := c.postgres(ctx, db, tableName)
+		_, err := db.ExecContext(ctx, "CREATE SCHEMA IF NOT EXISTS "+quoteIdent(schema))
 		if err != nil {
 			setupErr = err
 
@@ -87,20 +107,52 @@ func (c *Client) SetupFS(ctx context.Context, db *sql.DB, filesystem fs.FS, migr
 			return
 		}
 
-		migr, err := migrate.NewWithInstance("iofs", input, "postgres", post)
-		if err != nil {
-			setupErr = err
+		c.schema = schema
 
-			return
-		}
-
-		c.migrator = migr
+		setupErr = c.setup(ctx, db, input, filesystem, migrTable...)
 	})
 
 	return setupErr
 }
 
-//revive:enable:cognitive-complexity
+// setup wraps the source and the database drivers, so the registered Go
+// migrations are applied in order with the SQL files.
+func (c *Client) setup(
+	ctx context.Context,
+	db *sql.DB,
+	input source.Driver,
+	filesystem fs.FS,
+	migrTable ...string,
+) error {
+	tableName := DefaultMigrationTable
+
+	if len(migrTable) > 0 && migrTable[0] != "" {
+		tableName = migrTable[0]
+	}
+
+	c.source = filesystem
+	c.db = db
+	c.table = tableName
+
+	post, err := c.postgres(ctx, db, tableName)
+	if err != nil {
+		return err
+	}
+
+	withGo, err := newGoSource(input, c)
+	if err != nil {
+		return err
+	}
+
+	migr, err := migrate.NewWithInstance("go", withGo, "postgres", &goDatabase{Driver: post, client: c})
+	if err != nil {
+		return err
+	}
+
+	c.migrator = migr
+
+	return nil
+}
 
 func (c *Client) postgres(ctx context.Context, db *sql.DB, table string) (*postgres.Postgres, error) {
 	conn, err := db.Conn(ctx)
@@ -112,6 +164,19 @@ func (c *Client) postgres(ctx context.Context, db *sql.DB, table string) (*postg
 		MigrationsTable: table,
 	}
 
+	if c.schema != "" {
+		// the connection is returned to the pool on Close(), so search_path
+		// is reset there.
+		_, err = conn.ExecContext(ctx, "SELECT set_config('search_path', $1, false)", quoteIdent(c.schema))
+		if err != nil {
+			return nil, errors.Join(err, conn.Close())
+		}
+
+		cfg.SchemaName = c.schema
+	}
+
+	c.conn = conn
+
 	post, err := postgres.WithConnection(ctx, conn, cfg)
 	if err != nil {
 		return nil, err
@@ -120,27 +185,92 @@ func (c *Client) postgres(ctx context.Context, db *sql.DB, table string) (*postg
 	return post, nil
 }
 
-func (c *Client) Up() error {
+// Up applies all pending migrations.
+func (c *Client) Up(ctx context.Context) error {
+	return c.run(ctx, func(m *migrate.Migrate) error { return m.Up() })
+}
+
+// Down rollbacks all applied migrations.
+func (c *Client) Down(ctx context.Context) error {
+	return c.run(ctx, func(m *migrate.Migrate) error { return m.Down() })
+}
+
+// Steps applies n pending migrations if n > 0, or rollbacks n applied
+// migrations if n < 0.
+func (c *Client) Steps(ctx context.Context, n int) error {
+	return c.run(ctx, func(m *migrate.Migrate) error { return m.Steps(n) })
+}
+
+// Migrate applies or rollbacks migrations until the version.
+func (c *Client) Migrate(ctx context.Context, version uint) error {
+	return c.run(ctx, func(m *migrate.Migrate) error { return m.Migrate(version) })
+}
+
+// run runs the migrations by fn, Go migrations get the context. The
+// migrator has no context, so it is kept by the client for the run.
+func (c *Client) run(ctx context.Context, fn func(m *migrate.Migrate) error) error {
 	if c.migrator == nil {
-		return fmt.Errorf("migrate is not setup, use Setup() first")
+		return ErrNotSetup
 	}
 
-	err := c.migrator.Up()
-	if errors.Is(err, migrate.ErrNoChange) {
-		return nil
+	c.runMu.Lock()
+	defer c.runMu.Unlock()
+
+	c.runCtx = ctx
+
+	defer func() {
+		c.runCtx = nil
+	}()
+
+	return ignoreNoChange(fn(c.migrator))
+}
+
+// Force sets the version without running migrations and resets the dirty
+// state. Version -1 means that no migrations are applied.
+func (c *Client) Force(version int) error {
+	if c.migrator == nil {
+		return ErrNotSetup
 	}
 
-	if err != nil {
-		return err
+	return c.migrator.Force(version)
+}
+
+// Version returns the current version of database and the dirty state. If
+// no migrations are applied, 0 is returned.
+func (c *Client) Version() (uint, bool, error) {
+	if c.migrator == nil {
+		return 0, false, ErrNotSetup
 	}
 
-	return nil
+	version, dirty, err := c.migrator.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return 0, false, nil
+	}
+
+	return version, dirty, err
 }
 
-func (c *Client) Down() error {
+// Close closes the source and the database connection of the migrator.
+func (c *Client) Close() error {
 	if c.migrator == nil {
-		return fmt.Errorf("migrate is not setup, use Setup() first")
+		return nil
+	}
+
+	var resetErr error
+
+	if c.schema != "" {
+		_, resetErr = c.conn.ExecContext(context.Background(), "RESET search_path")
+	}
+
+	srcErr, dbErr := c.migrator.Close()
+
+	return errors.Join(resetErr, srcErr, dbErr)
+}
+
+func ignoreNoChange(err error) error {
+	if errors.Is(err, migrate.ErrNoChange) {
+		return nil
 	}
 
-	return c.migrator.Down()
+	return err
 }
@@ -0,0 +1,339 @@
+package migration
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-migrate/migrate/v4/database"
+	"github.com/golang-migrate/migrate/v4/source"
+)
+
+const (
+	// goMarker is the body of the Go migration read from the source. The
+	// database driver recognizes the marker and runs the function instead
+	// of executing the body as SQL.
+	goMarker = "-- go-migration:"
+
+	defaultBatchSize = 1000
+)
+
+var ErrAlreadySetup = errors.New("migrate is already setup")
+
+// Func is a migration written in Go. It runs in the transaction.
+type Func func(ctx context.Context, tx *sql.Tx) error
+
+// BatchFunc processes up to limit rows in the transaction and returns the
+// number of processed rows. It is called until it returns less than limit.
+// Each call runs in its own transaction, so the function must skip the rows
+// that were already processed, for example:
+//
+//	UPDATE orders SET total = price * amount
+//	WHERE id IN (SELECT id FROM orders WHERE total IS NULL LIMIT $1)
+type BatchFunc func(ctx context.Context, tx *sql.Tx, limit int) (int64, error)
+
+// GoMigration is applied in order with the SQL files by its version and is
+// recorded in the same migration table.
+type GoMigration struct {
+	Version uint
+	Name    string
+	// Up applies the migration in the single transaction.
+	Up Func
+	// UpBatches applies the migration by batches of BatchSize rows outside
+	// the single transaction, used instead of Up for large backfills. If the
+	// migration fails, already processed batches are not rolled back.
+	UpBatches BatchFunc
+	// BatchSize is the limit passed to UpBatches, 1000 by default.
+	BatchSize int
+	// Down rollbacks the migration in the transaction, optional.
+	Down Func
+}
+
+// Register adds Go migrations to the client. Must be called before Setup.
+//
+// Example:
+//
+//	m := migration.New()
+//	err := m.Register(migration.GoMigration{
+//		Version: 1692290155,
+//		Name:    "backfill_order_totals",
+//		UpBatches: func(ctx context.Context, tx *sql.Tx, limit int) (int64, error) {
+//			res, err := tx.ExecContext(ctx, query, limit)
+//			if err != nil {
+//				return 0, err
+//			}
+//
+//			return res.RowsAffected()
+//		},
+//	})
+func (c *Client) Register(migrations ...GoMigration) error {
+	if c.migrator != nil {
+		return ErrAlreadySetup
+	}
+
+	if c.goMigrations == nil {
+		c.goMigrations = make(map[uint]GoMigration, len(migrations))
+	}
+
+	for _, m := range migrations {
+		if (m.Up == nil) == (m.UpBatches == nil) {
+			return fmt.Errorf("go migration %d must have either Up or UpBatches", m.Version)
+		}
+
+		if _, ok := c.goMigrations[m.Version]; ok {
+			return fmt.Errorf("go migration %d is registered twice", m.Version)
+		}
+
+		c.goMigrations[m.Version] = m
+	}
+
+	return nil
+}
+
+// goFiles returns the registered Go migrations as files.
+func (c *Client) goFiles() []File {
+	res := make([]File, 0, len(c.goMigrations))
+
+	for _, m := range c.goMigrations {
+		res = append(res, File{Version: m.Version, Name: m.Name, Go: true})
+	}
+
+	return res
+}
+
+// files returns SQL files of the source and Go migrations sorted by version.
+func (c *Client) files() ([]File, error) {
+	files, err := ListFiles(c.source)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range c.goFiles() {
+		for _, sqlFile := range files {
+			if sqlFile.Version == f.Version {
+				return nil, fmt.Errorf("version %d is used by both go migration and %s", f.Version, sqlFile.Up)
+			}
+		}
+
+		files = append(files, f)
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].Version < files[j].Version })
+
+	return files, nil
+}
+
+// runGo runs the Go migration in the direction.
+func (c *Client) runGo(ctx context.Context, version uint, direction source.Direction) error {
+	m, ok := c.goMigrations[version]
+	if !ok {
+		return fmt.Errorf("go migration %d is not registered", version)
+	}
+
+	if direction == source.Down {
+		if m.Down == nil {
+			return fmt.Errorf("go migration %d does not have down function", version)
+		}
+
+		return c.inTx(ctx, m.Down)
+	}
+
+	if m.Up != nil {
+		return c.inTx(ctx, m.Up)
+	}
+
+	return c.runBatches(ctx, m)
+}
+
+func (c *Client) runBatches(ctx context.Context, m GoMigration) error {
+	var (
+		limit = m.BatchSize
+		total int64
+		start = time.Now()
+	)
+
+	if limit <= 0 {
+		limit = defaultBatchSize
+	}
+
+	for batch := 1; ; batch++ {
+		var n int64
+
+		err := c.inTx(ctx, func(ctx context.Context, tx *sql.Tx) error {
+			var err error
+
+			n, err = m.UpBatches(ctx, tx, limit)
+
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("batch %d of go migration %d, %w", batch, m.Version, err)
+		}
+
+		total += n
+
+		slog.Info("migration batch is applied",
+			slog.Uint64("version", uint64(m.Version)),
+			slog.String("name", m.Name),
+			slog.Int("batch", batch),
+			slog.Int64("rows", n),
+			slog.Int64("total", total),
+			slog.Duration("elapsed", time.Since(start)),
+		)
+
+		if n < int64(limit) {
+			return nil
+		}
+	}
+}
+
+// inTx runs fn in the transaction. If the schema is set, the transaction
+// uses it as search_path.
+func (c *Client) inTx(ctx context.Context, fn Func) error {
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if c.schema != "" {
+		_, err = tx.ExecContext(ctx, "SELECT set_config('search_path', $1, true)", quoteIdent(c.schema))
+		if err != nil {
+			return errors.Join(err, tx.Rollback())
+		}
+	}
+
+	if err = fn(ctx, tx); err != nil {
+		return errors.Join(err, tx.Rollback())
+	}
+
+	return tx.Commit()
+}
+
+// goSource merges versions of the source with the Go migrations. Body of the
+// Go migration is the marker with the version and the direction.
+type goSource struct {
+	source.Driver
+	client   *Client
+	versions []uint
+}
+
+func newGoSource(driver source.Driver, c *Client) (*goSource, error) {
+	files, err := c.files()
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]uint, 0, len(files))
+
+	for _, f := range files {
+		versions = append(versions, f.Version)
+	}
+
+	return &goSource{Driver: driver, client: c, versions: versions}, nil
+}
+
+func (s *goSource) First() (uint, error) {
+	if len(s.versions) == 0 {
+		return 0, os.ErrNotExist
+	}
+
+	return s.versions[0], nil
+}
+
+func (s *goSource) Prev(version uint) (uint, error) {
+	i := sort.Search(len(s.versions), func(i int) bool { return s.versions[i] >= version })
+	if i == 0 || i > len(s.versions) {
+		return 0, os.ErrNotExist
+	}
+
+	return s.versions[i-1], nil
+}
+
+func (s *goSource) Next(version uint) (uint, error) {
+	i := sort.Search(len(s.versions), func(i int) bool { return s.versions[i] > version })
+	if i == len(s.versions) {
+		return 0, os.ErrNotExist
+	}
+
+	return s.versions[i], nil
+}
+
+func (s *goSource) ReadUp(version uint) (io.ReadCloser, string, error) {
+	m, ok := s.client.goMigrations[version]
+	if !ok {
+		return s.Driver.ReadUp(version)
+	}
+
+	return goBody(version, source.Up), m.Name, nil
+}
+
+func (s *goSource) ReadDown(version uint) (io.ReadCloser, string, error) {
+	m, ok := s.client.goMigrations[version]
+	if !ok {
+		return s.Driver.ReadDown(version)
+	}
+
+	if m.Down == nil {
+		return nil, "", os.ErrNotExist
+	}
+
+	return goBody(version, source.Down), m.Name, nil
+}
+
+func goBody(version uint, direction source.Direction) io.ReadCloser {
+	return io.NopCloser(strings.NewReader(fmt.Sprintf("%s%d:%s", goMarker, version, direction)))
+}
+
+// goDatabase runs the Go migrations by the marker, other migrations are
+// executed by the underlying driver.
+type goDatabase struct {
+	database.Driver
+	client *Client
+}
+
+func (d *goDatabase) Run(migration io.Reader) error {
+	body, err := io.ReadAll(migration)
+	if err != nil {
+		return err
+	}
+
+	version, direction, ok := parseGoBody(body)
+	if !ok {
+		return d.Driver.Run(bytes.NewReader(body))
+	}
+
+	ctx := d.client.runCtx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	return d.client.runGo(ctx, version, direction)
+}
+
+func parseGoBody(body []byte) (uint, source.Direction, bool) {
+	rest, ok := bytes.CutPrefix(body, []byte(goMarker))
+	if !ok {
+		return 0, "", false
+	}
+
+	version, direction, ok := strings.Cut(string(rest), ":")
+	if !ok {
+		return 0, "", false
+	}
+
+	v, err := strconv.ParseUint(version, 10, 64)
+	if err != nil {
+		return 0, "", false
+	}
+
+	return uint(v), source.Direction(direction), true
+}
@@ -0,0 +1,141 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"os"
+	"testing"
+	"testing/fstest"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func noop(context.Context, *sql.Tx) error { return nil }
+
+func TestGoSource_Should_merge_go_migrations_with_files(t *testing.T) {
+	filesystem := fstest.MapFS{
+		"1_create_orders.up.sql":   {Data: []byte("CREATE TABLE orders (id int);")},
+		"1_create_orders.down.sql": {Data: []byte("DROP TABLE orders;")},
+		"3_add_index.up.sql":       {Data: []byte("CREATE INDEX ON orders (id);")},
+	}
+
+	c := &Client{source: filesystem}
+
+	require.NoError(t, c.Register(
+		GoMigration{Version: 2, Name: "backfill_orders", Up: noop, Down: noop},
+		GoMigration{Version: 4, Name: "backfill_totals", UpBatches: func(context.Context, *sql.Tx, int) (int64, error) {
+			return 0, nil
+		}},
+	))
+
+	input, err := iofs.New(filesystem, ".")
+	require.NoError(t, err)
+
+	s, err := newGoSource(input, c)
+	require.NoError(t, err)
+
+	var versions []uint
+
+	for v, err := s.First(); err == nil; v, err = s.Next(v) {
+		versions = append(versions, v)
+	}
+
+	assert.Equal(t, []uint{1, 2, 3, 4}, versions)
+
+	prev, err := s.Prev(3)
+	require.NoError(t, err)
+	assert.Equal(t, uint(2), prev)
+
+	_, err = s.Prev(1)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	r, name, err := s.ReadUp(2)
+	require.NoError(t, err)
+	assert.Equal(t, "backfill_orders", name)
+
+	body, err := io.ReadAll(r)
+	require.NoError(t, err)
+
+	version, direction, ok := parseGoBody(body)
+	assert.True(t, ok)
+	assert.Equal(t, uint(2), version)
+	assert.Equal(t, source.Up, direction)
+
+	_, _, err = s.ReadDown(4)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	r, _, err = s.ReadUp(1)
+	require.NoError(t, err)
+
+	body, err = io.ReadAll(r)
+	require.NoError(t, err)
+
+	_, _, ok = parseGoBody(body)
+	assert.False(t, ok)
+}
+
+func TestRegister_Should_validate_migrations(t *testing.T) {
+	c := New()
+
+	assert.Error(t, c.Register(GoMigration{Version: 1}))
+	assert.Error(t, c.Register(GoMigration{Version: 1, Up: noop, UpBatches: func(context.Context, *sql.Tx, int) (int64, error) {
+		return 0, nil
+	}}))
+
+	require.NoError(t, c.Register(GoMigration{Version: 1, Up: noop}))
+	assert.Error(t, c.Register(GoMigration{Version: 1, Up: noop}))
+
+	c.source = fstest.MapFS{"1_create_orders.up.sql": {}}
+
+	_, err := c.files()
+	assert.Error(t, err)
+}
+
+func TestRunBatches_Should_run_each_batch_in_own_transaction_until_batch_is_not_full(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	c := &Client{db: db}
+
+	for _, rows := range []int64{2, 2, 1} {
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE orders").WithArgs(2).WillReturnResult(sqlmock.NewResult(0, rows))
+		mock.ExpectCommit()
+	}
+
+	err = c.runBatches(context.Background(), GoMigration{
+		Version:   1,
+		BatchSize: 2,
+		UpBatches: func(ctx context.Context, tx *sql.Tx, limit int) (int64, error) {
+			res, err := tx.ExecContext(ctx, "UPDATE orders SET total = 0 WHERE total IS NULL LIMIT $1", limit)
+			if err != nil {
+				return 0, err
+			}
+
+			return res.RowsAffected()
+		},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGoDatabase_Should_run_go_migration_with_context_of_caller(t *testing.T) {
+	db, _, err := sqlmock.New()
+	require.NoError(t, err)
+
+	c := &Client{db: db}
+	require.NoError(t, c.Register(GoMigration{Version: 1, Up: noop}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	c.runCtx = ctx
+
+	err = (&goDatabase{client: c}).Run(goBody(1, source.Up))
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,127 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"time"
+)
+
+const lockRetryInterval = 500 * time.Millisecond
+
+var ErrLockTimeout = errors.New("timeout while waiting for migration lock")
+
+// UpLocked applies all pending migrations under the Postgres advisory lock.
+// Use it when several replicas of the application start at the same time.
+// Only one replica applies migrations, the others wait until the lock is
+// released (but not longer than wait) and check that the database has the
+// latest version before serving. Startup fails with ErrChecksumMismatch if
+// an applied migration file was edited.
+func (c *Client) UpLocked(ctx context.Context, wait time.Duration) error {
+	if c.migrator == nil {
+		return ErrNotSetup
+	}
+
+	conn, err := c.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer conn.Close()
+
+	key := lockKey(c.qualifiedTable())
+
+	if err = acquireLock(ctx, conn, key, wait); err != nil {
+		return err
+	}
+
+	defer func() {
+		// lock is released with the session anyway, so error can be skipped.
+		_, _ = conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", key)
+	}()
+
+	if err = c.VerifyChecksums(ctx); err != nil {
+		return err
+	}
+
+	if err = c.Up(ctx); err != nil {
+		return err
+	}
+
+	if err = c.checkLatest(); err != nil {
+		return err
+	}
+
+	return c.SyncChecksums(ctx)
+}
+
+// checkLatest returns error if the database does not have the version of
+// the latest migration file.
+func (c *Client) checkLatest() error {
+	files, err := c.files()
+	if err != nil || len(files) == 0 {
+		return err
+	}
+
+	version, dirty, err := c.Version()
+	if err != nil {
+		return err
+	}
+
+	latest := files[len(files)-1].Version
+
+	if dirty || version != latest {
+		return fmt.Errorf("database is not migrated to %d, current version %d, dirty %t", latest, version, dirty)
+	}
+
+	return nil
+}
+
+// acquireLock tries to get the session level advisory lock until it is
+// acquired or wait is expired.
+func acquireLock(ctx context.Context, conn *sql.Conn, key int64, wait time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, wait)
+	defer cancel()
+
+	ticker := time.NewTicker(lockRetryInterval)
+	defer ticker.Stop()
+
+	for {
+		var locked bool
+
+		err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", key).Scan(&locked)
+		if errors.Is(err, context.DeadlineExceeded) {
+			return ErrLockTimeout
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if locked {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return ErrLockTimeout
+			}
+
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// lockKey makes the key of the advisory lock from the migration table name, so
+// the migrations with different tables do not block each other.
+func lockKey(table string) int64 {
+	h := fnv.New64a()
+
+	_, _ = h.Write([]byte("migration:" + table))
+
+	return int64(h.Sum64()) //nolint:gosec
+}
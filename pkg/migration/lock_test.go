@@ -0,0 +1,49 @@
+package migration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcquireLock_Should_retry_until_lock_is_acquired(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	key := lockKey(DefaultMigrationTable)
+
+	mock.ExpectQuery("SELECT pg_try_advisory_lock").WithArgs(key).
+		WillReturnRows(sqlmock.NewRows([]string{"locked"}).AddRow(false))
+	mock.ExpectQuery("SELECT pg_try_advisory_lock").WithArgs(key).
+		WillReturnRows(sqlmock.NewRows([]string{"locked"}).AddRow(true))
+
+	conn, err := db.Conn(context.Background())
+	require.NoError(t, err)
+
+	err = acquireLock(context.Background(), conn, key, time.Second)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAcquireLock_Should_return_timeout_error_if_lock_is_not_released(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	mock.ExpectQuery("SELECT pg_try_advisory_lock").
+		WillReturnRows(sqlmock.NewRows([]string{"locked"}).AddRow(false))
+
+	conn, err := db.Conn(context.Background())
+	require.NoError(t, err)
+
+	err = acquireLock(context.Background(), conn, 1, 100*time.Millisecond)
+	assert.ErrorIs(t, err, ErrLockTimeout)
+}
+
+func TestLockKey_Should_depend_on_migration_table(t *testing.T) {
+	assert.Equal(t, lockKey("schema_migrations"), lockKey("schema_migrations"))
+	assert.NotEqual(t, lockKey("schema_migrations"), lockKey("tenant_migrations"))
+}
@@ -0,0 +1,148 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+
+	"github.com/golang-migrate/migrate/v4/database"
+	"github.com/golang-migrate/migrate/v4/source"
+)
+
+var ErrNotDirty = errors.New("database is not in dirty state")
+
+// RecoverStrategy describes how the dirty state of the database is resolved.
+type RecoverStrategy uint8
+
+const (
+	// RecoverForce marks the dirty migration as applied. Use it when the
+	// changes of the failed migration were applied manually.
+	RecoverForce RecoverStrategy = iota + 1
+	// RecoverPrevious marks the previous migration as current, so the failed
+	// migration is applied again by the next Up(). Use it when the failed
+	// migration did not change anything.
+	RecoverPrevious
+	// RecoverDown runs the down file of the failed migration in a transaction
+	// and marks the previous migration as current.
+	RecoverDown
+)
+
+func (s RecoverStrategy) String() string {
+	switch s {
+	case RecoverForce:
+		return "force"
+	case RecoverPrevious:
+		return "previous"
+	case RecoverDown:
+		return "down"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseRecoverStrategy returns the strategy by its name.
+func ParseRecoverStrategy(name string) (RecoverStrategy, error) {
+	for _, s := range []RecoverStrategy{RecoverForce, RecoverPrevious, RecoverDown} {
+		if strings.EqualFold(s.String(), name) {
+			return s, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown recover strategy %q", name)
+}
+
+// DirtyState describes the failed migration.
+type DirtyState struct {
+	// Version of the failed migration.
+	Version uint
+	Dirty   bool
+	// Previous is the version of the migration before the failed one, -1 if
+	// the failed migration is the first one.
+	Previous int
+	// File of the failed migration, empty if the file is not found in the source.
+	File File
+}
+
+// DirtyState returns information about the failed migration. Dirty is false
+// if the last migration was applied successfully.
+func (c *Client) DirtyState() (DirtyState, error) {
+	version, dirty, err := c.Version()
+	if err != nil {
+		return DirtyState{}, err
+	}
+
+	state := DirtyState{Version: version, Dirty: dirty, Previous: database.NilVersion}
+
+	files, err := c.files()
+	if err != nil {
+		return DirtyState{}, err
+	}
+
+	for _, f := range files {
+		if f.Version == version {
+			state.File = f
+		}
+
+		if f.Version < version {
+			state.Previous = int(f.Version) //nolint:gosec
+		}
+	}
+
+	return state, nil
+}
+
+// Recover resolves the dirty state of the database with the strategy.
+// ErrNotDirty is returned if the database is not in dirty state.
+func (c *Client) Recover(ctx context.Context, strategy RecoverStrategy) error {
+	state, err := c.DirtyState()
+	if err != nil {
+		return err
+	}
+
+	if !state.Dirty {
+		return ErrNotDirty
+	}
+
+	switch strategy {
+	case RecoverForce:
+		return c.Force(int(state.Version)) //nolint:gosec
+	case RecoverPrevious:
+		return c.Force(state.Previous)
+	case RecoverDown:
+		if err = c.runDown(ctx, state.File); err != nil {
+			return err
+		}
+
+		return c.Force(state.Previous)
+	default:
+		return fmt.Errorf("unknown recover strategy %d", strategy)
+	}
+}
+
+// runDown executes the down file in the transaction, so the database is not
+// changed if the file fails too.
+func (c *Client) runDown(ctx context.Context, f File) error {
+	if f.Go {
+		return c.runGo(ctx, f.Version, source.Down)
+	}
+
+	if f.Down == "" {
+		return fmt.Errorf("down file of migration %d is not found", f.Version)
+	}
+
+	content, err := fs.ReadFile(c.source, f.Down)
+	if err != nil {
+		return err
+	}
+
+	return c.inTx(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, string(content)); err != nil {
+			return fmt.Errorf("can not run %s, %w", f.Down, err)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,175 @@
+package migration
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang-migrate/migrate/v4/source"
+)
+
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// File is a migration from the source. Up and Down contain file names of
+// the migration, they are empty for Go migrations.
+type File struct {
+	Version uint
+	Name    string
+	Up      string
+	Down    string
+	// Go is true if the migration is registered by Client.Register.
+	Go bool
+}
+
+// Status of the migration file in the database.
+type Status struct {
+	File
+	Applied bool
+	// Dirty is true if the migration is current and was failed.
+	Dirty bool
+}
+
+// Status returns all the migrations from the source with information about
+// whether each of them is applied or pending.
+func (c *Client) Status() ([]Status, error) {
+	if c.migrator == nil {
+		return nil, ErrNotSetup
+	}
+
+	files, err := c.files()
+	if err != nil {
+		return nil, err
+	}
+
+	version, dirty, err := c.Version()
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]Status, 0, len(files))
+
+	for _, f := range files {
+		res = append(res, Status{
+			File:    f,
+			Applied: f.Version <= version,
+			Dirty:   dirty && f.Version == version,
+		})
+	}
+
+	return res, nil
+}
+
+// ListFiles returns migrations from the root of the filesystem sorted by version.
+func ListFiles(filesystem fs.FS) ([]File, error) {
+	entries, err := fs.ReadDir(filesystem, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[uint]*File)
+
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+
+		m, err := source.Parse(e.Name())
+		if err != nil {
+			continue
+		}
+
+		f, ok := byVersion[m.Version]
+		if !ok {
+			f = &File{Version: m.Version, Name: m.Identifier}
+			byVersion[m.Version] = f
+		}
+
+		switch m.Direction {
+		case source.Up:
+			f.Up = m.Raw
+		case source.Down:
+			f.Down = m.Raw
+		}
+	}
+
+	res := make([]File, 0, len(byVersion))
+
+	for _, f := range byVersion {
+		res = append(res, *f)
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].Version < res[j].Version })
+
+	return res, nil
+}
+
+// Create creates the pair of empty up and down migration files inside dir.
+// The version of the migration is the unix timestamp of now. The error
+// wrapping os.ErrExist is returned if the version is used by another
+// migration, for example created in the same second.
+//
+// Example:
+//
+//	Create("db/migrations", "Create orders", time.Now())
+//
+//	db/migrations/1692290155_create_orders.up.sql
+//	db/migrations/1692290155_create_orders.down.sql
+func Create(dir, name string, now time.Time) (File, error) {
+	name = strings.Trim(invalidNameChars.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if name == "" {
+		return File{}, errors.New("migration name is empty")
+	}
+
+	version := uint(now.Unix()) //nolint:gosec
+
+	f := File{
+		Version: version,
+		Name:    name,
+		Up:      fmt.Sprintf("%d_%s.%s.sql", version, name, source.Up),
+		Down:    fmt.Sprintf("%d_%s.%s.sql", version, name, source.Down),
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return File{}, err
+	}
+
+	files, err := ListFiles(os.DirFS(dir))
+	if err != nil {
+		return File{}, err
+	}
+
+	for _, existing := range files {
+		if existing.Version == version {
+			return File{}, fmt.Errorf("%w: version %d is used by %s", os.ErrExist, version, existing.Name)
+		}
+	}
+
+	for i, file := range []string{f.Up, f.Down} {
+		if err = createFile(filepath.Join(dir, file)); err != nil {
+			// the up file is removed, so the migration is not left half created.
+			if i > 0 {
+				_ = os.Remove(filepath.Join(dir, f.Up))
+			}
+
+			return File{}, err
+		}
+	}
+
+	return f, nil
+}
+
+func createFile(path string) error {
+	// O_EXCL protects already existing migrations from overwriting.
+	out, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644) //nolint:gosec
+	if err != nil {
+		return err
+	}
+
+	return out.Close()
+}
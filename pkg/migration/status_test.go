@@ -0,0 +1,94 @@
+package migration
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListFiles_Should_group_up_and_down_files_by_version(t *testing.T) {
+	filesystem := fstest.MapFS{
+		"2_add_index.up.sql":        {},
+		"1_create_orders.up.sql":    {},
+		"1_create_orders.down.sql":  {},
+		"README.md":                 {},
+		"nested/3_ignored.up.sql":   {},
+		"10_add_column.up.sql":      {},
+		"10_add_column.down.sql":    {},
+		"invalid_name.up.sql":       {},
+		"2_add_index.down.sql":      {},
+		"not_a_migration.down.json": {},
+	}
+
+	files, err := ListFiles(filesystem)
+	require.NoError(t, err)
+	assert.Equal(t, []File{
+		{Version: 1, Name: "create_orders", Up: "1_create_orders.up.sql", Down: "1_create_orders.down.sql"},
+		{Version: 2, Name: "add_index", Up: "2_add_index.up.sql", Down: "2_add_index.down.sql"},
+		{Version: 10, Name: "add_column", Up: "10_add_column.up.sql", Down: "10_add_column.down.sql"},
+	}, files)
+}
+
+func TestCreate_Should_create_pair_of_migration_files(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "migrations")
+	now := time.Unix(1692290155, 0)
+
+	f, err := Create(dir, " Create Orders-table ", now)
+	require.NoError(t, err)
+	assert.Equal(t, File{
+		Version: 1692290155,
+		Name:    "create_orders_table",
+		Up:      "1692290155_create_orders_table.up.sql",
+		Down:    "1692290155_create_orders_table.down.sql",
+	}, f)
+
+	for _, name := range []string{f.Up, f.Down} {
+		_, err = os.Stat(filepath.Join(dir, name))
+		assert.NoError(t, err)
+	}
+
+	_, err = Create(dir, "create orders table", now)
+	assert.ErrorIs(t, err, os.ErrExist)
+}
+
+func TestCreate_Should_return_error_if_version_is_used(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Unix(1692290155, 0)
+
+	_, err := Create(dir, "create orders", now)
+	require.NoError(t, err)
+
+	_, err = Create(dir, "create clients", now)
+	assert.ErrorIs(t, err, os.ErrExist)
+
+	// the down file can not be created, the up file is not left.
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "1692290156_create_clients.down.sql"), 0o755))
+
+	_, err = Create(dir, "create clients", now.Add(time.Second))
+	assert.ErrorIs(t, err, os.ErrExist)
+
+	_, err = os.Stat(filepath.Join(dir, "1692290156_create_clients.up.sql"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestCreate_Should_return_error_if_name_is_empty(t *testing.T) {
+	_, err := Create(t.TempDir(), " - ", time.Now())
+	assert.Error(t, err)
+}
+
+func TestClient_Should_return_error_if_not_setup(t *testing.T) {
+	c := New()
+
+	assert.ErrorIs(t, c.Up(context.Background()), ErrNotSetup)
+	assert.ErrorIs(t, c.Steps(context.Background(), 1), ErrNotSetup)
+	assert.ErrorIs(t, c.Force(1), ErrNotSetup)
+
+	_, err := c.Status()
+	assert.ErrorIs(t, err, ErrNotSetup)
+}
@@ -0,0 +1,256 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/semaphore"
+)
+
+const (
+	defaultParallelism       = 4
+	defaultTenantLockTimeout = time.Minute
+)
+
+// SchemaSource returns schemas of the tenants.
+type SchemaSource func(ctx context.Context) ([]string, error)
+
+// Schemas returns the static list of schemas.
+func Schemas(names ...string) SchemaSource {
+	return func(context.Context) ([]string, error) {
+		return names, nil
+	}
+}
+
+// SchemasLike discovers existing schemas by the LIKE pattern, for example
+// "tenant_%".
+func SchemasLike(db *sql.DB, pattern string) SchemaSource {
+	return func(ctx context.Context) ([]string, error) {
+		rows, err := db.QueryContext(ctx, "SELECT nspname FROM pg_namespace WHERE nspname LIKE $1 ORDER BY nspname", pattern)
+		if err != nil {
+			return nil, err
+		}
+
+		defer rows.Close()
+
+		var res []string
+
+		for rows.Next() {
+			var name string
+
+			if err = rows.Scan(&name); err != nil {
+				return nil, err
+			}
+
+			res = append(res, name)
+		}
+
+		return res, rows.Err()
+	}
+}
+
+// TenantConfig describes migrations of the tenant schemas.
+type TenantConfig struct {
+	DB *sql.DB
+	// Source contains migration files applied to each schema.
+	Source fs.FS
+	// GoMigrations applied to each schema, optional.
+	GoMigrations []GoMigration
+	// Schemas returns the list of tenant schemas.
+	Schemas SchemaSource
+	// Table is the migration table created inside each schema.
+	//
+	// Default: DefaultMigrationTable.
+	Table string
+	// Parallelism is the number of schemas migrated at the same time. Each
+	// migrated schema uses up to three connections of the pool.
+	//
+	// Default: 4.
+	Parallelism int
+	// LockTimeout is the time to wait for the migration lock of the schema,
+	// see Client.UpLocked.
+	//
+	// Default: 1 minute.
+	LockTimeout time.Duration
+}
+
+// TenantStatus is the result of migrations of the schema.
+type TenantStatus struct {
+	Schema  string
+	Version uint
+	Dirty   bool
+	// Initialized is false if the schema or its migration table does not
+	// exist yet.
+	Initialized bool
+	// Err is not nil if the schema is failed.
+	Err error
+}
+
+// Tenants applies the same migrations to many tenant schemas. Each schema
+// has its own migration table, so the schemas are migrated independently.
+//
+// Example:
+//
+//	tenants := migration.NewTenants(migration.TenantConfig{
+//		DB:      conn.DB,
+//		Source:  db.Migrations(),
+//		Schemas: migration.SchemasLike(conn.DB, "tenant_%"),
+//	})
+//
+//	statuses, err := tenants.Up(ctx)
+type Tenants struct {
+	cfg TenantConfig
+}
+
+func NewTenants(cfg TenantConfig) *Tenants {
+	if cfg.Table == "" {
+		cfg.Table = DefaultMigrationTable
+	}
+
+	if cfg.Parallelism <= 0 {
+		cfg.Parallelism = defaultParallelism
+	}
+
+	if cfg.LockTimeout <= 0 {
+		cfg.LockTimeout = defaultTenantLockTimeout
+	}
+
+	return &Tenants{cfg: cfg}
+}
+
+// Up creates missing schemas and applies pending migrations to each of them.
+// Failure of one schema does not stop the others, the error contains all
+// the failed schemas.
+func (t *Tenants) Up(ctx context.Context) ([]TenantStatus, error) {
+	return t.each(ctx, func(ctx context.Context, schema string) TenantStatus {
+		return t.migrate(ctx, schema, func(ctx context.Context, c *Client) error {
+			return c.UpLocked(ctx, t.cfg.LockTimeout)
+		})
+	})
+}
+
+// Status returns the version of each schema. It only reads the migration
+// tables, missing schemas and tables are reported as not initialized.
+func (t *Tenants) Status(ctx context.Context) ([]TenantStatus, error) {
+	return t.each(ctx, t.status)
+}
+
+func (t *Tenants) each(ctx context.Context, fn func(ctx context.Context, schema string) TenantStatus) ([]TenantStatus, error) {
+	schemas, err := t.cfg.Schemas(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("can not get schemas, %w", err)
+	}
+
+	var (
+		sem = semaphore.NewWeighted(int64(t.cfg.Parallelism))
+		res = make([]TenantStatus, len(schemas))
+		wg  sync.WaitGroup
+	)
+
+	for i, schema := range schemas {
+		if err = sem.Acquire(ctx, 1); err != nil {
+			res[i] = TenantStatus{Schema: schema, Err: err}
+
+			continue
+		}
+
+		wg.Add(1)
+
+		go func(i int, schema string) {
+			defer wg.Done()
+			defer sem.Release(1)
+
+			res[i] = fn(ctx, schema)
+		}(i, schema)
+	}
+
+	wg.Wait()
+
+	sort.Slice(res, func(i, j int) bool { return res[i].Schema < res[j].Schema })
+
+	var errs []error
+
+	for _, s := range res {
+		if s.Err != nil {
+			errs = append(errs, fmt.Errorf("schema %s: %w", s.Schema, s.Err))
+		}
+	}
+
+	return res, errors.Join(errs...)
+}
+
+// status reads the version from the migration table of the schema without
+// the setup, so nothing is created.
+func (t *Tenants) status(ctx context.Context, schema string) TenantStatus {
+	status := TenantStatus{Schema: schema}
+	table := quoteIdent(schema) + "." + quoteIdent(t.cfg.Table)
+
+	// to_regclass returns NULL if the schema or the table does not exist.
+	var exists bool
+
+	status.Err = t.cfg.DB.QueryRowContext(ctx, "SELECT to_regclass($1) IS NOT NULL", table).Scan(&exists)
+	if status.Err != nil || !exists {
+		return status
+	}
+
+	status.Initialized = true
+
+	var version int64
+
+	err := t.cfg.DB.QueryRowContext(ctx, "SELECT version, dirty FROM "+table+" LIMIT 1").Scan(&version, &status.Dirty)
+	if errors.Is(err, sql.ErrNoRows) {
+		return status
+	}
+
+	if err != nil {
+		status.Err = err
+
+		return status
+	}
+
+	// the negative version is the state without migrations, see Client.Force.
+	if version > 0 {
+		status.Version = uint(version)
+	}
+
+	return status
+}
+
+func (t *Tenants) migrate(
+	ctx context.Context,
+	schema string,
+	fn func(ctx context.Context, c *Client) error,
+) (status TenantStatus) {
+	status.Schema = schema
+
+	c := New()
+
+	if status.Err = c.Register(t.cfg.GoMigrations...); status.Err != nil {
+		return status
+	}
+
+	if status.Err = c.SetupSchema(ctx, t.cfg.DB, t.cfg.Source, schema, t.cfg.Table); status.Err != nil {
+		return status
+	}
+
+	status.Initialized = true
+
+	defer func() {
+		status.Err = errors.Join(status.Err, c.Close())
+	}()
+
+	fnErr := fn(ctx, c)
+
+	// version is reported even if the migrations are failed.
+	version, dirty, err := c.Version()
+
+	status.Version, status.Dirty, status.Err = version, dirty, errors.Join(fnErr, err)
+
+	return status
+}
@@ -0,0 +1,82 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemasLike_Should_return_schemas_by_pattern(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	mock.ExpectQuery("SELECT nspname FROM pg_namespace").WithArgs("tenant_%").
+		WillReturnRows(sqlmock.NewRows([]string{"nspname"}).AddRow("tenant_a").AddRow("tenant_b"))
+
+	schemas, err := SchemasLike(db, "tenant_%")(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tenant_a", "tenant_b"}, schemas)
+}
+
+func TestTenants_Should_report_status_of_each_schema(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	mock.MatchExpectationsInOrder(false)
+
+	for _, schema := range []string{"tenant_b", "tenant_a"} {
+		mock.ExpectExec(`CREATE SCHEMA IF NOT EXISTS "` + schema + `"`).
+			WillReturnError(errors.New("permission denied"))
+	}
+
+	tenants := NewTenants(TenantConfig{
+		DB:          db,
+		Source:      fstest.MapFS{},
+		Schemas:     Schemas("tenant_b", "tenant_a"),
+		Parallelism: 1,
+	})
+
+	statuses, err := tenants.Up(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "schema tenant_a: permission denied")
+	assert.Contains(t, err.Error(), "schema tenant_b: permission denied")
+
+	require.Len(t, statuses, 2)
+	assert.Equal(t, "tenant_a", statuses[0].Schema)
+	assert.Equal(t, "tenant_b", statuses[1].Schema)
+	assert.Error(t, statuses[0].Err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTenants_Status_Should_not_create_schemas(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	mock.MatchExpectationsInOrder(false)
+
+	mock.ExpectQuery(`SELECT to_regclass`).WithArgs(`"tenant_a"."schema_migrations"`).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectQuery(`SELECT version, dirty FROM "tenant_a"."schema_migrations"`).
+		WillReturnRows(sqlmock.NewRows([]string{"version", "dirty"}).AddRow(3, true))
+	mock.ExpectQuery(`SELECT to_regclass`).WithArgs(`"tenant_b"."schema_migrations"`).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+	tenants := NewTenants(TenantConfig{
+		DB:      db,
+		Source:  fstest.MapFS{},
+		Schemas: Schemas("tenant_b", "tenant_a"),
+	})
+
+	statuses, err := tenants.Status(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []TenantStatus{
+		{Schema: "tenant_a", Version: 3, Dirty: true, Initialized: true},
+		{Schema: "tenant_b"},
+	}, statuses)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
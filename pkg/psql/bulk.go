@@ -0,0 +1,166 @@
+package psql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+// ErrNotPgx возвращается, если соединение открыто не драйвером pgx/stdlib.
+var ErrNotPgx = errors.New("connection is not pgx connection")
+
+// defaultBulkSize размер пачки строк BulkWriter по умолчанию.
+const defaultBulkSize = 10000
+
+// RawConn соединение database/sql с доступом к соединению драйвера, например
+// *sql.Conn или tx.Manager().Raw(ctx).
+type RawConn interface {
+	Raw(f func(driverConn any) error) error
+}
+
+// WithPgx вызывает fn с нативным соединением pgx. Соединение нельзя
+// использовать после возврата из fn.
+func WithPgx(conn RawConn, fn func(conn *pgx.Conn) error) error {
+	return conn.Raw(func(driverConn any) error {
+		c, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			return fmt.Errorf("%w: %T", ErrNotPgx, driverConn)
+		}
+
+		return fn(c.Conn())
+	})
+}
+
+// CopyFrom записывает строки в таблицу через протокол COPY. Таблица может
+// быть со схемой: "public.orders". Возвращает кол-во записанных строк.
+//
+// Example:
+//
+//	n, err := psql.CopyFrom(ctx, tx.Manager().Raw(ctx), "orders", []string{"id", "status"}, rows)
+func CopyFrom(ctx context.Context, conn RawConn, table string, columns []string, rows [][]any) (int64, error) {
+	return CopyFromSource(ctx, conn, table, columns, pgx.CopyFromRows(rows))
+}
+
+// CopyFromSource как CopyFrom, но строки читаются из источника, например
+// pgx.CopyFromFunc, чтобы не держать все строки в памяти.
+func CopyFromSource(
+	ctx context.Context,
+	conn RawConn,
+	table string,
+	columns []string,
+	src pgx.CopyFromSource,
+) (int64, error) {
+	var n int64
+
+	err := WithPgx(conn, func(c *pgx.Conn) error {
+		var err error
+
+		n, err = c.CopyFrom(ctx, pgx.Identifier(strings.Split(table, ".")), columns, src)
+
+		return err
+	})
+
+	return n, err
+}
+
+// BulkWriter копит строки и записывает их в таблицу пачками через COPY.
+// После последней строки нужно вызвать Flush.
+//
+// Example:
+//
+//	w := psql.NewBulkWriter(tx.Manager().Raw(ctx), "orders", "id", "status")
+//
+//	for _, o := range orders {
+//		if err := w.Add(ctx, o.ID, o.Status); err != nil {
+//			return err
+//		}
+//	}
+//
+//	return w.Flush(ctx)
+type BulkWriter struct {
+	conn    RawConn
+	table   string
+	columns []string
+	size    int
+	rows    [][]any
+	written int64
+}
+
+// NewBulkWriter создает BulkWriter в таблицу с колонками.
+func NewBulkWriter(conn RawConn, table string, columns ...string) *BulkWriter {
+	return &BulkWriter{
+		conn:    conn,
+		table:   table,
+		columns: columns,
+		size:    defaultBulkSize,
+	}
+}
+
+// WithSize задает размер пачки.
+//
+// Default: defaultBulkSize.
+func (w *BulkWriter) WithSize(size int) *BulkWriter {
+	if size > 0 {
+		w.size = size
+	}
+
+	return w
+}
+
+// Add добавляет строку, значения в порядке колонок. Когда накоплена пачка,
+// она записывается в таблицу.
+func (w *BulkWriter) Add(ctx context.Context, values ...any) error {
+	if len(values) != len(w.columns) {
+		return fmt.Errorf("row has %d values, expected %d", len(values), len(w.columns))
+	}
+
+	w.rows = append(w.rows, values)
+
+	if len(w.rows) < w.size {
+		return nil
+	}
+
+	return w.Flush(ctx)
+}
+
+// Flush записывает накопленные строки.
+func (w *BulkWriter) Flush(ctx context.Context) error {
+	if len(w.rows) == 0 {
+		return nil
+	}
+
+	n, err := CopyFrom(ctx, w.conn, w.table, w.columns, w.rows)
+	if err != nil {
+		return err
+	}
+
+	w.written += n
+	w.rows = w.rows[:0]
+
+	return nil
+}
+
+// Written возвращает кол-во записанных строк.
+func (w *BulkWriter) Written() int64 {
+	return w.written
+}
+
+// SendBatch отправляет все запросы пачки за один round trip и читает их
+// результаты. Возвращает первую ошибку запроса пачки.
+//
+// Example:
+//
+//	batch := &pgx.Batch{}
+//	batch.Queue("UPDATE orders SET status = $1 WHERE id = $2", "paid", 1)
+//	batch.Queue("UPDATE orders SET status = $1 WHERE id = $2", "paid", 2)
+//
+//	err := psql.SendBatch(ctx, tx.Manager().Raw(ctx), batch)
+func SendBatch(ctx context.Context, conn RawConn, batch *pgx.Batch) error {
+	return WithPgx(conn, func(c *pgx.Conn) error {
+		return c.SendBatch(ctx, batch).Close()
+	})
+}
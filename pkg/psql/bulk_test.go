@@ -0,0 +1,45 @@
+package psql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithPgx_Should_return_error_for_other_drivers(t *testing.T) {
+	db, _, err := sqlmock.New()
+	require.NoError(t, err)
+
+	conn, err := db.Conn(context.Background())
+	require.NoError(t, err)
+
+	defer conn.Close()
+
+	err = WithPgx(conn, func(*pgx.Conn) error { return nil })
+	assert.ErrorIs(t, err, ErrNotPgx)
+
+	err = SendBatch(context.Background(), conn, &pgx.Batch{})
+	assert.ErrorIs(t, err, ErrNotPgx)
+}
+
+func TestBulkWriter_Should_flush_full_batches(t *testing.T) {
+	db, _, err := sqlmock.New()
+	require.NoError(t, err)
+
+	conn, err := db.Conn(context.Background())
+	require.NoError(t, err)
+
+	defer conn.Close()
+
+	w := NewBulkWriter(conn, "orders", "id", "status").WithSize(2)
+
+	assert.Error(t, w.Add(context.Background(), 1))
+	assert.NoError(t, w.Add(context.Background(), 1, "new"))
+	// the second row fills the batch, COPY is not supported by sqlmock.
+	assert.ErrorIs(t, w.Add(context.Background(), 2, "new"), ErrNotPgx)
+	assert.Equal(t, int64(0), w.Written())
+}
@@ -159,6 +159,14 @@ func mergeConfig(cfg1, cfg2 Config) Config {
 
 // Connect возвращает подключение к БД.
 func Connect(c Config) (*sqlx.DB, error) {
+	conn, _, err := ConnectPool(c)
+
+	return conn, err
+}
+
+// ConnectPool возвращает подключение к БД и пул pgx, поверх которого оно
+// открыто. Пул нужен для нативного API pgx, например Listener.
+func ConnectPool(c Config) (*sqlx.DB, *pgxpool.Pool, error) {
 	// Если нужна более тонкая конфигурация, то можно посмотреть тут, какие есть возможности.
 	// https://github.com/jackc/pgx/blob/master/conn.go#L22.
 	cfg := defaultConfig()
@@ -166,17 +174,17 @@ func Connect(c Config) (*sqlx.DB, error) {
 
 	conf, err := pgxpool.ParseConfig(cfg.URL())
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	conf.ConnConfig.RuntimeParams["search_path"] = c.Schema
 
 	pool, err := pgxpool.NewWithConfig(context.Background(), conf)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	nativeConn := stdlib.OpenDBFromPool(pool)
 
-	return sqlx.NewDb(nativeConn, "pgx"), nil
+	return sqlx.NewDb(nativeConn, "pgx"), pool, nil
 }
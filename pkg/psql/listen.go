@@ -0,0 +1,399 @@
+package psql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	defaultMinReconnectDelay = 100 * time.Millisecond
+	defaultMaxReconnectDelay = 30 * time.Second
+)
+
+// Handler обрабатывает уведомление канала. Обработчики вызываются
+// последовательно в горутине Listener, поэтому должны быть быстрыми.
+type Handler func(ctx context.Context, n *pgconn.Notification)
+
+type subscription struct {
+	handler Handler
+}
+
+// Listener получает уведомления LISTEN/NOTIFY через выделенное соединение
+// пула. При потере соединения Listener переподключается и заново подписывается
+// на все каналы. Уведомления, отправленные во время переподключения,
+// теряются, для этого случая есть OnReconnect.
+//
+// Example:
+//
+//	l := psql.NewListener(pool)
+//
+//	invalidate := psql.NewChannel[uuid.UUID]("orders_changed")
+//	invalidate.Subscribe(l, func(ctx context.Context, id uuid.UUID) {
+//		cache.Delete(id)
+//	})
+//
+//	go l.Listen(ctx)
+type Listener struct {
+	pool *pgxpool.Pool
+
+	minDelay    time.Duration
+	maxDelay    time.Duration
+	onReconnect func(ctx context.Context)
+
+	mu   sync.Mutex
+	subs map[string][]*subscription
+	// changed будит Listen после изменения подписок.
+	changed chan struct{}
+}
+
+// ListenerOption настройка Listener.
+type ListenerOption func(l *Listener)
+
+// WithReconnectDelay задает минимальную и максимальную задержку между
+// попытками переподключения, задержка растет экспоненциально.
+//
+// Default: defaultMinReconnectDelay, defaultMaxReconnectDelay.
+func WithReconnectDelay(minDelay, maxDelay time.Duration) ListenerOption {
+	return func(l *Listener) {
+		l.minDelay = minDelay
+		l.maxDelay = maxDelay
+	}
+}
+
+// OnReconnect задает функцию, которая вызывается после переподключения.
+// Например, чтобы сбросить кеш целиком, так как уведомления могли быть
+// потеряны.
+func OnReconnect(fn func(ctx context.Context)) ListenerOption {
+	return func(l *Listener) {
+		l.onReconnect = fn
+	}
+}
+
+// NewListener создает Listener поверх пула, см. ConnectPool.
+func NewListener(pool *pgxpool.Pool, opts ...ListenerOption) *Listener {
+	l := &Listener{
+		pool:     pool,
+		minDelay: defaultMinReconnectDelay,
+		maxDelay: defaultMaxReconnectDelay,
+		subs:     make(map[string][]*subscription),
+		changed:  make(chan struct{}, 1),
+	}
+
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	return l
+}
+
+// Subscribe подписывает обработчик на канал. Возвращает функцию отписки.
+// Подписываться можно до и после запуска Listen.
+func (l *Listener) Subscribe(channel string, h Handler) (unsubscribe func()) {
+	sub := &subscription{handler: h}
+
+	l.mu.Lock()
+	l.subs[channel] = append(l.subs[channel], sub)
+	l.mu.Unlock()
+
+	l.notifyChanged()
+
+	var once sync.Once
+
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+
+			subs := l.subs[channel]
+
+			for i, s := range subs {
+				if s == sub {
+					l.subs[channel] = append(subs[:i:i], subs[i+1:]...)
+
+					break
+				}
+			}
+
+			if len(l.subs[channel]) == 0 {
+				delete(l.subs, channel)
+			}
+
+			l.notifyChanged()
+		})
+	}
+}
+
+func (l *Listener) notifyChanged() {
+	select {
+	case l.changed <- struct{}{}:
+	default:
+	}
+}
+
+func (l *Listener) channels() map[string]struct{} {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	channels := make(map[string]struct{}, len(l.subs))
+
+	for ch := range l.subs {
+		channels[ch] = struct{}{}
+	}
+
+	return channels
+}
+
+func (l *Listener) dispatch(ctx context.Context, n *pgconn.Notification) {
+	l.mu.Lock()
+	subs := append([]*subscription(nil), l.subs[n.Channel]...)
+	l.mu.Unlock()
+
+	for _, s := range subs {
+		s.handler(ctx, n)
+	}
+}
+
+// Listen получает уведомления, пока контекст не отменен. Соединение
+// занимается только когда есть подписки. Подходит как воркер контейнера.
+func (l *Listener) Listen(ctx context.Context) error {
+	delay := l.minDelay
+	reconnect := false
+
+	for {
+		connected, err := l.listen(ctx, reconnect)
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		if connected {
+			reconnect = true
+			delay = l.minDelay
+		}
+
+		slog.Error("listener connection is lost",
+			slog.String("error", err.Error()),
+			slog.Duration("retry", delay),
+		)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(delay):
+		}
+
+		delay = min(delay*2, l.maxDelay)
+	}
+}
+
+// listen держит одно соединение до ошибки. connected сообщает, что
+// соединение было установлено, чтобы сбросить задержку.
+//
+//revive:disable:cognitive-complexity
+func (l *Listener) listen(ctx context.Context, reconnect bool) (connected bool, err error) {
+	for len(l.channels()) == 0 {
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case <-l.changed:
+		}
+	}
+
+	pc, err := l.pool.Acquire(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	// соединение забирается из пула, чтобы пул не закрыл его по таймауту.
+	conn := pc.Hijack()
+	defer conn.Close(context.Background())
+
+	listening := make(map[string]struct{})
+
+	if err = l.sync(ctx, conn, listening); err != nil {
+		return false, err
+	}
+
+	if reconnect && l.onReconnect != nil {
+		l.onReconnect(ctx)
+	}
+
+	for {
+		if err = l.sync(ctx, conn, listening); err != nil {
+			return true, err
+		}
+
+		var n *pgconn.Notification
+
+		if n, err = l.wait(ctx, conn); err != nil {
+			if ctx.Err() == nil && errors.Is(err, context.Canceled) && !conn.IsClosed() {
+				// подписки изменились.
+				continue
+			}
+
+			return true, err
+		}
+
+		l.dispatch(ctx, n)
+	}
+}
+
+//revive:enable:cognitive-complexity
+
+// wait ждет уведомление или изменение подписок.
+func (l *Listener) wait(ctx context.Context, conn *pgx.Conn) (*pgconn.Notification, error) {
+	waitCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go func() {
+		select {
+		case <-l.changed:
+			cancel()
+		case <-waitCtx.Done():
+		}
+	}()
+
+	return conn.WaitForNotification(waitCtx)
+}
+
+// sync выполняет LISTEN и UNLISTEN по разнице подписок и каналов
+// соединения.
+func (l *Listener) sync(ctx context.Context, conn *pgx.Conn, listening map[string]struct{}) error {
+	channels := l.channels()
+
+	for ch := range channels {
+		if _, ok := listening[ch]; ok {
+			continue
+		}
+
+		if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{ch}.Sanitize()); err != nil {
+			return err
+		}
+
+		listening[ch] = struct{}{}
+	}
+
+	for ch := range listening {
+		if _, ok := channels[ch]; ok {
+			continue
+		}
+
+		if _, err := conn.Exec(ctx, "UNLISTEN "+pgx.Identifier{ch}.Sanitize()); err != nil {
+			return err
+		}
+
+		delete(listening, ch)
+	}
+
+	return nil
+}
+
+// Execer выполняет запрос, например tx.Manager().Conn(ctx).
+type Execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// Notify отправляет уведомление в канал. Внутри транзакции уведомление
+// доставляется только после коммита, при откате не доставляется.
+//
+// Example:
+//
+//	err := psql.Notify(ctx, tx.Manager().Conn(ctx), "orders_changed", id.String())
+func Notify(ctx context.Context, conn Execer, channel, payload string) error {
+	_, err := conn.ExecContext(ctx, "SELECT pg_notify($1, $2)", channel, payload)
+
+	return err
+}
+
+// Channel канал уведомлений с payload типа T в формате JSON.
+type Channel[T any] struct {
+	Name string
+}
+
+// NewChannel создает типизированный канал.
+func NewChannel[T any](name string) Channel[T] {
+	return Channel[T]{Name: name}
+}
+
+// Notify отправляет payload в канал, см. Notify.
+func (c Channel[T]) Notify(ctx context.Context, conn Execer, payload T) error {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	return Notify(ctx, conn, c.Name, string(raw))
+}
+
+// Subscribe подписывает fn на канал. Уведомления, которые не удалось
+// декодировать, пропускаются с ошибкой в логе.
+func (c Channel[T]) Subscribe(l *Listener, fn func(ctx context.Context, payload T)) (unsubscribe func()) {
+	return l.Subscribe(c.Name, func(ctx context.Context, n *pgconn.Notification) {
+		payload, err := c.decode(n)
+		if err != nil {
+			slog.Error("can not decode notification",
+				slog.String("channel", n.Channel),
+				slog.String("error", err.Error()),
+			)
+
+			return
+		}
+
+		fn(ctx, payload)
+	})
+}
+
+// Chan подписывается на канал и доставляет уведомления в Go канал с буфером
+// size. Если буфер заполнен, Listener ждет чтения, поэтому канал нужно
+// читать до отписки. Канал закрывается после отписки.
+func (c Channel[T]) Chan(l *Listener, size int) (<-chan T, func()) {
+	var (
+		out  = make(chan T, size)
+		done = make(chan struct{})
+		mu   sync.RWMutex
+	)
+
+	unsubscribe := c.Subscribe(l, func(ctx context.Context, payload T) {
+		mu.RLock()
+		defer mu.RUnlock()
+
+		select {
+		case <-done:
+		case <-ctx.Done():
+		case out <- payload:
+		}
+	})
+
+	var once sync.Once
+
+	return out, func() {
+		once.Do(func() {
+			unsubscribe()
+			close(done)
+
+			// ждет отправку, которая могла начаться до отписки.
+			mu.Lock()
+			close(out)
+			mu.Unlock()
+		})
+	}
+}
+
+func (c Channel[T]) decode(n *pgconn.Notification) (T, error) {
+	var payload T
+
+	if err := json.Unmarshal([]byte(n.Payload), &payload); err != nil {
+		return payload, fmt.Errorf("channel %s: %w", c.Name, err)
+	}
+
+	return payload, nil
+}
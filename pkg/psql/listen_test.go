@@ -0,0 +1,82 @@
+package psql
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type orderChanged struct {
+	ID int `json:"id"`
+}
+
+func TestListener_Should_dispatch_notifications_to_subscribers_of_channel(t *testing.T) {
+	var (
+		ctx = context.Background()
+		l   = NewListener(nil)
+		got []string
+	)
+
+	unsubscribe := l.Subscribe("orders", func(_ context.Context, n *pgconn.Notification) {
+		got = append(got, "orders:"+n.Payload)
+	})
+	l.Subscribe("clients", func(_ context.Context, n *pgconn.Notification) {
+		got = append(got, "clients:"+n.Payload)
+	})
+
+	assert.Equal(t, map[string]struct{}{"orders": {}, "clients": {}}, l.channels())
+
+	l.dispatch(ctx, &pgconn.Notification{Channel: "orders", Payload: "1"})
+	unsubscribe()
+	unsubscribe()
+	l.dispatch(ctx, &pgconn.Notification{Channel: "orders", Payload: "2"})
+	l.dispatch(ctx, &pgconn.Notification{Channel: "clients", Payload: "3"})
+
+	assert.Equal(t, []string{"orders:1", "clients:3"}, got)
+	assert.Equal(t, map[string]struct{}{"clients": {}}, l.channels())
+}
+
+func TestChannel_Should_decode_payload(t *testing.T) {
+	var (
+		ctx = context.Background()
+		l   = NewListener(nil)
+		ch  = NewChannel[orderChanged]("orders")
+	)
+
+	out, unsubscribe := ch.Chan(l, 1)
+
+	l.dispatch(ctx, &pgconn.Notification{Channel: "orders", Payload: "broken"})
+	l.dispatch(ctx, &pgconn.Notification{Channel: "orders", Payload: `{"id":7}`})
+
+	assert.Equal(t, orderChanged{ID: 7}, <-out)
+
+	unsubscribe()
+
+	_, ok := <-out
+	assert.False(t, ok)
+}
+
+func TestChannel_Notify_Should_send_json_payload(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	mock.ExpectExec(`SELECT pg_notify\(\$1, \$2\)`).
+		WithArgs("orders", `{"id":7}`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = NewChannel[orderChanged]("orders").Notify(context.Background(), db, orderChanged{ID: 7})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListener_Listen_Should_not_connect_without_subscriptions(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	assert.NoError(t, NewListener(nil).Listen(ctx))
+}